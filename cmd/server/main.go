@@ -21,20 +21,48 @@ import (
 	"golang.org/x/crypto/bcrypt"
 
 	"db-dashboards/internal/config"
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/pkg/dbpool"
 	"db-dashboards/pkg/router"
 
+	adminhandler "db-dashboards/internal/handler/admin"
+	audithandler "db-dashboards/internal/handler/audit"
 	authhandler "db-dashboards/internal/handler/auth"
-	postgreshandler "db-dashboards/internal/handler/postgres"
+	connectionhandler "db-dashboards/internal/handler/connection"
+	dashboardhandler "db-dashboards/internal/handler/dashboard"
+	enginehandler "db-dashboards/internal/handler/engine"
+	historyhandler "db-dashboards/internal/handler/history"
+	savedqueryhandler "db-dashboards/internal/handler/savedquery"
 	userhandler "db-dashboards/internal/handler/user"
-
+	workspacehandler "db-dashboards/internal/handler/workspace"
+
+	auditrepo "db-dashboards/internal/repository/audit"
+	connectionrepo "db-dashboards/internal/repository/connection"
+	dashboardrepo "db-dashboards/internal/repository/dashboard"
+	enginerepo "db-dashboards/internal/repository/engine"
+	historyrepo "db-dashboards/internal/repository/history"
+	mysqlrepo "db-dashboards/internal/repository/mysql"
+	postgresrepo "db-dashboards/internal/repository/postgres"
+	refreshtokenrepo "db-dashboards/internal/repository/refreshtoken"
+	savedqueryrepo "db-dashboards/internal/repository/savedquery"
+	sqliterepo "db-dashboards/internal/repository/sqlite"
 	userrepo "db-dashboards/internal/repository/user"
+	workspacerepo "db-dashboards/internal/repository/workspace"
 
+	auditservice "db-dashboards/internal/service/audit"
 	authservice "db-dashboards/internal/service/auth"
-	postgreservice "db-dashboards/internal/service/postgres"
+	connectionservice "db-dashboards/internal/service/connection"
+	dashboardservice "db-dashboards/internal/service/dashboard"
+	engineservice "db-dashboards/internal/service/engine"
+	historyservice "db-dashboards/internal/service/history"
+	savedqueryservice "db-dashboards/internal/service/savedquery"
 	userservice "db-dashboards/internal/service/user"
+	workspaceservice "db-dashboards/internal/service/workspace"
 
 	middlewares "db-dashboards/internal/handler/middleware"
 
+	cryptoutils "db-dashboards/pkg/utils/crypto"
+
 	httpSwagger "github.com/swaggo/http-swagger"
 
 	_ "db-dashboards/docs"
@@ -51,6 +79,18 @@ func (h *Hasher) CompareHashAndPassword(hashedPassword []byte, password []byte)
 	return bcrypt.CompareHashAndPassword(hashedPassword, password)
 }
 
+type Cipher struct {
+	key []byte
+}
+
+func (c *Cipher) Encrypt(plaintext []byte) (string, error) {
+	return cryptoutils.Encrypt(plaintext, c.key)
+}
+
+func (c *Cipher) Decrypt(ciphertext string) ([]byte, error) {
+	return cryptoutils.Decrypt(ciphertext, c.key)
+}
+
 const (
 	configPath = "config/"
 )
@@ -85,6 +125,11 @@ func initConfig() (*config.Config, error) {
 		return nil, errors.New("CHAT_JWT_SECRET env variable not set")
 	}
 
+	conf.Encryption.Key = viper.GetString("ENCRYPTION_KEY")
+	if conf.Encryption.Key == "" {
+		return nil, errors.New("DB_DASHBOARDS_ENCRYPTION_KEY env variable not set")
+	}
+
 	return &conf, nil
 }
 
@@ -168,22 +213,81 @@ func main() {
 	}
 
 	userRepo := userrepo.New(db)
-
-	userService := userservice.New(userRepo, &Hasher{})
-	authService := authservice.New(userRepo, &Hasher{})
-	postgresService := postgreservice.New()
-
-	authMiddleware := middlewares.JWTAuthMiddleware(conf.Jwt.Secret, logger)
-
-	authHandler := authhandler.New(userService, authService, conf.Jwt, logger, valid)
+	refreshTokenRepo := refreshtokenrepo.New(db)
+	connectionRepo := connectionrepo.New(db)
+	savedQueryRepo := savedqueryrepo.New(db)
+	dashboardRepo := dashboardrepo.New(db)
+	workspaceRepo := workspacerepo.New(db)
+	auditRepo := auditrepo.New(db)
+	historyRepo := historyrepo.New(db)
+
+	registry := enginerepo.NewRegistry(
+		postgresrepo.NewDriver(),
+		mysqlrepo.NewDriver(),
+		sqliterepo.NewDriver(conf.Sqlite.BaseDir),
+	)
+
+	poolManager := dbpool.New(dbpool.Options{
+		MaxOpenConns:    conf.Pool.MaxOpenConns,
+		MaxIdleConns:    conf.Pool.MaxIdleConns,
+		ConnMaxLifetime: time.Duration(conf.Pool.ConnMaxLifetime) * time.Second,
+		IdleTimeout:     time.Duration(conf.Pool.IdleTimeout) * time.Second,
+		PingTimeout:     time.Duration(conf.Pool.PingTimeout) * time.Second,
+	})
+
+	cipher := &Cipher{key: cryptoutils.DeriveKey(conf.Encryption.Key)}
+
+	userService := userservice.New(userRepo, refreshTokenRepo, workspaceRepo, &Hasher{})
+	authService := authservice.New(userRepo, refreshTokenRepo, &Hasher{}, conf.Jwt)
+	auditService := auditservice.New(auditRepo, time.Duration(conf.Audit.RetentionDays)*24*time.Hour)
+	connectionService := connectionservice.New(connectionRepo, cipher, registry, poolManager, auditService)
+	engineService := engineservice.New(time.Duration(conf.Query.StatementTimeout)*time.Second,
+		conf.Query.MaxRows,
+		conf.Query.MaxStreamRows,
+		conf.Import.MaxRows,
+		conf.Import.MaxBytes,
+	)
+	historyService := historyservice.New(historyRepo, connectionService, engineService)
+	savedQueryService := savedqueryservice.New(savedQueryRepo, connectionService, historyService)
+	dashboardService := dashboardservice.New(dashboardRepo, connectionService, savedQueryService, historyService)
+	workspaceService := workspaceservice.New(workspaceRepo, userRepo)
+
+	authMiddleware := middlewares.JWTAuthMiddleware(conf.Jwt.Secret, authService, logger)
+	adminMiddleware := middlewares.RequireRole(logger, entity.RoleAdmin)
+	workspaceMiddleware := middlewares.WorkspaceMiddleware(workspaceService, logger)
+
+	authHandler := authhandler.New(userService, authService, logger, valid)
 	userHandler := userhandler.New(userService, logger, valid, authMiddleware)
-	postgresHandler := postgreshandler.New(postgresService, logger, valid)
+	connectionHandler := connectionhandler.New(connectionService, logger, valid, authMiddleware, workspaceMiddleware)
+	engineHandler := enginehandler.New(engineService, connectionService, historyService, registry, logger, valid, authMiddleware, workspaceMiddleware)
+	savedQueryHandler := savedqueryhandler.New(savedQueryService, logger, valid, authMiddleware, workspaceMiddleware)
+	dashboardHandler := dashboardhandler.New(dashboardService, logger, valid, authMiddleware, workspaceMiddleware)
+	adminHandler := adminhandler.New(userService, logger, valid, authMiddleware, adminMiddleware)
+	workspaceHandler := workspacehandler.New(workspaceService, logger, valid, authMiddleware)
+	historyHandler := historyhandler.New(historyService, logger, valid, authMiddleware, workspaceMiddleware)
+	auditHandler := audithandler.New(auditService, logger, valid, authMiddleware, adminMiddleware)
+
+	// resources of workspace are served under its path as well as under root path with X-Workspace-ID header
+	workspaceRouter := workspaceHandler.Routes()
+
+	workspaceRouter.Mount("/{workspace_id}/connections", connectionHandler.Routes())
+	workspaceRouter.Mount("/{workspace_id}/saved-queries", savedQueryHandler.Routes())
+	workspaceRouter.Mount("/{workspace_id}/dashboards", dashboardHandler.Routes())
+	workspaceRouter.Mount("/{workspace_id}/history", historyHandler.Routes())
+	workspaceRouter.Mount("/{workspace_id}/{engine}", engineHandler.Routes())
 
 	routers := make(map[string]chi.Router)
 
 	routers["/auth"] = authHandler.Routes()
 	routers["/users"] = userHandler.Routes()
-	routers["/postgres"] = postgresHandler.Routes()
+	routers["/connections"] = connectionHandler.Routes()
+	routers["/saved-queries"] = savedQueryHandler.Routes()
+	routers["/dashboards"] = dashboardHandler.Routes()
+	routers["/history"] = historyHandler.Routes()
+	routers["/admin"] = adminHandler.Routes()
+	routers["/workspaces"] = workspaceRouter
+	routers["/audit"] = auditHandler.Routes()
+	routers["/{engine}"] = engineHandler.Routes()
 
 	middlewars := []router.Middleware{
 		middleware.Recoverer,
@@ -210,6 +314,32 @@ func main() {
 		}
 	}()
 
+	// drop audit entries older than retention period
+	if conf.Audit.CleanupInterval > 0 {
+		go func() {
+			ticker := time.NewTicker(time.Duration(conf.Audit.CleanupInterval) * time.Second)
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-ctx.Done():
+					return
+
+				case <-ticker.C:
+					deleted, cleanupErr := auditService.Cleanup(ctx)
+					if cleanupErr != nil {
+						logger.WithError(cleanupErr).Errorf("can't clean up audit log")
+						continue
+					}
+
+					if deleted > 0 {
+						logger.Infof("deleted %v expired audit entries", deleted)
+					}
+				}
+			}
+		}()
+	}
+
 	logger.Infof("documentation available on: http://localhost:%v/swagger/index.html", conf.Server.Port)
 
 	interrupt := make(chan os.Signal, 1)
@@ -227,6 +357,14 @@ func main() {
 			logger.WithError(shutdownErr).Fatalf("can't close server listening on '%s'", server.Addr)
 		}
 
+		if closeErr := poolManager.Close(); closeErr != nil {
+			logger.WithError(closeErr).Errorf("can't close target database pools")
+		}
+
+		if closeErr := db.Close(); closeErr != nil {
+			logger.WithError(closeErr).Errorf("can't close database connection")
+		}
+
 		cancel()
 	}()
 
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE connections
+(
+    id            bigserial    not null primary key,
+    user_id       bigint       not null references users (id) on delete cascade,
+    name          varchar(256) not null,
+    engine        varchar(32)  not null,
+    encrypted_dsn text         not null,
+    created_at    timestamp    not null default now(),
+    updated_at    timestamp    not null default now(),
+
+    unique (user_id, name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE connections;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE saved_queries
+(
+    id            bigserial    not null primary key,
+    user_id       bigint       not null references users (id) on delete cascade,
+    connection_id bigint       not null references connections (id) on delete cascade,
+    name          varchar(256) not null,
+    description   text         not null default '',
+    sql           text         not null,
+    parameters    jsonb        not null default '[]',
+    tags          jsonb        not null default '[]',
+    created_at    timestamp    not null default now(),
+    updated_at    timestamp    not null default now(),
+
+    unique (user_id, name)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE saved_queries;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE dashboards
+(
+    id          bigserial    not null primary key,
+    user_id     bigint       not null references users (id) on delete cascade,
+    title       varchar(256) not null,
+    description text         not null default '',
+    created_at  timestamp    not null default now(),
+    updated_at  timestamp    not null default now()
+);
+
+CREATE TABLE widgets
+(
+    id               bigserial    not null primary key,
+    dashboard_id     bigint       not null references dashboards (id) on delete cascade,
+    title            varchar(256) not null,
+    type             varchar(32)  not null,
+    saved_query_id   bigint references saved_queries (id) on delete set null,
+    connection_id    bigint references connections (id) on delete set null,
+    sql              text         not null default '',
+    grid_x           int          not null default 0,
+    grid_y           int          not null default 0,
+    width            int          not null default 1,
+    height           int          not null default 1,
+    position         int          not null default 0,
+    refresh_interval int          not null default 0,
+    created_at       timestamp    not null default now(),
+    updated_at       timestamp    not null default now()
+);
+
+CREATE INDEX widgets_dashboard_id_idx ON widgets (dashboard_id, position);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE widgets;
+DROP TABLE dashboards;
+-- +goose StatementEnd
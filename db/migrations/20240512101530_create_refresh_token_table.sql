@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE refresh_tokens
+(
+    id         bigserial   not null primary key,
+    user_id    bigint      not null references users (id) on delete cascade,
+    session_id varchar(64) not null,
+    token_hash varchar(64) not null unique,
+    expires_at timestamp   not null,
+    revoked_at timestamp,
+    created_at timestamp   not null default now()
+);
+
+CREATE INDEX refresh_tokens_session_id_idx ON refresh_tokens (session_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE refresh_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,45 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE users
+    ADD COLUMN role varchar(16) not null default 'editor';
+
+-- the earliest user administers existing installation
+UPDATE users
+SET role = 'admin'
+WHERE id = (SELECT min(id) FROM users);
+
+CREATE TABLE connection_permissions
+(
+    connection_id bigint      not null references connections (id) on delete cascade,
+    user_id       bigint      not null references users (id) on delete cascade,
+    access        varchar(16) not null,
+    created_at    timestamp   not null default now(),
+    updated_at    timestamp   not null default now(),
+
+    primary key (connection_id, user_id)
+);
+
+CREATE INDEX connection_permissions_user_id_idx ON connection_permissions (user_id);
+
+CREATE TABLE dashboard_permissions
+(
+    dashboard_id bigint      not null references dashboards (id) on delete cascade,
+    user_id      bigint      not null references users (id) on delete cascade,
+    access       varchar(16) not null,
+    created_at   timestamp   not null default now(),
+    updated_at   timestamp   not null default now(),
+
+    primary key (dashboard_id, user_id)
+);
+
+CREATE INDEX dashboard_permissions_user_id_idx ON dashboard_permissions (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE dashboard_permissions;
+DROP TABLE connection_permissions;
+
+ALTER TABLE users
+    DROP COLUMN role;
+-- +goose StatementEnd
@@ -0,0 +1,67 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE workspaces
+(
+    id         bigserial    not null primary key,
+    name       varchar(256) not null,
+    created_at timestamp    not null default now(),
+    updated_at timestamp    not null default now()
+);
+
+CREATE TABLE workspace_members
+(
+    workspace_id bigint      not null references workspaces (id) on delete cascade,
+    user_id      bigint      not null references users (id) on delete cascade,
+    role         varchar(16) not null,
+    created_at   timestamp   not null default now(),
+    updated_at   timestamp   not null default now(),
+
+    primary key (workspace_id, user_id)
+);
+
+CREATE INDEX workspace_members_user_id_idx ON workspace_members (user_id);
+
+CREATE TABLE workspace_invitations
+(
+    id           bigserial    not null primary key,
+    workspace_id bigint       not null references workspaces (id) on delete cascade,
+    email        varchar(256) not null,
+    role         varchar(16)  not null,
+    invited_by   bigint references users (id) on delete set null,
+    created_at   timestamp    not null default now(),
+
+    unique (workspace_id, email)
+);
+
+CREATE INDEX workspace_invitations_email_idx ON workspace_invitations (email);
+
+-- resources without workspace stay personal
+ALTER TABLE connections
+    ADD COLUMN workspace_id bigint references workspaces (id) on delete cascade;
+
+ALTER TABLE saved_queries
+    ADD COLUMN workspace_id bigint references workspaces (id) on delete cascade;
+
+ALTER TABLE dashboards
+    ADD COLUMN workspace_id bigint references workspaces (id) on delete cascade;
+
+CREATE INDEX connections_workspace_id_idx ON connections (workspace_id);
+CREATE INDEX saved_queries_workspace_id_idx ON saved_queries (workspace_id);
+CREATE INDEX dashboards_workspace_id_idx ON dashboards (workspace_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE dashboards
+    DROP COLUMN workspace_id;
+
+ALTER TABLE saved_queries
+    DROP COLUMN workspace_id;
+
+ALTER TABLE connections
+    DROP COLUMN workspace_id;
+
+DROP TABLE workspace_invitations;
+DROP TABLE workspace_members;
+DROP TABLE workspaces;
+-- +goose StatementEnd
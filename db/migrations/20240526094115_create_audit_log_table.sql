@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+-- entries outlive users and connections they reference, so there are no foreign keys
+CREATE TABLE audit_log
+(
+    id            bigserial    not null primary key,
+    user_id       bigint,
+    user_email    varchar(256) not null default '',
+    connection_id bigint       not null,
+    workspace_id  bigint,
+    engine        varchar(32)  not null,
+    operation     varchar(32)  not null,
+    statement     text         not null,
+    params        jsonb        not null default '[]',
+    duration_ms   bigint       not null,
+    rows_returned int          not null default 0,
+    error         text         not null default '',
+    created_at    timestamp    not null default now()
+);
+
+CREATE INDEX audit_log_created_at_idx ON audit_log (created_at);
+CREATE INDEX audit_log_user_id_idx ON audit_log (user_id, created_at);
+CREATE INDEX audit_log_connection_id_idx ON audit_log (connection_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE audit_log;
+-- +goose StatementEnd
@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE query_history
+(
+    id            bigserial not null primary key,
+    user_id       bigint    not null references users (id) on delete cascade,
+    workspace_id  bigint references workspaces (id) on delete cascade,
+    connection_id bigint    not null references connections (id) on delete cascade,
+    sql           text      not null,
+    params        jsonb     not null default '[]',
+    duration_ms   bigint    not null,
+    row_count     int       not null default 0,
+    error         text      not null default '',
+    starred       boolean   not null default false,
+    created_at    timestamp not null default now()
+);
+
+CREATE INDEX query_history_user_id_idx ON query_history (user_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE query_history;
+-- +goose StatementEnd
@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE connections
+    DROP CONSTRAINT connections_user_id_name_key,
+    ADD CONSTRAINT connections_workspace_id_name_key unique (workspace_id, name);
+
+ALTER TABLE saved_queries
+    DROP CONSTRAINT saved_queries_user_id_name_key,
+    ADD CONSTRAINT saved_queries_workspace_id_name_key unique (workspace_id, name);
+
+-- personal resources have no workspace, their names stay unique per user
+CREATE UNIQUE INDEX connections_personal_user_id_name_key ON connections (user_id, name) WHERE workspace_id IS NULL;
+CREATE UNIQUE INDEX saved_queries_personal_user_id_name_key ON saved_queries (user_id, name) WHERE workspace_id IS NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX saved_queries_personal_user_id_name_key;
+DROP INDEX connections_personal_user_id_name_key;
+
+-- names are unique per workspace, the same user may repeat a name across workspaces. All but the oldest
+-- resource of repeated name get their id appended so that names are unique per user again
+UPDATE saved_queries q
+SET name = left(q.name, 256 - length(' (' || q.id || ')')) || ' (' || q.id || ')'
+FROM (SELECT id, row_number() OVER (PARTITION BY user_id, name ORDER BY id) AS n FROM saved_queries) d
+WHERE d.id = q.id AND d.n > 1;
+
+UPDATE connections c
+SET name = left(c.name, 256 - length(' (' || c.id || ')')) || ' (' || c.id || ')'
+FROM (SELECT id, row_number() OVER (PARTITION BY user_id, name ORDER BY id) AS n FROM connections) d
+WHERE d.id = c.id AND d.n > 1;
+
+ALTER TABLE saved_queries
+    DROP CONSTRAINT saved_queries_workspace_id_name_key,
+    ADD CONSTRAINT saved_queries_user_id_name_key unique (user_id, name);
+
+ALTER TABLE connections
+    DROP CONSTRAINT connections_workspace_id_name_key,
+    ADD CONSTRAINT connections_user_id_name_key unique (user_id, name);
+-- +goose StatementEnd
@@ -3,5 +3,11 @@ package config
 type Config struct {
 	Server
 	Jwt
+	Encryption
 	Postgres
+	Pool
+	Sqlite
+	Query
+	Audit
+	Import
 }
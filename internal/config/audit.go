@@ -0,0 +1,6 @@
+package config
+
+type Audit struct {
+	RetentionDays   int // entries older than this are deleted, 0 keeps them forever
+	CleanupInterval int // sec
+}
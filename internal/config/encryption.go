@@ -0,0 +1,5 @@
+package config
+
+type Encryption struct {
+	Key string
+}
@@ -0,0 +1,6 @@
+package config
+
+type Import struct {
+	MaxRows  int   // rows of uploaded file, rows are validated before any of them is inserted so they are buffered
+	MaxBytes int64 // size of uploaded file
+}
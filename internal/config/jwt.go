@@ -1,5 +1,7 @@
 package config
 
 type Jwt struct {
-	Secret string
+	Secret     string
+	AccessTTL  int // sec
+	RefreshTTL int // sec
 }
@@ -0,0 +1,9 @@
+package config
+
+type Pool struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime int // sec
+	IdleTimeout     int // sec, pool is closed after not being used for this long
+	PingTimeout     int // sec
+}
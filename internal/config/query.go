@@ -0,0 +1,7 @@
+package config
+
+type Query struct {
+	StatementTimeout int // sec
+	MaxRows          int
+	MaxStreamRows    int // cap of streamed rows, they are not buffered so it may be much higher than MaxRows
+}
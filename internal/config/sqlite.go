@@ -0,0 +1,5 @@
+package config
+
+type Sqlite struct {
+	BaseDir string // saved sqlite connections may only point to files inside this directory
+}
@@ -0,0 +1,43 @@
+package entity
+
+type MeasureFunc string
+
+const (
+	MeasureCount         MeasureFunc = "count"
+	MeasureCountDistinct MeasureFunc = "count_distinct"
+	MeasureSum           MeasureFunc = "sum"
+	MeasureAvg           MeasureFunc = "avg"
+	MeasureMin           MeasureFunc = "min"
+	MeasureMax           MeasureFunc = "max"
+)
+
+// Measure with empty Column is allowed only for count and counts rows
+type Measure struct {
+	Func   MeasureFunc
+	Column string
+	Alias  string
+}
+
+// TimeGrain truncates temporal Column to Grain, truncated column is grouped by as dimension named Column
+type TimeGrain struct {
+	Column string
+	Grain  TimeBucket
+}
+
+// AggregateQuery is no-code description of grouped query, Order refers to output column names
+type AggregateQuery struct {
+	Schema     string
+	Table      string
+	Dimensions []string
+	Measures   []Measure
+	TimeGrain  *TimeGrain
+	Filters    []Filter
+	Order      []Sort
+	Limit      int
+}
+
+type AggregateResult struct {
+	SQL    string
+	Args   []any
+	Result *QueryResult
+}
@@ -0,0 +1,68 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+// AuditOperation names repository call of target database audit entry is recorded for
+type AuditOperation string
+
+const (
+	AuditOperationDefaultSchema AuditOperation = "default_schema"
+	AuditOperationSchemas       AuditOperation = "schemas"
+	AuditOperationTables        AuditOperation = "tables"
+	AuditOperationColumns       AuditOperation = "columns"
+	AuditOperationRows          AuditOperation = "rows"
+	AuditOperationStream        AuditOperation = "stream"
+	AuditOperationCount         AuditOperation = "count"
+	AuditOperationEstimate      AuditOperation = "estimate"
+	AuditOperationQuery         AuditOperation = "query"
+	AuditOperationImport        AuditOperation = "import"
+	AuditOperationImportDryRun  AuditOperation = "import_dry_run"
+)
+
+// AuditParams keeps types of bound parameters, values are redacted
+type AuditParams []string
+
+func (p AuditParams) Value() (driver.Value, error) {
+	if p == nil {
+		p = AuditParams{}
+	}
+
+	return jsonValue(p)
+}
+
+func (p *AuditParams) Scan(src any) error {
+	return jsonScan(src, p)
+}
+
+// AuditEntry records single call to target database, entries outlive users and connections they reference
+type AuditEntry struct {
+	ID           int            `db:"id"`
+	UserID       *int           `db:"user_id"` // nil for calls made without authenticated user
+	UserEmail    string         `db:"user_email"`
+	ConnectionID int            `db:"connection_id"`
+	WorkspaceID  *int           `db:"workspace_id"`
+	Engine       string         `db:"engine"`
+	Operation    AuditOperation `db:"operation"`
+	Statement    string         `db:"statement"` // sql text or accessed table
+	Params       AuditParams    `db:"params"`
+	DurationMs   int64          `db:"duration_ms"`
+	RowsReturned int            `db:"rows_returned"` // inserted rows for imports
+	Error        string         `db:"error"`
+	CreatedAt    time.Time      `db:"created_at"`
+}
+
+// AuditFilter selects audit entries, zero fields do not filter
+type AuditFilter struct {
+	UserID       *int
+	ConnectionID *int
+	Operation    AuditOperation
+	Failed       *bool
+	From         *time.Time
+	To           *time.Time
+	Search       string // substring of statement
+	Offset       int
+	Limit        int
+}
@@ -0,0 +1,17 @@
+package entity
+
+import "time"
+
+type Connection struct {
+	ID           int       `db:"id"`
+	UserID       int       `db:"user_id"`
+	WorkspaceID  *int      `db:"workspace_id"` // nil for personal resources
+	Name         string    `db:"name"`
+	Engine       string    `db:"engine"`
+	EncryptedDSN string    `db:"encrypted_dsn"`
+	CreatedAt    time.Time `db:"created_at"`
+	UpdatedAt    time.Time `db:"updated_at"`
+
+	// Access is level of user the connection was fetched for, filled by service
+	Access ConnectionAccess `db:"access"`
+}
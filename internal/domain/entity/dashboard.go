@@ -0,0 +1,108 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+type WidgetType string
+
+const (
+	WidgetTypeTable      WidgetType = "table"
+	WidgetTypeLine       WidgetType = "line"
+	WidgetTypeBar        WidgetType = "bar"
+	WidgetTypePie        WidgetType = "pie"
+	WidgetTypeSingleStat WidgetType = "single_stat"
+)
+
+type TimeBucket string
+
+const (
+	TimeBucketNone   TimeBucket = ""
+	TimeBucketMinute TimeBucket = "minute"
+	TimeBucketHour   TimeBucket = "hour"
+	TimeBucketDay    TimeBucket = "day"
+	TimeBucketWeek   TimeBucket = "week"
+	TimeBucketMonth  TimeBucket = "month"
+	TimeBucketYear   TimeBucket = "year"
+)
+
+type Aggregate string
+
+const (
+	AggregateSum   Aggregate = "sum"
+	AggregateAvg   Aggregate = "avg"
+	AggregateMin   Aggregate = "min"
+	AggregateMax   Aggregate = "max"
+	AggregateCount Aggregate = "count"
+)
+
+// WidgetOptions describe how query result is shaped into chart series, empty fields are picked by column types
+type WidgetOptions struct {
+	XColumn    string     `json:"x_column,omitempty"`
+	YColumns   []string   `json:"y_columns,omitempty"`
+	GroupBy    string     `json:"group_by,omitempty"`
+	TimeBucket TimeBucket `json:"time_bucket,omitempty"`
+	Aggregate  Aggregate  `json:"aggregate,omitempty"` // applied to values falling into the same x, sum by default
+}
+
+func (o WidgetOptions) Value() (driver.Value, error) {
+	return jsonValue(o)
+}
+
+func (o *WidgetOptions) Scan(src any) error {
+	return jsonScan(src, o)
+}
+
+type Dashboard struct {
+	ID          int       `db:"id"`
+	UserID      int       `db:"user_id"`
+	WorkspaceID *int      `db:"workspace_id"` // nil for personal resources
+	Title       string    `db:"title"`
+	Description string    `db:"description"`
+	CreatedAt   time.Time `db:"created_at"`
+	UpdatedAt   time.Time `db:"updated_at"`
+
+	// Access is level of user the dashboard was fetched for, filled by service
+	Access DashboardAccess `db:"access"`
+}
+
+// Widget is bound either to saved query or to connection with inline sql
+type Widget struct {
+	ID              int           `db:"id"`
+	DashboardID     int           `db:"dashboard_id"`
+	Title           string        `db:"title"`
+	Type            WidgetType    `db:"type"`
+	SavedQueryID    *int          `db:"saved_query_id"`
+	ConnectionID    *int          `db:"connection_id"`
+	SQL             string        `db:"sql"`
+	GridX           int           `db:"grid_x"`
+	GridY           int           `db:"grid_y"`
+	Width           int           `db:"width"`
+	Height          int           `db:"height"`
+	Position        int           `db:"position"`
+	RefreshInterval int           `db:"refresh_interval"` // sec, 0 disables auto refresh
+	Options         WidgetOptions `db:"options"`
+	CreatedAt       time.Time     `db:"created_at"`
+	UpdatedAt       time.Time     `db:"updated_at"`
+}
+
+type ChartPoint struct {
+	X any
+	Y *float64
+}
+
+type ChartSeries struct {
+	Name   string
+	Points []ChartPoint
+}
+
+// WidgetData is query result of widget shaped for its type, table widgets get raw rows
+type WidgetData struct {
+	Type      WidgetType
+	Columns   []QueryColumn
+	Rows      [][]any
+	XColumn   string
+	Series    []ChartSeries
+	Truncated bool
+}
@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+)
+
+// HistoryParams keeps bind params of executed query so that it can be replayed
+type HistoryParams []any
+
+func (p HistoryParams) Value() (driver.Value, error) {
+	if p == nil {
+		p = HistoryParams{}
+	}
+
+	return jsonValue(p)
+}
+
+// Scan decodes numbers with UseNumber, so that integers are restored as int64 without passing through float64
+func (p *HistoryParams) Scan(src any) error {
+	var data []byte
+
+	switch v := src.(type) {
+	case nil:
+		return nil
+
+	case []byte:
+		data = v
+
+	case string:
+		data = []byte(v)
+
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, p)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var params []any
+
+	if err := dec.Decode(&params); err != nil {
+		return err
+	}
+
+	for i, param := range params {
+		if n, ok := param.(json.Number); ok {
+			params[i] = ParamFromNumber(n)
+		}
+	}
+
+	*p = params
+
+	return nil
+}
+
+// ParamFromNumber returns json number as int64 if it is a whole number, as float64 otherwise.
+// Whole floats such as 1.0 are integers as well as long as float64 holds them exactly
+func ParamFromNumber(n json.Number) any {
+	if i, err := n.Int64(); err == nil {
+		return i
+	}
+
+	f, _ := n.Float64()
+
+	if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return int64(f)
+	}
+
+	return f
+}
+
+// HistoryEntry is query executed by user from query console, saved query or widget, it is visible to the user only
+type HistoryEntry struct {
+	ID           int           `db:"id"`
+	UserID       int           `db:"user_id"`
+	WorkspaceID  *int          `db:"workspace_id"` // workspace of connection, nil for personal connections
+	ConnectionID int           `db:"connection_id"`
+	SQL          string        `db:"sql"`
+	Params       HistoryParams `db:"params"`
+	DurationMs   int64         `db:"duration_ms"`
+	RowCount     int           `db:"row_count"`
+	Error        string        `db:"error"` // empty for succeeded queries
+	Starred      bool          `db:"starred"`
+	CreatedAt    time.Time     `db:"created_at"`
+}
+
+// HistoryFilter selects history entries of user, zero fields do not filter
+type HistoryFilter struct {
+	UserID       int
+	WorkspaceID  *int
+	ConnectionID *int
+	Starred      bool
+	Search       string // substring of sql
+	Offset       int
+	Limit        int
+}
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHistoryParamsScan(t *testing.T) {
+	tests := []struct {
+		name string
+		src  any
+		want HistoryParams
+	}{
+		{"nil", nil, nil},
+		{"integer beyond float precision", []byte(`[9007199254740993]`), HistoryParams{int64(9007199254740993)}},
+		{"max int64", `[9223372036854775807]`, HistoryParams{int64(9223372036854775807)}},
+		{"negative integer", `[-42]`, HistoryParams{int64(-42)}},
+		{"whole float", `[2.0]`, HistoryParams{int64(2)}},
+		{"fraction", `[1.5]`, HistoryParams{1.5}},
+		{"mixed", `["a", true, null, 7]`, HistoryParams{"a", true, nil, int64(7)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got HistoryParams
+
+			if err := got.Scan(tt.src); err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Scan() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryParamsRoundTrip(t *testing.T) {
+	params := HistoryParams{int64(9007199254740993), "x", 0.25}
+
+	value, err := params.Value()
+	if err != nil {
+		t.Fatalf("Value() error = %v", err)
+	}
+
+	var got HistoryParams
+
+	if err = got.Scan(value); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(got, params) {
+		t.Errorf("round trip = %#v, want %#v", got, params)
+	}
+}
@@ -0,0 +1,35 @@
+package entity
+
+type ImportFormat string
+
+const (
+	ImportFormatCSV    ImportFormat = "csv"
+	ImportFormatNDJSON ImportFormat = "ndjson"
+)
+
+// Import describes upload of file rows into table. File columns are matched to table columns by name
+// unless Mapping is set, then only mapped file columns are imported
+type Import struct {
+	Schema    string
+	Table     string
+	Format    ImportFormat
+	Delimiter rune              // csv field delimiter, comma if zero
+	Null      string            // csv field read as NULL
+	Mapping   map[string]string // file column to table column
+	DryRun    bool              // rows are validated and inserted in transaction which is rolled back
+}
+
+// ImportError is validation error of file row, rows are numbered from 1 not counting csv header
+type ImportError struct {
+	Row     int
+	Column  string
+	Message string
+}
+
+type ImportResult struct {
+	Rows        int
+	Inserted    int64
+	InvalidRows int
+	Errors      []ImportError // first errors of invalid rows, nothing is inserted if there are any
+	DryRun      bool
+}
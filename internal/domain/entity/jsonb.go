@@ -0,0 +1,32 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+func jsonValue(v any) (driver.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+func jsonScan(src any, dst any) error {
+	switch v := src.(type) {
+	case nil:
+		return nil
+
+	case []byte:
+		return json.Unmarshal(v, dst)
+
+	case string:
+		return json.Unmarshal([]byte(v), dst)
+
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, dst)
+	}
+}
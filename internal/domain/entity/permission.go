@@ -0,0 +1,87 @@
+package entity
+
+import "time"
+
+// ConnectionAccess levels are ordered, each level includes the previous ones
+type ConnectionAccess string
+
+const (
+	ConnectionAccessNone     ConnectionAccess = ""
+	ConnectionAccessReadOnly ConnectionAccess = "read_only" // browse tables and run builder queries
+	ConnectionAccessQuery    ConnectionAccess = "query"     // run read only sql in query console
+	ConnectionAccessWrite    ConnectionAccess = "write"     // run any sql in query console and import rows
+)
+
+var connectionAccessRanks = map[ConnectionAccess]int{
+	ConnectionAccessReadOnly: 1,
+	ConnectionAccessQuery:    2,
+	ConnectionAccessWrite:    3,
+}
+
+func (a ConnectionAccess) Valid() bool {
+	return connectionAccessRanks[a] > 0
+}
+
+func (a ConnectionAccess) Allows(need ConnectionAccess) bool {
+	return a.Valid() && connectionAccessRanks[a] >= connectionAccessRanks[need]
+}
+
+// Cap lowers access to max if it is higher
+func (a ConnectionAccess) Cap(max ConnectionAccess) ConnectionAccess {
+	if connectionAccessRanks[a] > connectionAccessRanks[max] {
+		return max
+	}
+
+	return a
+}
+
+// DashboardAccess levels are ordered, each level includes the previous ones
+type DashboardAccess string
+
+const (
+	DashboardAccessNone DashboardAccess = ""
+	DashboardAccessView DashboardAccess = "view" // view dashboard and its widget data
+	DashboardAccessEdit DashboardAccess = "edit" // change dashboard and its widgets
+	DashboardAccessOwn  DashboardAccess = "own"  // delete dashboard and share it
+)
+
+var dashboardAccessRanks = map[DashboardAccess]int{
+	DashboardAccessView: 1,
+	DashboardAccessEdit: 2,
+	DashboardAccessOwn:  3,
+}
+
+func (a DashboardAccess) Valid() bool {
+	return dashboardAccessRanks[a] > 0
+}
+
+func (a DashboardAccess) Allows(need DashboardAccess) bool {
+	return a.Valid() && dashboardAccessRanks[a] >= dashboardAccessRanks[need]
+}
+
+// Cap lowers access to max if it is higher
+func (a DashboardAccess) Cap(max DashboardAccess) DashboardAccess {
+	if dashboardAccessRanks[a] > dashboardAccessRanks[max] {
+		return max
+	}
+
+	return a
+}
+
+// ConnectionPermission grants access to connection to user other than its owner
+type ConnectionPermission struct {
+	ConnectionID int              `db:"connection_id"`
+	UserID       int              `db:"user_id"`
+	Access       ConnectionAccess `db:"access"`
+	CreatedAt    time.Time        `db:"created_at"`
+	UpdatedAt    time.Time        `db:"updated_at"`
+}
+
+// DashboardPermission grants access to dashboard to user other than its owner
+type DashboardPermission struct {
+	DashboardID int             `db:"dashboard_id"`
+	UserID      int             `db:"user_id"`
+	Access      DashboardAccess `db:"access"`
+	CreatedAt   time.Time       `db:"created_at"`
+	UpdatedAt   time.Time       `db:"updated_at"`
+}
@@ -1,3 +0,0 @@
-package postgres
-
-type Row = map[string]any
@@ -0,0 +1,15 @@
+package entity
+
+type QueryColumn struct {
+	Name string
+	Type string
+	// Precision and Scale reported by driver for decimal columns, zero precision if unknown
+	Precision int
+	Scale     int
+}
+
+type QueryResult struct {
+	Columns   []QueryColumn
+	Rows      [][]any
+	Truncated bool
+}
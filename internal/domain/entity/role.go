@@ -0,0 +1,42 @@
+package entity
+
+type Role string
+
+const (
+	RoleAdmin  Role = "admin"
+	RoleEditor Role = "editor"
+	RoleViewer Role = "viewer"
+)
+
+func (r Role) Valid() bool {
+	switch r {
+	case RoleAdmin, RoleEditor, RoleViewer:
+		return true
+	}
+
+	return false
+}
+
+// MaxConnectionAccess caps access granted on connections, viewers never write to target databases
+func (r Role) MaxConnectionAccess() ConnectionAccess {
+	switch r {
+	case RoleAdmin, RoleEditor:
+		return ConnectionAccessWrite
+	case RoleViewer:
+		return ConnectionAccessQuery
+	}
+
+	return ConnectionAccessNone
+}
+
+// MaxDashboardAccess caps access granted on dashboards, viewers never change them
+func (r Role) MaxDashboardAccess() DashboardAccess {
+	switch r {
+	case RoleAdmin, RoleEditor:
+		return DashboardAccessOwn
+	case RoleViewer:
+		return DashboardAccessView
+	}
+
+	return DashboardAccessNone
+}
@@ -0,0 +1,52 @@
+package entity
+
+type FilterOp string
+
+const (
+	FilterOpEq      FilterOp = "eq"
+	FilterOpNe      FilterOp = "ne"
+	FilterOpLt      FilterOp = "lt"
+	FilterOpGt      FilterOp = "gt"
+	FilterOpLike    FilterOp = "like"
+	FilterOpIn      FilterOp = "in"
+	FilterOpIsNull  FilterOp = "is_null"
+	FilterOpNotNull FilterOp = "not_null"
+)
+
+type Filter struct {
+	Column string
+	Op     FilterOp
+	Values []string
+}
+
+type Sort struct {
+	Column string
+	Desc   bool
+}
+
+type CountMode string
+
+const (
+	CountModeNone      CountMode = "none"
+	CountModeExact     CountMode = "exact"
+	CountModeEstimated CountMode = "estimated"
+)
+
+type RowsQuery struct {
+	Schema  string
+	Table   string
+	Filters []Filter
+	Sort    []Sort
+	Limit   int
+	Offset  int
+	Cursor  string
+	Count   CountMode
+}
+
+type RowsPage struct {
+	Columns        []QueryColumn
+	Rows           [][]any // values are ordered as columns
+	Total          *int64
+	TotalEstimated bool
+	NextCursor     string
+}
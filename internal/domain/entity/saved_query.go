@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"time"
+)
+
+type ParamType string
+
+const (
+	ParamTypeString    ParamType = "string"
+	ParamTypeInteger   ParamType = "integer"
+	ParamTypeNumber    ParamType = "number"
+	ParamTypeBoolean   ParamType = "boolean"
+	ParamTypeTimestamp ParamType = "timestamp"
+)
+
+type QueryParameter struct {
+	Name    string    `json:"name"`
+	Type    ParamType `json:"type"`
+	Default any       `json:"default,omitempty"`
+}
+
+// QueryParameters stored as jsonb
+type QueryParameters []QueryParameter
+
+func (p QueryParameters) Value() (driver.Value, error) {
+	if p == nil {
+		p = QueryParameters{}
+	}
+
+	return jsonValue(p)
+}
+
+func (p *QueryParameters) Scan(src any) error {
+	return jsonScan(src, p)
+}
+
+// Tags stored as jsonb
+type Tags []string
+
+func (t Tags) Value() (driver.Value, error) {
+	if t == nil {
+		t = Tags{}
+	}
+
+	return jsonValue(t)
+}
+
+func (t *Tags) Scan(src any) error {
+	return jsonScan(src, t)
+}
+
+type SavedQuery struct {
+	ID           int             `db:"id"`
+	UserID       int             `db:"user_id"`
+	WorkspaceID  *int            `db:"workspace_id"` // nil for personal resources
+	ConnectionID int             `db:"connection_id"`
+	Name         string          `db:"name"`
+	Description  string          `db:"description"`
+	SQL          string          `db:"sql"`
+	Parameters   QueryParameters `db:"parameters"`
+	Tags         Tags            `db:"tags"`
+	CreatedAt    time.Time       `db:"created_at"`
+	UpdatedAt    time.Time       `db:"updated_at"`
+}
@@ -0,0 +1,12 @@
+package entity
+
+type Table struct {
+	Name string `db:"table_name"`
+}
+
+type Column struct {
+	Name       string `db:"column_name"`
+	Type       string `db:"data_type"`
+	Nullable   bool   `db:"is_nullable"`
+	PrimaryKey bool   `db:"is_primary_key"`
+}
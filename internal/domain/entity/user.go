@@ -3,9 +3,11 @@ package entity
 import "time"
 
 type User struct {
-	ID             int       `db:"id"`
-	Email          string    `db:"email"`
-	HashedPassword string    `db:"hashed_password"`
-	CreatedAt      time.Time `db:"created_at"`
-	UpdatedAt      time.Time `db:"updated_at"`
+	ID             int        `db:"id"`
+	Email          string     `db:"email"`
+	HashedPassword string     `db:"hashed_password"`
+	Role           Role       `db:"role"`
+	DisabledAt     *time.Time `db:"disabled_at"` // disabled users can not log in
+	CreatedAt      time.Time  `db:"created_at"`
+	UpdatedAt      time.Time  `db:"updated_at"`
 }
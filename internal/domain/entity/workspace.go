@@ -0,0 +1,82 @@
+package entity
+
+import "time"
+
+// WorkspaceRole is role of member within workspace, it grants access to every resource of workspace.
+// Roles are ordered, each role includes the previous ones
+type WorkspaceRole string
+
+const (
+	WorkspaceRoleViewer WorkspaceRole = "viewer" // reads resources of workspace
+	WorkspaceRoleEditor WorkspaceRole = "editor" // creates and changes resources of workspace
+	WorkspaceRoleOwner  WorkspaceRole = "owner"  // manages workspace, its members and resources
+)
+
+var workspaceRoleRanks = map[WorkspaceRole]int{
+	WorkspaceRoleViewer: 1,
+	WorkspaceRoleEditor: 2,
+	WorkspaceRoleOwner:  3,
+}
+
+func (r WorkspaceRole) Valid() bool {
+	return workspaceRoleRanks[r] > 0
+}
+
+func (r WorkspaceRole) Allows(need WorkspaceRole) bool {
+	return r.Valid() && workspaceRoleRanks[r] >= workspaceRoleRanks[need]
+}
+
+// ConnectionAccess is access granted to member on every connection of workspace
+func (r WorkspaceRole) ConnectionAccess() ConnectionAccess {
+	switch r {
+	case WorkspaceRoleOwner, WorkspaceRoleEditor:
+		return ConnectionAccessWrite
+	case WorkspaceRoleViewer:
+		return ConnectionAccessQuery
+	}
+
+	return ConnectionAccessNone
+}
+
+// DashboardAccess is access granted to member on every dashboard of workspace
+func (r WorkspaceRole) DashboardAccess() DashboardAccess {
+	switch r {
+	case WorkspaceRoleOwner:
+		return DashboardAccessOwn
+	case WorkspaceRoleEditor:
+		return DashboardAccessEdit
+	case WorkspaceRoleViewer:
+		return DashboardAccessView
+	}
+
+	return DashboardAccessNone
+}
+
+type Workspace struct {
+	ID        int       `db:"id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+	UpdatedAt time.Time `db:"updated_at"`
+
+	// Role is role of user the workspace was fetched for
+	Role WorkspaceRole `db:"role"`
+}
+
+type Membership struct {
+	WorkspaceID int           `db:"workspace_id"`
+	UserID      int           `db:"user_id"`
+	Email       string        `db:"email"` // email of member, joined from users
+	Role        WorkspaceRole `db:"role"`
+	CreatedAt   time.Time     `db:"created_at"`
+	UpdatedAt   time.Time     `db:"updated_at"`
+}
+
+// Invitation to workspace is addressed by email, so that users can be invited before they register
+type Invitation struct {
+	ID          int           `db:"id"`
+	WorkspaceID int           `db:"workspace_id"`
+	Email       string        `db:"email"`
+	Role        WorkspaceRole `db:"role"`
+	InvitedBy   *int          `db:"invited_by"`
+	CreatedAt   time.Time     `db:"created_at"`
+}
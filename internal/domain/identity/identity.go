@@ -0,0 +1,124 @@
+package identity
+
+import (
+	"context"
+	"errors"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+var ErrNoIdentity = errors.New("no authenticated user in context")
+
+type ctxKey struct{}
+
+// Identity is authenticated user set to request context by auth middleware
+type Identity struct {
+	UserID    int
+	Email     string
+	SessionID string
+	Role      entity.Role
+}
+
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+func FromContext(ctx context.Context) (Identity, error) {
+	id, ok := ctx.Value(ctxKey{}).(Identity)
+	if !ok {
+		return Identity{}, ErrNoIdentity
+	}
+
+	return id, nil
+}
+
+// UserID is shortcut for services doing ownership checks
+func UserID(ctx context.Context) (int, error) {
+	id, err := FromContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	return id.UserID, nil
+}
+
+// HasRole reports whether authenticated user has one of roles, false if there is no authenticated user
+func HasRole(ctx context.Context, roles ...entity.Role) bool {
+	id, err := FromContext(ctx)
+	if err != nil {
+		return false
+	}
+
+	for _, role := range roles {
+		if id.Role == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Role returns role of authenticated user, empty role grants nothing
+func Role(ctx context.Context) entity.Role {
+	id, err := FromContext(ctx)
+	if err != nil {
+		return ""
+	}
+
+	return id.Role
+}
+
+type workspaceCtxKey struct{}
+
+// Workspace is active workspace of request set by workspace middleware, resources are scoped by it
+type Workspace struct {
+	ID   int
+	Role entity.WorkspaceRole
+}
+
+func WithWorkspace(ctx context.Context, ws Workspace) context.Context {
+	return context.WithValue(ctx, workspaceCtxKey{}, ws)
+}
+
+// ActiveWorkspace returns workspace selected for request, false means request is scoped to personal resources
+func ActiveWorkspace(ctx context.Context) (Workspace, bool) {
+	ws, ok := ctx.Value(workspaceCtxKey{}).(Workspace)
+	return ws, ok
+}
+
+// WorkspaceID returns id of active workspace, nil for personal scope
+func WorkspaceID(ctx context.Context) *int {
+	ws, ok := ActiveWorkspace(ctx)
+	if !ok {
+		return nil
+	}
+
+	return &ws.ID
+}
+
+// InScope reports whether resource of workspace belongs to scope of request, nil workspaceID is personal resource
+func InScope(ctx context.Context, workspaceID *int) bool {
+	active := WorkspaceID(ctx)
+
+	if active == nil || workspaceID == nil {
+		return active == nil && workspaceID == nil
+	}
+
+	return *active == *workspaceID
+}
+
+// HasWorkspaceRole reports whether authenticated user has one of roles in active workspace, false in personal scope
+func HasWorkspaceRole(ctx context.Context, roles ...entity.WorkspaceRole) bool {
+	ws, ok := ActiveWorkspace(ctx)
+	if !ok {
+		return false
+	}
+
+	for _, role := range roles {
+		if ws.Role == role {
+			return true
+		}
+	}
+
+	return false
+}
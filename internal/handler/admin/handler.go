@@ -0,0 +1,136 @@
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/mapper"
+	"db-dashboards/internal/handler/request"
+
+	userrepo "db-dashboards/internal/repository/user"
+	userservice "db-dashboards/internal/service/user"
+
+	handlerutils "db-dashboards/pkg/utils/handler"
+)
+
+type UserService interface {
+	SetUserRole(ctx context.Context, id int, role entity.Role) (*entity.User, error)
+}
+
+type Middleware = func(http.Handler) http.Handler
+
+// Handler serves administration endpoints, middlewares are expected to authenticate request and require admin role
+type Handler struct {
+	UserService UserService
+	Middlewares []Middleware
+
+	logger    *logrus.Logger
+	validator *validator.Validate
+}
+
+func New(userService UserService,
+	logger *logrus.Logger,
+	validator *validator.Validate,
+	middlewares ...Middleware,
+) *Handler {
+	return &Handler{
+		UserService: userService,
+		Middlewares: middlewares,
+		logger:      logger,
+		validator:   validator,
+	}
+}
+
+func (h *Handler) Routes() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(h.Middlewares...)
+
+		r.Put("/users/{id}/role", h.SetUserRole)
+	})
+
+	return router
+}
+
+// SetUserRole godoc
+//
+//	@Summary		Assign user role
+//	@Description	Assign admin, editor or viewer role to user, sessions of user are revoked so that new role applies from the next login.
+//	@Description	The last admin can not be demoted
+//	@Security		JWT
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int						true	"user id"
+//	@Param			input	body		request.SetRoleRequest	true	"role"
+//	@Success		200		{object}	response.GetUserResponse
+//	@Failure		400		{string}	invalid		role	provided
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		403		{string}	admin		rights	required
+//	@Failure		404		{string}	user		not		found
+//	@Failure		409		{string}	last		admin
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/admin/users/{id}/role [put]
+func (h *Handler) SetUserRole(rw http.ResponseWriter, req *http.Request) {
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid user id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	var roleReq request.SetRoleRequest
+
+	if err = render.DecodeJSON(req.Body, &roleReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to SetRoleRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid role provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err = roleReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating SetRoleRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid role provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	user, err := h.UserService.SetUserRole(req.Context(), id, entity.Role(roleReq.Role))
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred setting user role: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapUserToUserResponse(user))
+}
+
+func (h *Handler) writeServiceErr(rw http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, userrepo.ErrUserNotFound):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusNotFound, msg, msg)
+
+	case errors.Is(err, userservice.ErrAdminRequired):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusForbidden, msg, msg)
+
+	case errors.Is(err, userservice.ErrInvalidRole):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+
+	case errors.Is(err, userservice.ErrLastAdmin):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusConflict, msg, msg)
+
+	default:
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+	}
+}
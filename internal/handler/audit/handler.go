@@ -0,0 +1,118 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/mapper"
+
+	auditservice "db-dashboards/internal/service/audit"
+
+	handlerinternalutils "db-dashboards/internal/handler/utils"
+	handlerutils "db-dashboards/pkg/utils/handler"
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+type Service interface {
+	GetEntries(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error)
+}
+
+type Middleware = func(http.Handler) http.Handler
+
+// Handler serves audit log, middlewares are expected to authenticate request and require admin role
+type Handler struct {
+	Service     Service
+	Middlewares []Middleware
+
+	logger    *logrus.Logger
+	validator *validator.Validate
+}
+
+func New(service Service,
+	logger *logrus.Logger,
+	validator *validator.Validate,
+	middlewares ...Middleware,
+) *Handler {
+	return &Handler{
+		Service:     service,
+		Middlewares: middlewares,
+		logger:      logger,
+		validator:   validator,
+	}
+}
+
+func (h *Handler) Routes() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(h.Middlewares...)
+
+		r.Get("/", h.GetEntries)
+	})
+
+	return router
+}
+
+// GetEntries godoc
+//
+//	@Summary		Get audit log
+//	@Description	Get calls made to target databases, the most recent first. Values of bound params are redacted, only their types are kept
+//	@Security		JWT
+//	@Tags			Audit
+//	@Produce		json
+//	@Param			user_id			query		int		false	"user id"
+//	@Param			connection_id	query		int		false	"connection id"
+//	@Param			operation		query		string	false	"operation"	Enums(default_schema, schemas, tables, columns, rows, stream, count, estimate, query, import, import_dry_run)
+//	@Param			status			query		string	false	"status"	Enums(failed, succeeded)
+//	@Param			from			query		string	false	"RFC3339 time, inclusive"
+//	@Param			to				query		string	false	"RFC3339 time, exclusive"
+//	@Param			search			query		string	false	"substring of statement"
+//	@Param			offset			query		int		false	"Offset"
+//	@Param			limit			query		int		false	"Limit"
+//	@Success		200				{object}	[]response.AuditEntryResponse
+//	@Failure		400				{string}	invalid		filter	provided
+//	@Failure		401				{string}	Unauthorized
+//	@Failure		403				{string}	admin		rights	required
+//	@Failure		500				{string}	internal	error
+//	@Router			/db-dashboards/api/v1/audit [get]
+func (h *Handler) GetEntries(rw http.ResponseWriter, req *http.Request) {
+	getReq := handlerinternalutils.GetAuditRequestFromQuery(req)
+
+	if err := getReq.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid filter provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	filter, err := mapper.MapGetAuditRequestToAuditFilter(&getReq)
+	if err != nil {
+		msg := fmt.Sprintf("invalid filter provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	entries, err := h.Service.GetEntries(req.Context(), filter)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred fetching audit entries: %v", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, auditservice.ErrAdminRequired) {
+			status = http.StatusForbidden
+		}
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, status, msg, msg)
+		return
+	}
+
+	render.JSON(rw, req, sliceutils.Map(entries, mapper.MapAuditEntryToAuditEntryResponse))
+}
@@ -2,34 +2,32 @@ package auth
 
 import (
 	"context"
-	"db-dashboards/internal/config"
 	"db-dashboards/internal/domain/entity"
 	"db-dashboards/internal/handler/mapper"
 	"db-dashboards/internal/handler/request"
-	"db-dashboards/internal/handler/response"
+	"errors"
 	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
-	"github.com/golang-jwt/jwt/v5"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
 	"net/http"
 
+	userrepo "db-dashboards/internal/repository/user"
+	authservice "db-dashboards/internal/service/auth"
 	handlerutils "db-dashboards/pkg/utils/handler"
-	jwtutils "db-dashboards/pkg/utils/jwt"
 )
 
 type UserService interface {
 	RegisterUser(ctx context.Context, user entity.User) (*entity.User, error)
-	GetUserByID(ctx context.Context, id int) (*entity.User, error)
-	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
-	GetAllUsers(ctx context.Context, offset, limit int) ([]*entity.User, error)
-	DeleteUser(ctx context.Context, id int) (*entity.User, error)
 }
 
 type AuthService interface {
 	Login(ctx context.Context, email, password string) (*entity.User, error)
+	IssueTokens(ctx context.Context, user *entity.User) (*entity.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (*entity.TokenPair, error)
+	Logout(ctx context.Context, refreshToken string) error
 }
 
 type Middleware = func(http.Handler) http.Handler
@@ -39,15 +37,12 @@ type Handler struct {
 	AuthService AuthService
 	Middlewares []Middleware
 
-	JwtConfig config.Jwt
-
 	logger    *logrus.Logger
 	validator *validator.Validate
 }
 
 func New(userService UserService,
 	authService AuthService,
-	jwtConfig config.Jwt,
 	logger *logrus.Logger,
 	validator *validator.Validate,
 	middlewares ...Middleware,
@@ -55,7 +50,6 @@ func New(userService UserService,
 	return &Handler{
 		UserService: userService,
 		AuthService: authService,
-		JwtConfig:   jwtConfig,
 		Middlewares: middlewares,
 		logger:      logger,
 		validator:   validator,
@@ -69,6 +63,8 @@ func (h *Handler) Routes() *chi.Mux {
 		r.Use(h.Middlewares...)
 		r.Post("/register", h.Register)
 		r.Post("/login", h.Login)
+		r.Post("/refresh", h.Refresh)
+		r.Post("/logout", h.Logout)
 	})
 
 	return router
@@ -77,13 +73,14 @@ func (h *Handler) Routes() *chi.Mux {
 // Register godoc
 //
 //	@Summary		Register new user
-//	@Description	to register new user
+//	@Description	to register new user, the first registered user becomes admin
 //	@Tags			Auth
 //	@Accept			json
-//	@Produce		plain
+//	@Produce		json
 //	@Param			input	body		request.RegisterRequest	true	"registration info"
-//	@Success		200		{object}	response.GetUserResponse
+//	@Success		201		{object}	response.GetUserResponse
 //	@Failure		400		{string}	invalid		registration	data	provided
+//	@Failure		409		{string}	user		with			this	email	already	exists
 //	@Failure		500		{string}	internal	error
 //	@Router			/db-dashboards/api/v1/auth/register [post]
 func (h *Handler) Register(rw http.ResponseWriter, req *http.Request) {
@@ -111,13 +108,18 @@ func (h *Handler) Register(rw http.ResponseWriter, req *http.Request) {
 	if err != nil {
 		msg := fmt.Sprintf("error occurred registrating user: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+		status := http.StatusInternalServerError
+		if errors.Is(err, userrepo.ErrEmailExists) {
+			status = http.StatusConflict
+		}
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, status, msg, msg)
 
 		return
 	}
 
+	render.Status(req, http.StatusCreated)
 	render.JSON(rw, req, mapper.MapUserToUserResponse(user))
-	rw.WriteHeader(http.StatusCreated)
 }
 
 // Login godoc
@@ -126,10 +128,11 @@ func (h *Handler) Register(rw http.ResponseWriter, req *http.Request) {
 //	@Description	login user via JWT
 //	@Tags			Auth
 //	@Accept			json
-//	@Produce		plain
+//	@Produce		json
 //	@Param			input	body		request.LoginRequest	true	"login info"
 //	@Success		200		{object}	response.LoginResponse
 //	@Failure		400		{string}	invalid		login	data	provided
+//	@Failure		403		{string}	user		is		disabled
 //	@Failure		500		{string}	internal	error
 //	@Router			/db-dashboards/api/v1/auth/login [post]
 func (h *Handler) Login(rw http.ResponseWriter, req *http.Request) {
@@ -155,29 +158,116 @@ func (h *Handler) Login(rw http.ResponseWriter, req *http.Request) {
 	if err != nil {
 		msg := fmt.Sprintf("error occurred while user login: %v", err)
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		status := http.StatusBadRequest
+		if errors.Is(err, authservice.ErrUserDisabled) {
+			status = http.StatusForbidden
+		}
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, status, msg, msg)
 		return
 	}
 
-	// construct jwt token
-	payload := map[string]any{
-		"id":    user.ID,
-		"email": user.Email,
+	pair, err := h.AuthService.IssueTokens(req.Context(), user)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred issuing tokens: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+		return
 	}
 
-	token, err := jwtutils.CreateJWT(payload, jwt.SigningMethodHS256, h.JwtConfig.Secret)
+	render.JSON(rw, req, mapper.MapTokenPairToLoginResponse(pair))
+}
+
+// Refresh godoc
+//
+//	@Summary		Refresh tokens
+//	@Description	Exchange refresh token for new access and refresh tokens, used refresh token becomes invalid.
+//	@Description	Reusing already exchanged refresh token revokes the whole session
+//	@Tags			Auth
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		request.RefreshRequest	true	"refresh token"
+//	@Success		200		{object}	response.LoginResponse
+//	@Failure		400		{string}	invalid		refresh	data	provided
+//	@Failure		401		{string}	invalid		refresh	token
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/auth/refresh [post]
+func (h *Handler) Refresh(rw http.ResponseWriter, req *http.Request) {
+	var refreshReq request.RefreshRequest
+
+	if err := render.DecodeJSON(req.Body, &refreshReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to RefreshRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid refresh data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err := refreshReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating RefreshRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid refresh data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	pair, err := h.AuthService.Refresh(req.Context(), refreshReq.RefreshToken)
 	if err != nil {
-		msg := fmt.Sprintf("error occurred signing jwt token: %v", err)
+		h.writeTokenErr(rw, fmt.Sprintf("error occurred refreshing tokens: %v", err), err)
+		return
+	}
 
-		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+	render.JSON(rw, req, mapper.MapTokenPairToLoginResponse(pair))
+}
+
+// Logout godoc
+//
+//	@Summary		Logout user
+//	@Description	Revoke session of refresh token, access tokens of the session are rejected from now on
+//	@Tags			Auth
+//	@Accept			json
+//	@Param			input	body		request.RefreshRequest	true	"refresh token"
+//	@Success		204
+//	@Failure		400		{string}	invalid		logout	data	provided
+//	@Failure		401		{string}	invalid		refresh	token
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/auth/logout [post]
+func (h *Handler) Logout(rw http.ResponseWriter, req *http.Request) {
+	var logoutReq request.RefreshRequest
+
+	if err := render.DecodeJSON(req.Body, &logoutReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to RefreshRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid logout data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err := logoutReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating RefreshRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid logout data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
 		return
 	}
 
-	// todo: mapper?
-	resp := response.LoginResponse{
-		Token: token,
+	if err := h.AuthService.Logout(req.Context(), logoutReq.RefreshToken); err != nil {
+		h.writeTokenErr(rw, fmt.Sprintf("error occurred logging out: %v", err), err)
+		return
 	}
 
-	render.JSON(rw, req, resp)
-	rw.WriteHeader(http.StatusOK)
+	rw.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) writeTokenErr(rw http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, authservice.ErrInvalidRefreshToken),
+		errors.Is(err, authservice.ErrRefreshTokenExpired),
+		errors.Is(err, authservice.ErrRefreshTokenReused),
+		errors.Is(err, authservice.ErrUserDisabled):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+
+	default:
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+	}
 }
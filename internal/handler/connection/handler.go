@@ -0,0 +1,497 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/mapper"
+	"db-dashboards/internal/handler/request"
+
+	connectionrepo "db-dashboards/internal/repository/connection"
+	connectionservice "db-dashboards/internal/service/connection"
+
+	handlerinternalutils "db-dashboards/internal/handler/utils"
+	handlerutils "db-dashboards/pkg/utils/handler"
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+type Service interface {
+	GetAllConnections(ctx context.Context, userID, offset, limit int) ([]*entity.Connection, error)
+	GetConnectionByID(ctx context.Context, userID, id int) (*entity.Connection, error)
+	CreateConnection(ctx context.Context, conn entity.Connection) (*entity.Connection, error)
+	UpdateConnection(ctx context.Context, userID int, conn entity.Connection) (*entity.Connection, error)
+	DeleteConnection(ctx context.Context, userID, id int) (*entity.Connection, error)
+
+	GetConnectionPermissions(ctx context.Context, userID, id int) ([]*entity.ConnectionPermission, error)
+	SetConnectionPermission(ctx context.Context, userID int, perm entity.ConnectionPermission) (*entity.ConnectionPermission, error)
+	RevokeConnectionPermission(ctx context.Context, userID, id, granteeID int) (*entity.ConnectionPermission, error)
+}
+
+type Middleware = func(http.Handler) http.Handler
+
+type Handler struct {
+	Service     Service
+	Middlewares []Middleware
+
+	logger    *logrus.Logger
+	validator *validator.Validate
+}
+
+func New(service Service,
+	logger *logrus.Logger,
+	validator *validator.Validate,
+	middlewares ...Middleware,
+) *Handler {
+	return &Handler{
+		Service:     service,
+		Middlewares: middlewares,
+		logger:      logger,
+		validator:   validator,
+	}
+}
+
+func (h *Handler) Routes() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(h.Middlewares...)
+
+		r.Get("/", h.GetAll)
+		r.Post("/", h.Create)
+		r.Get("/{id}", h.GetByID)
+		r.Put("/{id}", h.Update)
+		r.Delete("/{id}", h.Delete)
+
+		r.Get("/{id}/permissions", h.GetPermissions)
+		r.Put("/{id}/permissions", h.SetPermission)
+		r.Delete("/{id}/permissions/{user_id}", h.RevokePermission)
+	})
+
+	return router
+}
+
+// GetAll godoc
+//
+//	@Summary		Get all saved connections
+//	@Description	Get all saved connections owned by or shared with current user
+//	@Security		JWT
+//	@Tags			Connection
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			offset	query		int	false	"offset"
+//	@Param			limit	query		int	false	"limit"
+//	@Success		200		{object}	[]response.GetConnectionResponse
+//	@Failure		400		{string}	invalid	pagination	options
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/connections [get]
+func (h *Handler) GetAll(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	paginationOpts := handlerinternalutils.GetPaginationOptsFromQuery(req, handlerutils.DefaultOffset, handlerutils.DefaultLimit)
+
+	if err = paginationOpts.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid pagination options provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	conns, err := h.Service.GetAllConnections(req.Context(), userID, paginationOpts.Offset, paginationOpts.Limit)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred fetching connections: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+		return
+	}
+
+	render.JSON(rw, req, sliceutils.Map(conns, mapper.MapConnectionToConnectionResponse))
+}
+
+// GetByID godoc
+//
+//	@Summary		Get saved connection
+//	@Description	Get saved connection by id
+//	@Security		JWT
+//	@Tags			Connection
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			id	path		int	true	"connection id"
+//	@Success		200	{object}	response.GetConnectionResponse
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		404	{string}	connection	not	found
+//	@Failure		500	{string}	internal	error
+//	@Router			/db-dashboards/api/v1/connections/{id} [get]
+func (h *Handler) GetByID(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid connection id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	conn, err := h.Service.GetConnectionByID(req.Context(), userID, id)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred fetching connection: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapConnectionToConnectionResponse(conn))
+}
+
+// Create godoc
+//
+//	@Summary		Save new connection
+//	@Description	Save new connection, dsn is encrypted at rest and never returned
+//	@Security		JWT
+//	@Tags			Connection
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		request.CreateConnectionRequest	true	"connection info"
+//	@Success		201		{object}	response.GetConnectionResponse
+//	@Failure		400		{string}	invalid		connection	data	provided
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		403		{string}	insufficient	access
+//	@Failure		409		{string}	connection	already		exists
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/connections [post]
+func (h *Handler) Create(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	var createReq request.CreateConnectionRequest
+
+	if err = render.DecodeJSON(req.Body, &createReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to CreateConnectionRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid connection data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err = createReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating CreateConnectionRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid connection data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	conn, err := h.Service.CreateConnection(req.Context(), mapper.MapCreateConnectionRequestToConnectionEntity(&createReq, userID))
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred creating connection: %v", err), err)
+		return
+	}
+
+	render.Status(req, http.StatusCreated)
+	render.JSON(rw, req, mapper.MapConnectionToConnectionResponse(conn))
+}
+
+// Update godoc
+//
+//	@Summary		Update saved connection
+//	@Description	Update name and/or dsn of saved connection
+//	@Security		JWT
+//	@Tags			Connection
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int								true	"connection id"
+//	@Param			input	body		request.UpdateConnectionRequest	true	"connection info"
+//	@Success		200		{object}	response.GetConnectionResponse
+//	@Failure		400		{string}	invalid		connection	data	provided
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		403		{string}	insufficient	access
+//	@Failure		404		{string}	connection	not			found
+//	@Failure		409		{string}	connection	already		exists
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/connections/{id} [put]
+func (h *Handler) Update(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid connection id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	var updateReq request.UpdateConnectionRequest
+
+	if err = render.DecodeJSON(req.Body, &updateReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to UpdateConnectionRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid connection data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err = updateReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating UpdateConnectionRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid connection data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	conn, err := h.Service.UpdateConnection(req.Context(), userID, mapper.MapUpdateConnectionRequestToConnectionEntity(&updateReq, id))
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred updating connection: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapConnectionToConnectionResponse(conn))
+}
+
+// Delete godoc
+//
+//	@Summary		Delete saved connection
+//	@Description	Delete saved connection by id
+//	@Security		JWT
+//	@Tags			Connection
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			id	path		int	true	"connection id"
+//	@Success		200	{object}	response.GetConnectionResponse
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	insufficient	access
+//	@Failure		404	{string}	connection	not	found
+//	@Failure		500	{string}	internal	error
+//	@Router			/db-dashboards/api/v1/connections/{id} [delete]
+func (h *Handler) Delete(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid connection id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	conn, err := h.Service.DeleteConnection(req.Context(), userID, id)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred deleting connection: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapConnectionToConnectionResponse(conn))
+}
+
+// GetPermissions godoc
+//
+//	@Summary		Get connection permissions
+//	@Description	Get users connection is shared with, available to connection owner and admins
+//	@Security		JWT
+//	@Tags			Connection
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			id	path		int	true	"connection id"
+//	@Success		200	{object}	[]response.ConnectionPermissionResponse
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	insufficient	access
+//	@Failure		404	{string}	connection		not	found
+//	@Failure		500	{string}	internal		error
+//	@Router			/db-dashboards/api/v1/connections/{id}/permissions [get]
+func (h *Handler) GetPermissions(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid connection id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	perms, err := h.Service.GetConnectionPermissions(req.Context(), userID, id)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred fetching connection permissions: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, sliceutils.Map(perms, mapper.MapConnectionPermissionToConnectionPermissionResponse))
+}
+
+// SetPermission godoc
+//
+//	@Summary		Share connection
+//	@Description	Grant user access to connection or change access already granted, available to connection owner and admins.
+//	@Description	read_only allows browsing tables, query allows read only sql, write allows any sql
+//	@Security		JWT
+//	@Tags			Connection
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int									true	"connection id"
+//	@Param			input	body		request.ConnectionPermissionRequest	true	"permission"
+//	@Success		200		{object}	response.ConnectionPermissionResponse
+//	@Failure		400		{string}	invalid			permission	data	provided
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		403		{string}	insufficient	access
+//	@Failure		404		{string}	connection		or			user	not	found
+//	@Failure		500		{string}	internal		error
+//	@Router			/db-dashboards/api/v1/connections/{id}/permissions [put]
+func (h *Handler) SetPermission(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid connection id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	var permReq request.ConnectionPermissionRequest
+
+	if err = render.DecodeJSON(req.Body, &permReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to ConnectionPermissionRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid permission data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err = permReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating ConnectionPermissionRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid permission data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	perm, err := h.Service.SetConnectionPermission(req.Context(), userID,
+		mapper.MapConnectionPermissionRequestToConnectionPermissionEntity(&permReq, id))
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred setting connection permission: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapConnectionPermissionToConnectionPermissionResponse(perm))
+}
+
+// RevokePermission godoc
+//
+//	@Summary		Revoke connection access
+//	@Description	Revoke access of user to connection, available to connection owner and admins
+//	@Security		JWT
+//	@Tags			Connection
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			id		path		int	true	"connection id"
+//	@Param			user_id	path		int	true	"id of user to revoke access from"
+//	@Success		200		{object}	response.ConnectionPermissionResponse
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		403		{string}	insufficient	access
+//	@Failure		404		{string}	permission		not	found
+//	@Failure		500		{string}	internal		error
+//	@Router			/db-dashboards/api/v1/connections/{id}/permissions/{user_id} [delete]
+func (h *Handler) RevokePermission(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid connection id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	granteeID, err := handlerutils.GetIntURLParam(req, "user_id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid user id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	perm, err := h.Service.RevokeConnectionPermission(req.Context(), userID, id, granteeID)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred revoking connection permission: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapConnectionPermissionToConnectionPermissionResponse(perm))
+}
+
+func (h *Handler) writeServiceErr(rw http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, connectionrepo.ErrConnectionNotFound),
+		errors.Is(err, connectionrepo.ErrPermissionNotFound),
+		errors.Is(err, connectionrepo.ErrGranteeNotFound):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusNotFound, msg, msg)
+
+	case errors.Is(err, connectionservice.ErrAccessDenied):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusForbidden, msg, msg)
+
+	case errors.Is(err, connectionservice.ErrInvalidAccess), errors.Is(err, connectionservice.ErrGrantToOwner):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+
+	case errors.Is(err, connectionrepo.ErrConnectionNameExists):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusConflict, msg, msg)
+
+	default:
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+	}
+}
@@ -0,0 +1,852 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/mapper"
+	"db-dashboards/internal/handler/request"
+
+	connectionrepo "db-dashboards/internal/repository/connection"
+	dashboardrepo "db-dashboards/internal/repository/dashboard"
+	savedqueryrepo "db-dashboards/internal/repository/savedquery"
+	auditservice "db-dashboards/internal/service/audit"
+	connectionservice "db-dashboards/internal/service/connection"
+	dashboardservice "db-dashboards/internal/service/dashboard"
+	historyservice "db-dashboards/internal/service/history"
+
+	handlerinternalutils "db-dashboards/internal/handler/utils"
+	handlerutils "db-dashboards/pkg/utils/handler"
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+type Service interface {
+	GetAllDashboards(ctx context.Context, userID, offset, limit int) ([]*entity.Dashboard, error)
+	GetDashboardByID(ctx context.Context, userID, id int) (*entity.Dashboard, error)
+	CreateDashboard(ctx context.Context, dashboard entity.Dashboard) (*entity.Dashboard, error)
+	UpdateDashboard(ctx context.Context, userID int, dashboard entity.Dashboard) (*entity.Dashboard, error)
+	DeleteDashboard(ctx context.Context, userID, id int) (*entity.Dashboard, error)
+
+	GetWidgets(ctx context.Context, userID, dashboardID int) ([]*entity.Widget, error)
+	CreateWidget(ctx context.Context, userID int, widget entity.Widget) (*entity.Widget, error)
+	UpdateWidget(ctx context.Context, userID int, widget entity.Widget) (*entity.Widget, error)
+	DeleteWidget(ctx context.Context, userID, dashboardID, id int) (*entity.Widget, error)
+	ReorderWidgets(ctx context.Context, userID, dashboardID int, ids []int) ([]*entity.Widget, error)
+	GetWidgetData(ctx context.Context, userID, dashboardID, id int) (*entity.WidgetData, error)
+
+	GetDashboardPermissions(ctx context.Context, userID, id int) ([]*entity.DashboardPermission, error)
+	SetDashboardPermission(ctx context.Context, userID int, perm entity.DashboardPermission) (*entity.DashboardPermission, error)
+	RevokeDashboardPermission(ctx context.Context, userID, id, granteeID int) (*entity.DashboardPermission, error)
+}
+
+type Middleware = func(http.Handler) http.Handler
+
+type Handler struct {
+	Service     Service
+	Middlewares []Middleware
+
+	logger    *logrus.Logger
+	validator *validator.Validate
+}
+
+func New(service Service,
+	logger *logrus.Logger,
+	validator *validator.Validate,
+	middlewares ...Middleware,
+) *Handler {
+	return &Handler{
+		Service:     service,
+		Middlewares: middlewares,
+		logger:      logger,
+		validator:   validator,
+	}
+}
+
+func (h *Handler) Routes() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(h.Middlewares...)
+
+		r.Get("/", h.GetAll)
+		r.Post("/", h.Create)
+		r.Get("/{id}", h.GetByID)
+		r.Put("/{id}", h.Update)
+		r.Delete("/{id}", h.Delete)
+
+		r.Post("/{id}/widgets", h.CreateWidget)
+		r.Put("/{id}/widgets/order", h.ReorderWidgets)
+		r.Put("/{id}/widgets/{wid}", h.UpdateWidget)
+		r.Delete("/{id}/widgets/{wid}", h.DeleteWidget)
+		r.Get("/{id}/widgets/{wid}/data", h.GetWidgetData)
+
+		r.Get("/{id}/permissions", h.GetPermissions)
+		r.Put("/{id}/permissions", h.SetPermission)
+		r.Delete("/{id}/permissions/{user_id}", h.RevokePermission)
+	})
+
+	return router
+}
+
+// GetAll godoc
+//
+//	@Summary		Get all dashboards
+//	@Description	Get all dashboards owned by or shared with current user without widgets
+//	@Security		JWT
+//	@Tags			Dashboard
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			offset	query		int	false	"offset"
+//	@Param			limit	query		int	false	"limit"
+//	@Success		200		{object}	[]response.GetDashboardResponse
+//	@Failure		400		{string}	invalid	pagination	options
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/dashboards [get]
+func (h *Handler) GetAll(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	paginationOpts := handlerinternalutils.GetPaginationOptsFromQuery(req, handlerutils.DefaultOffset, handlerutils.DefaultLimit)
+
+	if err = paginationOpts.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid pagination options provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	dashboards, err := h.Service.GetAllDashboards(req.Context(), userID, paginationOpts.Offset, paginationOpts.Limit)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred fetching dashboards: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+		return
+	}
+
+	render.JSON(rw, req, sliceutils.Map(dashboards, mapper.MapDashboardToDashboardResponse))
+}
+
+// GetByID godoc
+//
+//	@Summary		Get dashboard
+//	@Description	Get dashboard by id with widgets ordered by position
+//	@Security		JWT
+//	@Tags			Dashboard
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			id	path		int	true	"dashboard id"
+//	@Success		200	{object}	response.GetDashboardWithWidgetsResponse
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		404	{string}	dashboard	not	found
+//	@Failure		500	{string}	internal	error
+//	@Router			/db-dashboards/api/v1/dashboards/{id} [get]
+func (h *Handler) GetByID(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid dashboard id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	dashboard, err := h.Service.GetDashboardByID(req.Context(), userID, id)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred fetching dashboard: %v", err), err)
+		return
+	}
+
+	widgets, err := h.Service.GetWidgets(req.Context(), userID, id)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred fetching widgets: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapDashboardToDashboardWithWidgetsResponse(dashboard, widgets))
+}
+
+// Create godoc
+//
+//	@Summary		Create dashboard
+//	@Description	Create empty dashboard
+//	@Security		JWT
+//	@Tags			Dashboard
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		request.CreateDashboardRequest	true	"dashboard info"
+//	@Success		201		{object}	response.GetDashboardResponse
+//	@Failure		400		{string}	invalid	dashboard	data	provided
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		403		{string}	insufficient	access
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/dashboards [post]
+func (h *Handler) Create(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	var createReq request.CreateDashboardRequest
+
+	if err = render.DecodeJSON(req.Body, &createReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to CreateDashboardRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid dashboard data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err = createReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating CreateDashboardRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid dashboard data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	dashboard, err := h.Service.CreateDashboard(req.Context(), mapper.MapCreateDashboardRequestToDashboardEntity(&createReq, userID))
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred creating dashboard: %v", err), err)
+		return
+	}
+
+	render.Status(req, http.StatusCreated)
+	render.JSON(rw, req, mapper.MapDashboardToDashboardResponse(dashboard))
+}
+
+// Update godoc
+//
+//	@Summary		Update dashboard
+//	@Description	Update title and/or description of dashboard
+//	@Security		JWT
+//	@Tags			Dashboard
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int								true	"dashboard id"
+//	@Param			input	body		request.UpdateDashboardRequest	true	"dashboard info"
+//	@Success		200		{object}	response.GetDashboardResponse
+//	@Failure		400		{string}	invalid		dashboard	data	provided
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		403		{string}	insufficient	access
+//	@Failure		404		{string}	dashboard	not	found
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/dashboards/{id} [put]
+func (h *Handler) Update(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid dashboard id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	var updateReq request.UpdateDashboardRequest
+
+	if err = render.DecodeJSON(req.Body, &updateReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to UpdateDashboardRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid dashboard data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err = updateReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating UpdateDashboardRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid dashboard data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	dashboard, err := h.Service.UpdateDashboard(req.Context(), userID, mapper.MapUpdateDashboardRequestToDashboardEntity(&updateReq, id))
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred updating dashboard: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapDashboardToDashboardResponse(dashboard))
+}
+
+// Delete godoc
+//
+//	@Summary		Delete dashboard
+//	@Description	Delete dashboard by id together with its widgets
+//	@Security		JWT
+//	@Tags			Dashboard
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			id	path		int	true	"dashboard id"
+//	@Success		200	{object}	response.GetDashboardResponse
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	insufficient	access
+//	@Failure		404	{string}	dashboard	not	found
+//	@Failure		500	{string}	internal	error
+//	@Router			/db-dashboards/api/v1/dashboards/{id} [delete]
+func (h *Handler) Delete(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid dashboard id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	dashboard, err := h.Service.DeleteDashboard(req.Context(), userID, id)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred deleting dashboard: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapDashboardToDashboardResponse(dashboard))
+}
+
+// CreateWidget godoc
+//
+//	@Summary		Add widget to dashboard
+//	@Description	Add widget to the end of dashboard. Widget is bound either to saved_query_id or to connection_id with sql
+//	@Security		JWT
+//	@Tags			Dashboard
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int						true	"dashboard id"
+//	@Param			input	body		request.WidgetRequest	true	"widget info"
+//	@Success		201		{object}	response.GetWidgetResponse
+//	@Failure		400		{string}	invalid		widget	data	provided
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		403		{string}	insufficient	access
+//	@Failure		404		{string}	dashboard	not	found
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/dashboards/{id}/widgets [post]
+func (h *Handler) CreateWidget(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	dashboardID, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid dashboard id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	var widgetReq request.WidgetRequest
+
+	if err = render.DecodeJSON(req.Body, &widgetReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to WidgetRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid widget data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err = widgetReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating WidgetRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid widget data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	widget, err := h.Service.CreateWidget(req.Context(), userID, mapper.MapWidgetRequestToWidgetEntity(&widgetReq, dashboardID, 0))
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred creating widget: %v", err), err)
+		return
+	}
+
+	render.Status(req, http.StatusCreated)
+	render.JSON(rw, req, mapper.MapWidgetToWidgetResponse(widget))
+}
+
+// UpdateWidget godoc
+//
+//	@Summary		Update widget
+//	@Description	Replace widget definition, position is kept
+//	@Security		JWT
+//	@Tags			Dashboard
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int						true	"dashboard id"
+//	@Param			wid		path		int						true	"widget id"
+//	@Param			input	body		request.WidgetRequest	true	"widget info"
+//	@Success		200		{object}	response.GetWidgetResponse
+//	@Failure		400		{string}	invalid		widget	data	provided
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		403		{string}	insufficient	access
+//	@Failure		404		{string}	widget	not	found
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/dashboards/{id}/widgets/{wid} [put]
+func (h *Handler) UpdateWidget(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	dashboardID, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid dashboard id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	widgetID, err := handlerutils.GetIntURLParam(req, "wid")
+	if err != nil {
+		msg := fmt.Sprintf("invalid widget id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	var widgetReq request.WidgetRequest
+
+	if err = render.DecodeJSON(req.Body, &widgetReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to WidgetRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid widget data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err = widgetReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating WidgetRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid widget data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	widget, err := h.Service.UpdateWidget(req.Context(), userID, mapper.MapWidgetRequestToWidgetEntity(&widgetReq, dashboardID, widgetID))
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred updating widget: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapWidgetToWidgetResponse(widget))
+}
+
+// DeleteWidget godoc
+//
+//	@Summary		Delete widget
+//	@Description	Delete widget from dashboard
+//	@Security		JWT
+//	@Tags			Dashboard
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			id	path		int	true	"dashboard id"
+//	@Param			wid	path		int	true	"widget id"
+//	@Success		200	{object}	response.GetWidgetResponse
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	insufficient	access
+//	@Failure		404	{string}	widget	not	found
+//	@Failure		500	{string}	internal	error
+//	@Router			/db-dashboards/api/v1/dashboards/{id}/widgets/{wid} [delete]
+func (h *Handler) DeleteWidget(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	dashboardID, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid dashboard id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	widgetID, err := handlerutils.GetIntURLParam(req, "wid")
+	if err != nil {
+		msg := fmt.Sprintf("invalid widget id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	widget, err := h.Service.DeleteWidget(req.Context(), userID, dashboardID, widgetID)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred deleting widget: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapWidgetToWidgetResponse(widget))
+}
+
+// ReorderWidgets godoc
+//
+//	@Summary		Reorder widgets
+//	@Description	Set widget positions by order of ids, every widget of dashboard must be listed exactly once
+//	@Security		JWT
+//	@Tags			Dashboard
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int								true	"dashboard id"
+//	@Param			input	body		request.ReorderWidgetsRequest	true	"widget ids in new order"
+//	@Success		200		{object}	[]response.GetWidgetResponse
+//	@Failure		400		{string}	invalid		widget	order
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		403		{string}	insufficient	access
+//	@Failure		404		{string}	dashboard	not	found
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/dashboards/{id}/widgets/order [put]
+func (h *Handler) ReorderWidgets(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	dashboardID, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid dashboard id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	var reorderReq request.ReorderWidgetsRequest
+
+	if err = render.DecodeJSON(req.Body, &reorderReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to ReorderWidgetsRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid widget order provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err = reorderReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating ReorderWidgetsRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid widget order provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	widgets, err := h.Service.ReorderWidgets(req.Context(), userID, dashboardID, reorderReq.WidgetIDs)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred reordering widgets: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, sliceutils.Map(widgets, mapper.MapWidgetToWidgetResponse))
+}
+
+// GetWidgetData godoc
+//
+//	@Summary		Get widget data
+//	@Description	Execute widget query and return rows for table widgets or chart series for other widget types.
+//	@Description	Unset x/y columns are picked by column types, timestamp x column can be bucketed by time_bucket option.
+//	@Security		JWT
+//	@Tags			Dashboard
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			id	path		int	true	"dashboard id"
+//	@Param			wid	path		int	true	"widget id"
+//	@Success		200	{object}	response.WidgetDataResponse
+//	@Failure		400	{string}	invalid	widget	query	or	options
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	insufficient	access
+//	@Failure		404	{string}	widget	not	found
+//	@Failure		502	{string}	cannot	connect	to	database
+//	@Failure		504	{string}	query	timed	out
+//	@Router			/db-dashboards/api/v1/dashboards/{id}/widgets/{wid}/data [get]
+func (h *Handler) GetWidgetData(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	dashboardID, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid dashboard id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	widgetID, err := handlerutils.GetIntURLParam(req, "wid")
+	if err != nil {
+		msg := fmt.Sprintf("invalid widget id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	data, err := h.Service.GetWidgetData(req.Context(), userID, dashboardID, widgetID)
+
+	switch {
+	case errors.Is(err, historyservice.ErrNotRecorded):
+		h.logger.WithError(err).Errorf("can't record query to history")
+
+	case err != nil:
+		h.writeExecuteErr(rw, fmt.Sprintf("cannot get widget data: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapWidgetDataToWidgetDataResponse(data))
+}
+
+// GetPermissions godoc
+//
+//	@Summary		Get dashboard permissions
+//	@Description	Get users dashboard is shared with, requires own access to dashboard
+//	@Security		JWT
+//	@Tags			Dashboard
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			id	path		int	true	"dashboard id"
+//	@Success		200	{object}	[]response.DashboardPermissionResponse
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	insufficient	access
+//	@Failure		404	{string}	dashboard		not	found
+//	@Failure		500	{string}	internal		error
+//	@Router			/db-dashboards/api/v1/dashboards/{id}/permissions [get]
+func (h *Handler) GetPermissions(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid dashboard id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	perms, err := h.Service.GetDashboardPermissions(req.Context(), userID, id)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred fetching dashboard permissions: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, sliceutils.Map(perms, mapper.MapDashboardPermissionToDashboardPermissionResponse))
+}
+
+// SetPermission godoc
+//
+//	@Summary		Share dashboard
+//	@Description	Grant user access to dashboard or change access already granted, requires own access to dashboard.
+//	@Description	view allows viewing widgets data, edit allows changing dashboard and widgets, own allows deleting and sharing dashboard.
+//	@Description	Widget data is fetched on behalf of dashboard owner
+//	@Security		JWT
+//	@Tags			Dashboard
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int									true	"dashboard id"
+//	@Param			input	body		request.DashboardPermissionRequest	true	"permission"
+//	@Success		200		{object}	response.DashboardPermissionResponse
+//	@Failure		400		{string}	invalid			permission	data	provided
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		403		{string}	insufficient	access
+//	@Failure		404		{string}	dashboard		or			user	not	found
+//	@Failure		500		{string}	internal		error
+//	@Router			/db-dashboards/api/v1/dashboards/{id}/permissions [put]
+func (h *Handler) SetPermission(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid dashboard id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	var permReq request.DashboardPermissionRequest
+
+	if err = render.DecodeJSON(req.Body, &permReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to DashboardPermissionRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid permission data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err = permReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating DashboardPermissionRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid permission data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	perm, err := h.Service.SetDashboardPermission(req.Context(), userID,
+		mapper.MapDashboardPermissionRequestToDashboardPermissionEntity(&permReq, id))
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred setting dashboard permission: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapDashboardPermissionToDashboardPermissionResponse(perm))
+}
+
+// RevokePermission godoc
+//
+//	@Summary		Revoke dashboard access
+//	@Description	Revoke access of user to dashboard, requires own access to dashboard
+//	@Security		JWT
+//	@Tags			Dashboard
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			id		path		int	true	"dashboard id"
+//	@Param			user_id	path		int	true	"id of user to revoke access from"
+//	@Success		200		{object}	response.DashboardPermissionResponse
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		403		{string}	insufficient	access
+//	@Failure		404		{string}	permission		not	found
+//	@Failure		500		{string}	internal		error
+//	@Router			/db-dashboards/api/v1/dashboards/{id}/permissions/{user_id} [delete]
+func (h *Handler) RevokePermission(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid dashboard id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	granteeID, err := handlerutils.GetIntURLParam(req, "user_id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid user id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	perm, err := h.Service.RevokeDashboardPermission(req.Context(), userID, id, granteeID)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred revoking dashboard permission: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapDashboardPermissionToDashboardPermissionResponse(perm))
+}
+
+func (h *Handler) writeServiceErr(rw http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, dashboardrepo.ErrDashboardNotFound),
+		errors.Is(err, dashboardrepo.ErrWidgetNotFound),
+		errors.Is(err, dashboardrepo.ErrPermissionNotFound),
+		errors.Is(err, dashboardrepo.ErrGranteeNotFound):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusNotFound, msg, msg)
+
+	case errors.Is(err, dashboardservice.ErrAccessDenied), errors.Is(err, connectionservice.ErrAccessDenied):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusForbidden, msg, msg)
+
+	case errors.Is(err, dashboardservice.ErrInvalidAccess), errors.Is(err, dashboardservice.ErrGrantToOwner):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+
+	// widget bound to query or connection of other user
+	case errors.Is(err, savedqueryrepo.ErrSavedQueryNotFound),
+		errors.Is(err, connectionrepo.ErrConnectionNotFound),
+		errors.Is(err, dashboardservice.ErrInvalidWidgetBinding),
+		errors.Is(err, dashboardservice.ErrInvalidWidgetOrder):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+
+	default:
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+	}
+}
+
+// writeExecuteErr treats errors returned by target database as client errors
+func (h *Handler) writeExecuteErr(rw http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, dashboardrepo.ErrDashboardNotFound), errors.Is(err, dashboardrepo.ErrWidgetNotFound):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusNotFound, msg, msg)
+
+	case errors.Is(err, connectionservice.ErrAccessDenied):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusForbidden, msg, msg)
+
+	case errors.Is(err, connectionservice.ErrCannotConnect):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadGateway, msg, msg)
+
+	case errors.Is(err, context.DeadlineExceeded):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusGatewayTimeout, msg, msg)
+
+	case errors.Is(err, auditservice.ErrAuditFailed):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+
+	default:
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+	}
+}
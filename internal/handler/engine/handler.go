@@ -0,0 +1,716 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/export"
+	"db-dashboards/internal/handler/mapper"
+	"db-dashboards/internal/handler/request"
+
+	connectionrepo "db-dashboards/internal/repository/connection"
+	enginerepo "db-dashboards/internal/repository/engine"
+	auditservice "db-dashboards/internal/service/audit"
+	connectionservice "db-dashboards/internal/service/connection"
+	engineservice "db-dashboards/internal/service/engine"
+	historyservice "db-dashboards/internal/service/history"
+
+	handlerinternalutils "db-dashboards/internal/handler/utils"
+	handlerutils "db-dashboards/pkg/utils/handler"
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+type Service interface {
+	GetAllSchemas(ctx context.Context, repo enginerepo.Repo) ([]string, error)
+	GetAllTables(ctx context.Context, repo enginerepo.Repo, schema string) ([]*entity.Table, error)
+	GetColumnsFromTable(ctx context.Context, repo enginerepo.Repo, schema, tableName string) ([]*entity.Column, error)
+	GetRowsFromTable(ctx context.Context, repo enginerepo.Repo, q entity.RowsQuery) (*entity.RowsPage, error)
+	StreamRowsFromTable(ctx context.Context, repo enginerepo.Repo, q entity.RowsQuery, w enginerepo.RowWriter) (*enginerepo.StreamResult, error)
+	ExecuteAggregate(ctx context.Context, repo enginerepo.Repo, q entity.AggregateQuery) (*entity.AggregateResult, error)
+	ImportRows(ctx context.Context, repo enginerepo.Repo, imp entity.Import, file io.Reader) (*entity.ImportResult, error)
+}
+
+type ConnectionService interface {
+	OpenConnection(ctx context.Context, userID, id int, need entity.ConnectionAccess) (*entity.Connection, enginerepo.Repo, error)
+}
+
+// HistoryService runs query console queries and records them to history of user
+type HistoryService interface {
+	Execute(ctx context.Context,
+		userID int,
+		conn *entity.Connection,
+		repo enginerepo.Repo,
+		query string,
+		params []any,
+		allowWrite bool,
+	) (*entity.QueryResult, error)
+	Stream(ctx context.Context,
+		userID int,
+		conn *entity.Connection,
+		repo enginerepo.Repo,
+		query string,
+		params []any,
+		allowWrite bool,
+		w enginerepo.RowWriter,
+	) (*enginerepo.StreamResult, error)
+}
+
+type Middleware = func(http.Handler) http.Handler
+
+type Handler struct {
+	Service           Service
+	ConnectionService ConnectionService
+	HistoryService    HistoryService
+	Registry          *enginerepo.Registry
+	Middlewares       []Middleware
+
+	logger    *logrus.Logger
+	validator *validator.Validate
+}
+
+func New(service Service,
+	connectionService ConnectionService,
+	historyService HistoryService,
+	registry *enginerepo.Registry,
+	logger *logrus.Logger,
+	validator *validator.Validate,
+	middlewares ...Middleware,
+) *Handler {
+	return &Handler{
+		Service:           service,
+		ConnectionService: connectionService,
+		HistoryService:    historyService,
+		Registry:          registry,
+		Middlewares:       middlewares,
+		logger:            logger,
+		validator:         validator,
+	}
+}
+
+// Routes are expected to be mounted under path with {engine} url param
+func (h *Handler) Routes() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(h.Middlewares...)
+
+		r.Get("/schemas", h.GetAllSchemas)
+		r.Get("/tables", h.GetAllTables)
+		r.Get("/columns", h.GetColumnsFromTable)
+		r.Get("/data", h.GetRowsFromTable)
+		r.Post("/query", h.ExecuteQuery)
+		r.Post("/aggregate", h.ExecuteAggregate)
+		r.Post("/tables/{table}/import", h.ImportRows)
+	})
+
+	return router
+}
+
+// GetAllSchemas godoc
+//
+//	@Summary		Get all schemas from db
+//	@Description	Get all user schemas from db, for mysql schemas are databases
+//	@Security		JWT
+//	@Tags			Database
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Param			engine			path	string	true	"database engine"	Enums(postgres, mysql, sqlite)
+//	@Param			connection_id	query	int		true	"saved connection id"
+//	@Produce		json
+//	@Success		200	{object}	[]string
+//	@Failure		400	{string}	invalid	request
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	insufficient	access
+//	@Failure		404	{string}	connection	not	found
+//	@Failure		500	{string}	internal	error
+//	@Failure		502	{string}	database	error
+//	@Router			/db-dashboards/api/v1/{engine}/schemas [get]
+func (h *Handler) GetAllSchemas(rw http.ResponseWriter, req *http.Request) {
+	_, repo, ok := h.repoFromRequest(rw, req, entity.ConnectionAccessReadOnly)
+	if !ok {
+		return
+	}
+
+	schemas, err := h.Service.GetAllSchemas(req.Context(), repo)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("cannot fetch schemas from db: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, schemas)
+}
+
+// GetAllTables godoc
+//
+//	@Summary		Get all tables from db
+//	@Description	Get all tables from db
+//	@Security		JWT
+//	@Tags			Database
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Param			engine			path	string	true	"database engine"	Enums(postgres, mysql, sqlite)
+//	@Param			connection_id	query	int		true	"saved connection id"
+//	@Param			schema			query	string	false	"schema, default schema of connection if omitted"
+//	@Produce		json
+//	@Success		200	{object}	[]response.GetTableResponse
+//	@Failure		400	{string}	invalid	request
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	insufficient	access
+//	@Failure		404	{string}	connection	not	found
+//	@Failure		500	{string}	internal	error
+//	@Failure		502	{string}	database	error
+//	@Router			/db-dashboards/api/v1/{engine}/tables [get]
+func (h *Handler) GetAllTables(rw http.ResponseWriter, req *http.Request) {
+	_, repo, ok := h.repoFromRequest(rw, req, entity.ConnectionAccessReadOnly)
+	if !ok {
+		return
+	}
+
+	schema, _ := handlerutils.GetStringParamFromQuery(req, "schema")
+
+	tables, err := h.Service.GetAllTables(req.Context(), repo, schema)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("cannot fetch tables from db: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, sliceutils.Map(tables, mapper.MapTableToTableResponse))
+}
+
+// GetColumnsFromTable godoc
+//
+//	@Summary		Get all columns from table
+//	@Description	Get all columns from table
+//	@Security		JWT
+//	@Tags			Database
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Param			engine			path	string	true	"database engine"	Enums(postgres, mysql, sqlite)
+//	@Param			connection_id	query	int		true	"saved connection id"
+//	@Param			schema			query	string	false	"schema, default schema of connection if omitted"
+//	@Param			table-name		header	string	true	"name of the table"
+//	@Produce		json
+//	@Success		200	{object}	[]response.GetColumnsResponse
+//	@Failure		400	{string}	invalid	request
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	insufficient	access
+//	@Failure		404	{string}	connection	or	table	not	found
+//	@Failure		500	{string}	internal	error
+//	@Failure		502	{string}	database	error
+//	@Router			/db-dashboards/api/v1/{engine}/columns [get]
+func (h *Handler) GetColumnsFromTable(rw http.ResponseWriter, req *http.Request) {
+	_, repo, ok := h.repoFromRequest(rw, req, entity.ConnectionAccessReadOnly)
+	if !ok {
+		return
+	}
+
+	tableName, err := handlerutils.GetStringHeaderByKey(req, "table-name")
+	if err != nil {
+		msg := "no table name header provided"
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	schema, _ := handlerutils.GetStringParamFromQuery(req, "schema")
+
+	columns, err := h.Service.GetColumnsFromTable(req.Context(), repo, schema, tableName)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("cannot fetch columns from db: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, sliceutils.Map(columns, mapper.MapColumnToColumnResponse))
+}
+
+// GetRowsFromTable godoc
+//
+//	@Summary		Get data from table
+//	@Description	Get page of rows from table with optional sort, filters and total count.
+//	@Description	Rows are arrays of values ordered as columns, integers and decimals beyond float precision are strings,
+//	@Description	binary values are hex literals, json values are embedded and postgres arrays are json arrays.
+//	@Description	Filter format is column:op[:value], op is one of eq, ne, lt, gt, like, in, is_null, not_null,
+//	@Description	value of in is comma separated list. Sort is comma separated list of columns, prefix column with - for descending order.
+//	@Description	Next cursor is returned when rows are sorted by not null columns of table with primary key and may be passed
+//	@Description	instead of offset to fetch next page, primary key columns are appended to sort to order rows uniquely.
+//	@Description	When format param is provided or Accept header is one of export media types rows are streamed as they are read
+//	@Description	without paging up to server stream limit, row count and truncation are reported in X-Row-Count and X-Rows-Truncated trailers.
+//	@Description	Json export is {"columns": [...], "rows": [[...]]} document, ndjson has {"columns": [...]} on the first line and a row array per line.
+//	@Security		JWT
+//	@Tags			Database
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Param			engine			path	string		true	"database engine"	Enums(postgres, mysql, sqlite)
+//	@Param			connection_id	query	int			true	"saved connection id"
+//	@Param			schema			query	string		false	"schema, default schema of connection if omitted"
+//	@Param			table-name		header	string		true	"name of the table"
+//	@Param			offset			query	int			false	"offset"
+//	@Param			limit			query	int			false	"limit"
+//	@Param			cursor			query	string		false	"next_cursor of previous page"
+//	@Param			sort			query	string		false	"sort, e.g. -created_at,id"
+//	@Param			filter			query	[]string	false	"filters, e.g. age:gt:30"	collectionFormat(multi)
+//	@Param			count			query	string		false	"total count mode"	Enums(none, exact, estimated)
+//	@Param			format			query	string		false	"stream rows in export format"	Enums(json, ndjson, csv, xlsx, parquet)
+//	@Param			delimiter		query	string		false	"csv delimiter, comma by default"
+//	@Param			null			query	string		false	"csv rendering of NULL, empty by default"
+//	@Produce		json
+//	@Produce		application/x-ndjson
+//	@Produce		text/csv
+//	@Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Produce		application/vnd.apache.parquet
+//	@Success		200	{object}	response.GetRowsResponse
+//	@Failure		400	{string}	invalid	request
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	insufficient	access
+//	@Failure		404	{string}	connection	or	table	not	found
+//	@Failure		500	{string}	internal	error
+//	@Failure		502	{string}	database	error
+//	@Router			/db-dashboards/api/v1/{engine}/data [get]
+func (h *Handler) GetRowsFromTable(rw http.ResponseWriter, req *http.Request) {
+	_, repo, ok := h.repoFromRequest(rw, req, entity.ConnectionAccessReadOnly)
+	if !ok {
+		return
+	}
+
+	tableName, err := handlerutils.GetStringHeaderByKey(req, "table-name")
+	if err != nil {
+		msg := "no table name header provided"
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	if format, ok := handlerinternalutils.GetExportFormat(req); ok {
+		h.streamRowsFromTable(rw, req, repo, tableName, format)
+		return
+	}
+
+	getReq := handlerinternalutils.GetRowsRequestFromQuery(req, tableName)
+
+	if err = getReq.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid rows query provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	rowsQuery, err := mapper.MapGetRowsRequestToRowsQuery(&getReq)
+	if err != nil {
+		msg := fmt.Sprintf("invalid rows query provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	page, err := h.Service.GetRowsFromTable(req.Context(), repo, rowsQuery)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("cannot fetch rows from db: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapRowsPageToGetRowsResponse(page))
+}
+
+func (h *Handler) streamRowsFromTable(rw http.ResponseWriter, req *http.Request, repo enginerepo.Repo, tableName, format string) {
+	streamReq := handlerinternalutils.GetStreamRowsRequestFromQuery(req, tableName, format)
+
+	if err := streamReq.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid rows query provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	rowsQuery, err := mapper.MapStreamRowsRequestToRowsQuery(&streamReq)
+	if err != nil {
+		msg := fmt.Sprintf("invalid rows query provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	exportFormat, opts := mapper.MapExportRequestToExportOptions(&streamReq.ExportRequest)
+
+	err = export.Serve(rw, h.logger, exportFormat, opts, func(w enginerepo.RowWriter) (*enginerepo.StreamResult, error) {
+		return h.Service.StreamRowsFromTable(req.Context(), repo, rowsQuery, w)
+	})
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("cannot stream rows from db: %v", err), err)
+	}
+}
+
+func (h *Handler) streamQuery(rw http.ResponseWriter,
+	req *http.Request,
+	userID int,
+	conn *entity.Connection,
+	repo enginerepo.Repo,
+	query string,
+	params []any,
+	format string,
+) {
+	exportReq := handlerinternalutils.GetExportRequestFromQuery(req, format)
+
+	if err := exportReq.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid export options provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	exportFormat, opts := mapper.MapExportRequestToExportOptions(&exportReq)
+
+	err := export.Serve(rw, h.logger, exportFormat, opts, func(w enginerepo.RowWriter) (*enginerepo.StreamResult, error) {
+		result, err := h.HistoryService.Stream(req.Context(), userID, conn, repo, query, params, conn.Access.Allows(entity.ConnectionAccessWrite), w)
+		if errors.Is(err, historyservice.ErrNotRecorded) {
+			h.logger.WithError(err).Errorf("can't record query to history")
+			return result, nil
+		}
+
+		return result, err
+	})
+	if err != nil {
+		h.writeExecuteErr(rw, fmt.Sprintf("cannot execute query: %v", err), err)
+	}
+}
+
+// ExecuteQuery godoc
+//
+//	@Summary		Execute sql query
+//	@Description	Execute single sql statement with positional bind params in read only transaction with statement timeout.
+//	@Description	Editors and admins with write access to connection may run several statements in one read write transaction,
+//	@Description	params are bound to the last statement and its result is returned. Result is truncated to server row limit.
+//	@Description	Query is recorded to query history of user. When format param is provided or Accept header is one of
+//	@Description	export media types result is streamed as it is read up to server stream limit, see data endpoint.
+//	@Security		JWT
+//	@Tags			Database
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Param			engine			path	string	true	"database engine"	Enums(postgres, mysql, sqlite)
+//	@Param			connection_id	query	int		true	"saved connection id"
+//	@Param			input			body	request.ExecuteQueryRequest	true	"query"
+//	@Param			format			query	string	false	"stream result in export format"	Enums(json, ndjson, csv, xlsx, parquet)
+//	@Param			delimiter		query	string	false	"csv delimiter, comma by default"
+//	@Param			null			query	string	false	"csv rendering of NULL, empty by default"
+//	@Accept			json
+//	@Produce		json
+//	@Produce		application/x-ndjson
+//	@Produce		text/csv
+//	@Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Produce		application/vnd.apache.parquet
+//	@Success		200	{object}	response.QueryResultResponse
+//	@Failure		400	{string}	invalid	query
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	insufficient	access
+//	@Failure		404	{string}	connection	not	found
+//	@Failure		500	{string}	internal	error
+//	@Failure		502	{string}	database	error
+//	@Failure		504	{string}	query	timed	out
+//	@Router			/db-dashboards/api/v1/{engine}/query [post]
+func (h *Handler) ExecuteQuery(rw http.ResponseWriter, req *http.Request) {
+	var queryReq request.ExecuteQueryRequest
+
+	// params are recorded to history and replayed, integers must not pass through float64
+	if err := handlerutils.DecodeJSONNumbers(req.Body, &queryReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to ExecuteQueryRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid query provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err := queryReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating ExecuteQueryRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid query provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	conn, repo, ok := h.repoFromRequest(rw, req, entity.ConnectionAccessQuery)
+	if !ok {
+		return
+	}
+
+	params := mapper.MapQueryParams(queryReq.Params)
+
+	if format, ok := handlerinternalutils.GetExportFormat(req); ok {
+		h.streamQuery(rw, req, userID, conn, repo, queryReq.SQL, params, format)
+		return
+	}
+
+	result, err := h.HistoryService.Execute(req.Context(), userID, conn, repo, queryReq.SQL, params, conn.Access.Allows(entity.ConnectionAccessWrite))
+
+	switch {
+	case errors.Is(err, historyservice.ErrNotRecorded):
+		h.logger.WithError(err).Errorf("can't record query to history")
+
+	case err != nil:
+		h.writeExecuteErr(rw, fmt.Sprintf("cannot execute query: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapQueryResultToQueryResultResponse(result))
+}
+
+// ExecuteAggregate godoc
+//
+//	@Summary		Execute no-code aggregate query
+//	@Description	Compile aggregate spec (dimensions, measures, time grain, filters, order, limit) into engine sql,
+//	@Description	validated against table columns, and execute it in read only transaction. Generated sql is returned with results.
+//	@Security		JWT
+//	@Tags			Database
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Param			engine			path	string	true	"database engine"	Enums(postgres, mysql, sqlite)
+//	@Param			connection_id	query	int		true	"saved connection id"
+//	@Param			input			body	request.AggregateRequest	true	"aggregate spec"
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	response.AggregateResponse
+//	@Failure		400	{string}	invalid	aggregate
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	insufficient	access
+//	@Failure		404	{string}	table	not	found
+//	@Failure		500	{string}	internal	error
+//	@Failure		502	{string}	database	error
+//	@Failure		504	{string}	query	timed	out
+//	@Router			/db-dashboards/api/v1/{engine}/aggregate [post]
+func (h *Handler) ExecuteAggregate(rw http.ResponseWriter, req *http.Request) {
+	var aggReq request.AggregateRequest
+
+	if err := render.DecodeJSON(req.Body, &aggReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to AggregateRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid aggregate provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err := aggReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating AggregateRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid aggregate provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	_, repo, ok := h.repoFromRequest(rw, req, entity.ConnectionAccessReadOnly)
+	if !ok {
+		return
+	}
+
+	result, err := h.Service.ExecuteAggregate(req.Context(), repo, mapper.MapAggregateRequestToAggregateQuery(&aggReq))
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("cannot execute aggregate: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapAggregateResultToAggregateResponse(result))
+}
+
+// ImportRows godoc
+//
+//	@Summary		Import rows into table
+//	@Description	Insert rows of uploaded csv or ndjson file into table in one transaction, with COPY FROM for postgres
+//	@Description	and batched inserts for other engines. File columns are matched to table columns by name,
+//	@Description	mapping param file_column:table_column imports only mapped columns. Values are converted to column types,
+//	@Description	csv header is required and values equal to null param are NULL. Nothing is inserted if any row is invalid,
+//	@Description	then 422 is returned with errors of invalid rows. Dry run validates rows and rolls back their insert.
+//	@Security		JWT
+//	@Tags			Database
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Param			engine			path	string		true	"database engine"	Enums(postgres, mysql, sqlite)
+//	@Param			table			path	string		true	"name of the table"
+//	@Param			connection_id	query	int			true	"saved connection id"
+//	@Param			schema			query	string		false	"schema, default schema of connection if omitted"
+//	@Param			format			query	string		false	"file format, Content-Type of request if omitted"	Enums(csv, ndjson)
+//	@Param			delimiter		query	string		false	"csv delimiter, comma by default"
+//	@Param			null			query	string		false	"csv value read as NULL, empty by default"
+//	@Param			mapping			query	[]string	false	"file_column:table_column"	collectionFormat(multi)
+//	@Param			dry_run			query	bool		false	"validate rows without inserting them"
+//	@Param			file			body	string		true	"csv or ndjson file"
+//	@Accept			text/csv
+//	@Accept			application/x-ndjson
+//	@Produce		json
+//	@Success		200	{object}	response.ImportResultResponse
+//	@Failure		400	{string}	invalid	import
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	insufficient	access
+//	@Failure		404	{string}	table	not	found
+//	@Failure		413	{string}	file	too	large
+//	@Failure		422	{object}	response.ImportResultResponse
+//	@Failure		500	{string}	internal	error
+//	@Failure		502	{string}	database	error
+//	@Failure		504	{string}	query	timed	out
+//	@Router			/db-dashboards/api/v1/{engine}/tables/{table}/import [post]
+func (h *Handler) ImportRows(rw http.ResponseWriter, req *http.Request) {
+	importReq := handlerinternalutils.GetImportRequestFromQuery(req, chi.URLParam(req, "table"))
+
+	if err := importReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating ImportRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid import provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	imp, err := mapper.MapImportRequestToImport(&importReq)
+	if err != nil {
+		msg := fmt.Sprintf("invalid import provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	_, repo, ok := h.repoFromRequest(rw, req, entity.ConnectionAccessWrite)
+	if !ok {
+		return
+	}
+
+	result, err := h.Service.ImportRows(req.Context(), repo, imp, req.Body)
+	if errors.Is(err, auditservice.ErrAuditFailed) && result != nil {
+		h.logger.WithError(err).Errorf("can't record import to audit log")
+		err = nil
+	}
+
+	if err != nil {
+		h.writeExecuteErr(rw, fmt.Sprintf("cannot import rows: %v", err), err)
+		return
+	}
+
+	if result.InvalidRows > 0 && !result.DryRun {
+		render.Status(req, http.StatusUnprocessableEntity)
+	}
+
+	render.JSON(rw, req, mapper.MapImportResultToImportResultResponse(result))
+}
+
+// repoFromRequest resolves engine url param and saved connection from connection_id query param
+// the user has at least need access to, writes error response on failure
+func (h *Handler) repoFromRequest(rw http.ResponseWriter, req *http.Request, need entity.ConnectionAccess) (*entity.Connection, enginerepo.Repo, bool) {
+	driver, err := h.Registry.Get(chi.URLParam(req, "engine"))
+	if err != nil {
+		msg := fmt.Sprintf("unsupported engine %q, supported: %v", chi.URLParam(req, "engine"), h.Registry.Names())
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusNotFound, msg, msg)
+		return nil, nil, false
+	}
+
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return nil, nil, false
+	}
+
+	connID, err := handlerutils.GetIntParamFromQuery(req, "connection_id")
+	if err != nil {
+		msg := "no valid connection_id query param provided"
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return nil, nil, false
+	}
+
+	conn, repo, err := h.ConnectionService.OpenConnection(req.Context(), userID, connID, need)
+	if err != nil {
+		msg := fmt.Sprintf("cannot open connection %v: %v", connID, err)
+
+		switch {
+		case errors.Is(err, connectionrepo.ErrConnectionNotFound):
+			handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusNotFound, msg, msg)
+
+		case errors.Is(err, connectionservice.ErrAccessDenied):
+			handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusForbidden, msg, msg)
+
+		case errors.Is(err, connectionservice.ErrInvalidDSN):
+			handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+
+		case errors.Is(err, connectionservice.ErrCannotConnect):
+			handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadGateway, msg, msg)
+
+		default:
+			handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+		}
+
+		return nil, nil, false
+	}
+
+	if conn.Engine != driver.Name() {
+		msg := fmt.Sprintf("connection %v is not a %v connection", connID, driver.Name())
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return nil, nil, false
+	}
+
+	return conn, repo, true
+}
+
+func (h *Handler) writeServiceErr(rw http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, enginerepo.ErrTableNotFound):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusNotFound, msg, msg)
+
+	case errors.Is(err, context.DeadlineExceeded):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusGatewayTimeout, msg, msg)
+
+	case errors.Is(err, auditservice.ErrAuditFailed):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+
+	case errors.Is(err, engineservice.ErrImportTooLarge), errors.Is(err, engineservice.ErrTooManyImportRows):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusRequestEntityTooLarge, msg, msg)
+
+	case errors.Is(err, enginerepo.ErrUnknownColumn),
+		errors.Is(err, engineservice.ErrInvalidCursor),
+		errors.Is(err, engineservice.ErrCursorRequiresSort),
+		errors.Is(err, engineservice.ErrCursorWithOffset),
+		errors.Is(err, engineservice.ErrCursorUnsupported),
+		errors.Is(err, engineservice.ErrInvalidFilterValues),
+		errors.Is(err, engineservice.ErrEmptyQuery),
+		errors.Is(err, engineservice.ErrMultipleStatements),
+		errors.Is(err, engineservice.ErrEmptyAggregate),
+		errors.Is(err, engineservice.ErrMeasureRequiresColumn),
+		errors.Is(err, engineservice.ErrMeasureRequiresNumeric),
+		errors.Is(err, engineservice.ErrTimeGrainRequiresTemporal),
+		errors.Is(err, engineservice.ErrDuplicateOutputColumn),
+		errors.Is(err, engineservice.ErrUnknownOrderColumn),
+		errors.Is(err, engineservice.ErrUnsupportedImportFormat),
+		errors.Is(err, engineservice.ErrInvalidImportFile),
+		errors.Is(err, engineservice.ErrDuplicateImportColumn),
+		errors.Is(err, export.ErrUnsupportedFormat),
+		errors.Is(err, export.ErrInvalidDelimiter),
+		errors.Is(err, export.ErrNoColumns):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+
+	case enginerepo.IsStatementError(err), enginerepo.IsConnectionError(err):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadGateway, msg, msg)
+
+	default:
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+	}
+}
+
+// writeExecuteErr treats errors raised by target database for sql or rows sent by client as client errors
+func (h *Handler) writeExecuteErr(rw http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, auditservice.ErrAuditFailed), errors.Is(err, context.DeadlineExceeded):
+		h.writeServiceErr(rw, msg, err)
+
+	case enginerepo.IsStatementError(err):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+
+	default:
+		h.writeServiceErr(rw, msg, err)
+	}
+}
@@ -0,0 +1,144 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+var ErrInvalidDelimiter = errors.New("invalid csv delimiter")
+
+// csvWriter writes RFC 4180 csv with header row of column names
+type csvWriter struct {
+	csv    *csv.Writer
+	null   string
+	kinds  []valueKind
+	record []string
+}
+
+func newCSVWriter(w io.Writer, opts Options) (*csvWriter, error) {
+	writer := csv.NewWriter(w)
+
+	if opts.Delimiter != 0 {
+		// quotes and line breaks would make fields ambiguous
+		if opts.Delimiter == '"' || opts.Delimiter == '\r' || opts.Delimiter == '\n' || !utf8.ValidRune(opts.Delimiter) {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidDelimiter, opts.Delimiter)
+		}
+
+		writer.Comma = opts.Delimiter
+	}
+
+	// RFC 4180 lines end with CRLF
+	writer.UseCRLF = true
+
+	return &csvWriter{
+		csv:  writer,
+		null: opts.Null,
+	}, nil
+}
+
+func (w *csvWriter) WriteColumns(columns []entity.QueryColumn) error {
+	w.record = make([]string, len(columns))
+	w.kinds = make([]valueKind, len(columns))
+
+	for i, column := range columns {
+		w.record[i] = column.Name
+		w.kinds[i] = columnKindOf(column.Type).kind
+	}
+
+	return w.csv.Write(w.record)
+}
+
+func (w *csvWriter) WriteRow(row []any) error {
+	for i, val := range row {
+		field, err := w.field(w.kinds[i], val)
+		if err != nil {
+			return err
+		}
+
+		w.record[i] = field
+	}
+
+	return w.csv.Write(w.record)
+}
+
+func (w *csvWriter) Close() error {
+	w.csv.Flush()
+
+	return w.csv.Error()
+}
+
+func (w *csvWriter) field(kind valueKind, val any) (string, error) {
+	if val == nil {
+		return w.null, nil
+	}
+
+	return formatValue(kind, val)
+}
+
+// formatValue renders value of column kind as text the same way regardless of engine, as JSONEncoder does:
+// binary values are hex literals even when driver returns them as strings, dates have no time and
+// timestamps without time zone have no offset
+func formatValue(kind valueKind, val any) (string, error) {
+	switch kind {
+	case valueBytes, valueDate, valueTimestamp, valueUUID:
+		if text, ok := jsonValue(kind, val).(string); ok {
+			return text, nil
+		}
+	}
+
+	return formatText(val)
+}
+
+// formatText renders value as text, bytes which are not valid text are written as hex literal
+// and structured values as json
+func formatText(val any) (string, error) {
+	switch v := val.(type) {
+	case string:
+		return v, nil
+
+	case []byte:
+		if utf8.Valid(v) {
+			return string(v), nil
+		}
+
+		return `\x` + hex.EncodeToString(v), nil
+
+	case bool:
+		return strconv.FormatBool(v), nil
+
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+
+	case fmt.Stringer:
+		return v.String(), nil
+
+	case map[string]any, []any:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+
+		return string(b), nil
+
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
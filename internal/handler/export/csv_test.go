@@ -0,0 +1,128 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+func TestCSVWriter(t *testing.T) {
+	at := time.Date(2024, 5, 30, 10, 24, 15, 500000000, time.UTC)
+
+	tests := []struct {
+		name    string
+		opts    Options
+		columns []entity.QueryColumn
+		rows    [][]any
+		want    string
+	}{
+		{
+			"rfc 4180 escaping",
+			Options{},
+			[]entity.QueryColumn{{Name: "id", Type: "int8"}, {Name: "note, text", Type: "text"}},
+			[][]any{
+				{int64(1), `say "hi"`},
+				{int64(2), "a,b"},
+				{int64(3), "line\nbreak"},
+				{int64(4), ""},
+			},
+			// line breaks of quoted fields are written as CRLF too
+			"id,\"note, text\"\r\n" +
+				"1,\"say \"\"hi\"\"\"\r\n" +
+				"2,\"a,b\"\r\n" +
+				"3,\"line\r\nbreak\"\r\n" +
+				"4,\r\n",
+		},
+		{
+			"delimiter",
+			Options{Delimiter: ';'},
+			[]entity.QueryColumn{{Name: "id", Type: "int8"}, {Name: "note", Type: "text"}},
+			[][]any{
+				{int64(1), "a,b"},
+				{int64(2), "a;b"},
+			},
+			"id;note\r\n" +
+				"1;a,b\r\n" +
+				"2;\"a;b\"\r\n",
+		},
+		{
+			"tab delimiter and null",
+			Options{Delimiter: '\t', Null: `\N`},
+			[]entity.QueryColumn{{Name: "id", Type: "int8"}, {Name: "note", Type: "text"}},
+			[][]any{
+				{int64(1), nil},
+				{int64(2), "a\tb"},
+			},
+			"id\tnote\r\n" +
+				"1\t\\N\r\n" +
+				"2\t\"a\tb\"\r\n",
+		},
+		{
+			"values formatted by column type",
+			Options{},
+			[]entity.QueryColumn{
+				{Name: "blob", Type: "blob"},
+				{Name: "bytea", Type: "bytea"},
+				{Name: "day", Type: "date"},
+				{Name: "at", Type: "timestamp"},
+				{Name: "attz", Type: "timestamptz"},
+				{Name: "id", Type: "uuid"},
+				{Name: "tags", Type: "_text"},
+			},
+			[][]any{
+				{
+					// mysql blobs are converted to strings
+					"ab",
+					[]byte{0xde, 0xad},
+					at,
+					at,
+					at,
+					[]byte{0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3, 0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00},
+					`{a,"b c"}`,
+				},
+			},
+			"blob,bytea,day,at,attz,id,tags\r\n" +
+				`\x6162,\xdead,2024-05-30,2024-05-30T10:24:15.5,2024-05-30T10:24:15.5Z,123e4567-e89b-12d3-a456-426614174000,"{a,""b c""}"` + "\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			w, err := NewWriter(FormatCSV, &buf, tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err = w.WriteColumns(tt.columns); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, row := range tt.rows {
+				if err = w.WriteRow(row); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err = w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("csv = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVWriterInvalidDelimiter(t *testing.T) {
+	for _, delimiter := range []rune{'"', '\r', '\n', -1} {
+		if _, err := NewWriter(FormatCSV, &bytes.Buffer{}, Options{Delimiter: delimiter}); !errors.Is(err, ErrInvalidDelimiter) {
+			t.Errorf("delimiter %q: err = %v, want %v", delimiter, err, ErrInvalidDelimiter)
+		}
+	}
+}
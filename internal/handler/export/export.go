@@ -0,0 +1,89 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+
+	enginerepo "db-dashboards/internal/repository/engine"
+)
+
+type Format string
+
+const (
+	FormatJSON    Format = "json"
+	FormatNDJSON  Format = "ndjson"
+	FormatCSV     Format = "csv"
+	FormatXLSX    Format = "xlsx"
+	FormatParquet Format = "parquet"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+var contentTypes = map[Format]string{
+	FormatJSON:    "application/json",
+	FormatNDJSON:  "application/x-ndjson",
+	FormatCSV:     "text/csv",
+	FormatXLSX:    "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	FormatParquet: "application/vnd.apache.parquet",
+}
+
+// Options tune text formats, zero Options are defaults
+type Options struct {
+	Delimiter rune   // csv field delimiter, comma if zero
+	Null      string // csv rendering of NULL, empty field by default
+}
+
+// Writer encodes streamed rows in export format
+type Writer interface {
+	enginerepo.RowWriter
+	// Close completes document and flushes buffered output, it is not called if streaming failed
+	Close() error
+}
+
+func (f Format) ContentType() string {
+	return contentTypes[f]
+}
+
+// FormatFromMediaType returns export format served as media type of Accept header, generic json is not
+// an export format since it is served as page of rows
+func FormatFromMediaType(accept string) (Format, bool) {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		for format, contentType := range contentTypes {
+			if format != FormatJSON && mediaType == contentType {
+				return format, true
+			}
+		}
+	}
+
+	return "", false
+}
+
+func NewWriter(format Format, w io.Writer, opts Options) (Writer, error) {
+	switch format {
+	case FormatJSON:
+		return newJSONWriter(w, false), nil
+
+	case FormatNDJSON:
+		return newJSONWriter(w, true), nil
+
+	case FormatCSV:
+		return newCSVWriter(w, opts)
+
+	case FormatXLSX:
+		return newXLSXWriter(w)
+
+	case FormatParquet:
+		return newParquetWriter(w), nil
+
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, format)
+	}
+}
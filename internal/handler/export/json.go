@@ -0,0 +1,93 @@
+package export
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+// jsonColumn is column header of json formats, it matches columns of paged responses
+type jsonColumn struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// jsonWriter writes rows as arrays of values in column order, so that columns of the same name are kept.
+// Json document is {"columns": [...], "rows": [[...], ...]}, ndjson has the header {"columns": [...]}
+// on the first line and a row per line after it. Values are converted by JSONEncoder.
+// Errors of bufio are sticky, so only the last write of row and Flush are checked
+type jsonWriter struct {
+	buf       *bufio.Writer
+	lines     bool
+	encoder   *JSONEncoder
+	delimiter []byte
+}
+
+func newJSONWriter(w io.Writer, lines bool) *jsonWriter {
+	return &jsonWriter{
+		buf:   bufio.NewWriter(w),
+		lines: lines,
+	}
+}
+
+func (w *jsonWriter) WriteColumns(columns []entity.QueryColumn) error {
+	w.encoder = NewJSONEncoder(columns)
+
+	header := struct {
+		Columns []jsonColumn `json:"columns"`
+	}{
+		Columns: make([]jsonColumn, len(columns)),
+	}
+
+	for i, column := range columns {
+		header.Columns[i] = jsonColumn{Name: column.Name, Type: column.Type}
+	}
+
+	encoded, err := json.Marshal(header)
+	if err != nil {
+		return err
+	}
+
+	if w.lines {
+		w.buf.Write(encoded)
+		w.delimiter = []byte{'\n'}
+
+		return nil
+	}
+
+	// header object is left open for rows
+	w.buf.Write(encoded[:len(encoded)-1])
+
+	_, err = w.buf.WriteString(`,"rows":[`)
+
+	return err
+}
+
+func (w *jsonWriter) WriteRow(row []any) error {
+	encoded, err := json.Marshal(w.encoder.Encode(row))
+	if err != nil {
+		return err
+	}
+
+	w.buf.Write(w.delimiter)
+
+	if !w.lines {
+		w.delimiter = []byte{','}
+	}
+
+	_, err = w.buf.Write(encoded)
+
+	return err
+}
+
+func (w *jsonWriter) Close() error {
+	if w.lines {
+		w.buf.WriteByte('\n')
+	} else {
+		w.buf.WriteString("]}")
+	}
+
+	return w.buf.Flush()
+}
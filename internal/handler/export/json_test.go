@@ -0,0 +1,93 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+func TestJSONWriter(t *testing.T) {
+	columns := []entity.QueryColumn{{Name: "id", Type: "INT8"}, {Name: "id", Type: "TEXT"}, {Name: "amount", Type: "NUMERIC"}}
+
+	rows := [][]any{
+		{int64(1), "a", "1.50"},
+		{int64(2), nil, "12345678901234567.89"},
+	}
+
+	tests := []struct {
+		name    string
+		format  Format
+		columns []entity.QueryColumn
+		rows    [][]any
+		want    string
+	}{
+		{
+			"json",
+			FormatJSON,
+			columns,
+			rows,
+			`{"columns":[{"name":"id","type":"INT8"},{"name":"id","type":"TEXT"},{"name":"amount","type":"NUMERIC"}],` +
+				`"rows":[[1,"a",1.50],[2,null,"12345678901234567.89"]]}`,
+		},
+		{
+			"json without rows",
+			FormatJSON,
+			columns[:1],
+			nil,
+			`{"columns":[{"name":"id","type":"INT8"}],"rows":[]}`,
+		},
+		{
+			"json without columns",
+			FormatJSON,
+			nil,
+			nil,
+			`{"columns":[],"rows":[]}`,
+		},
+		{
+			"ndjson",
+			FormatNDJSON,
+			columns,
+			rows,
+			`{"columns":[{"name":"id","type":"INT8"},{"name":"id","type":"TEXT"},{"name":"amount","type":"NUMERIC"}]}` + "\n" +
+				`[1,"a",1.50]` + "\n" +
+				`[2,null,"12345678901234567.89"]` + "\n",
+		},
+		{
+			"ndjson without rows",
+			FormatNDJSON,
+			columns[:1],
+			nil,
+			`{"columns":[{"name":"id","type":"INT8"}]}` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			w, err := NewWriter(tt.format, &buf, Options{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err = w.WriteColumns(tt.columns); err != nil {
+				t.Fatal(err)
+			}
+
+			for _, row := range tt.rows {
+				if err = w.WriteRow(row); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err = w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := buf.String(); got != tt.want {
+				t.Errorf("document =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,454 @@
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/parquet-go/parquet-go/compress/zstd"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+// rows are buffered in memory until row group is complete
+const parquetRowGroupRows = 64 * 1024
+
+// decimals of up to 18 digits fit int64, longer ones are written as fixed length two's complement
+const maxInt64DecimalPrecision = 18
+
+var (
+	ErrValueTypeMismatch = errors.New("value doesn't match column type")
+	ErrNoColumns         = errors.New("parquet file can't be written for result without columns")
+)
+
+// parquetWriter writes rows as zstd compressed parquet file of optional columns, row groups are flushed to w
+// as they fill up. Rows of the first row group are kept as sample before schema is written: columns whose
+// sampled values can't be coerced to column type, e.g. text stored in integer column of sqlite, are written
+// as text. Values of later row groups which can't be coerced fail with ErrValueTypeMismatch
+type parquetWriter struct {
+	w      io.Writer
+	writer *parquet.Writer
+
+	columns []entity.QueryColumn
+	kinds   []valueKind
+	sample  [][]any
+	rows    []parquet.Row
+}
+
+func newParquetWriter(w io.Writer) *parquetWriter {
+	return &parquetWriter{w: w}
+}
+
+func (w *parquetWriter) WriteColumns(columns []entity.QueryColumn) error {
+	if len(columns) == 0 {
+		return ErrNoColumns
+	}
+
+	w.columns = columns
+	w.kinds = make([]valueKind, len(columns))
+
+	for i, column := range columns {
+		w.kinds[i] = columnKindOf(column.Type).kind
+
+		// decimals of unknown precision keep exact text
+		if w.kinds[i] == valueDecimal && column.Precision == 0 {
+			w.kinds[i] = valueText
+		}
+	}
+
+	return nil
+}
+
+func (w *parquetWriter) WriteRow(row []any) error {
+	if w.writer != nil {
+		return w.write(row)
+	}
+
+	w.sample = append(w.sample, row)
+
+	if len(w.sample) < parquetRowGroupRows {
+		return nil
+	}
+
+	return w.start()
+}
+
+func (w *parquetWriter) Close() error {
+	if w.columns == nil {
+		return ErrNoColumns
+	}
+
+	if w.writer == nil {
+		if err := w.start(); err != nil {
+			return err
+		}
+	}
+
+	return w.writer.Close()
+}
+
+// start writes schema of kinds which hold for all sampled values and writes sampled rows
+func (w *parquetWriter) start() error {
+	for i := range w.kinds {
+		for _, row := range w.sample {
+			if row[i] == nil {
+				continue
+			}
+
+			if _, err := w.value(i, row[i]); err != nil {
+				w.kinds[i] = valueText
+				break
+			}
+		}
+	}
+
+	group := parquetGroup{Group: make(parquet.Group, len(w.columns))}
+
+	for i, column := range w.columns {
+		// parquet fields are addressed by name, so repeated names of query result get numbered
+		name := column.Name
+		for n := 2; group.Group[name] != nil; n++ {
+			name = fmt.Sprintf("%s_%d", column.Name, n)
+		}
+
+		node := parquet.Optional(parquetNode(w.kinds[i], column))
+
+		group.Group[name] = node
+		group.fields = append(group.fields, parquetField{Node: node, name: name})
+	}
+
+	w.writer = parquet.NewWriter(w.w,
+		parquet.NewSchema("row", group),
+		parquet.Compression(&zstd.Codec{}),
+		parquet.MaxRowsPerRowGroup(parquetRowGroupRows),
+	)
+
+	w.rows = []parquet.Row{make(parquet.Row, len(w.columns))}
+
+	sample := w.sample
+	w.sample = nil
+
+	for _, row := range sample {
+		if err := w.write(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *parquetWriter) write(row []any) error {
+	values := w.rows[0]
+
+	for i, val := range row {
+		if val == nil {
+			values[i] = parquet.NullValue().Level(0, 0, i)
+			continue
+		}
+
+		value, err := w.value(i, val)
+		if err != nil {
+			return err
+		}
+
+		values[i] = value.Level(0, 1, i)
+	}
+
+	_, err := w.writer.WriteRows(w.rows)
+
+	return err
+}
+
+func (w *parquetWriter) value(i int, val any) (parquet.Value, error) {
+	var (
+		value parquet.Value
+		ok    bool
+	)
+
+	switch w.kinds[i] {
+	case valueInt:
+		value, ok = intValue(val)
+
+	case valueUint:
+		value, ok = uintValue(val)
+
+	case valueFloat:
+		value, ok = doubleValue(val)
+
+	case valueDecimal:
+		value, ok = decimalValue(val, w.columns[i].Precision, w.columns[i].Scale)
+
+	case valueBool:
+		value, ok = boolValue(val)
+
+	case valueDate, valueTimestamp, valueTimestampTZ:
+		t, isTime := val.(time.Time)
+
+		if text, isText := val.(string); isText {
+			t, isTime = parseTemporal(text)
+		}
+
+		if !isTime {
+			break
+		}
+
+		ok = true
+
+		if w.kinds[i] != valueDate {
+			value = parquet.Int64Value(t.UnixMicro())
+			break
+		}
+
+		// days since epoch of calendar date, regardless of location
+		value = parquet.Int32Value(int32(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Unix() / 86400))
+
+	case valueBytes:
+		switch v := val.(type) {
+		case []byte:
+			value, ok = parquet.ByteArrayValue(v), true
+
+		case string:
+			value, ok = parquet.ByteArrayValue([]byte(v)), true
+		}
+
+	default:
+		text, err := formatValue(w.kinds[i], val)
+		if err != nil {
+			return parquet.Value{}, err
+		}
+
+		value, ok = parquet.ByteArrayValue([]byte(text)), true
+	}
+
+	if !ok {
+		return parquet.Value{}, fmt.Errorf("%w: %T value of %v column %q", ErrValueTypeMismatch, val, w.columns[i].Type, w.columns[i].Name)
+	}
+
+	return value, nil
+}
+
+// parquetNode returns logical type of column kind, arrays keep text representation of postgres
+func parquetNode(kind valueKind, column entity.QueryColumn) parquet.Node {
+	switch kind {
+	case valueInt:
+		return parquet.Int(64)
+
+	case valueUint:
+		return parquet.Uint(64)
+
+	case valueFloat:
+		return parquet.Leaf(parquet.DoubleType)
+
+	case valueDecimal:
+		if column.Precision <= maxInt64DecimalPrecision {
+			return parquet.Decimal(column.Scale, column.Precision, parquet.Int64Type)
+		}
+
+		return parquet.Decimal(column.Scale, column.Precision, parquet.FixedLenByteArrayType(decimalSize(column.Precision)))
+
+	case valueBool:
+		return parquet.Leaf(parquet.BooleanType)
+
+	case valueDate:
+		return parquet.Date()
+
+	case valueTimestamp, valueTimestampTZ:
+		// timestamps without time zone are read by drivers as UTC
+		return parquet.Timestamp(parquet.Microsecond)
+
+	case valueBytes:
+		return parquet.Leaf(parquet.ByteArrayType)
+
+	case valueJSON:
+		return parquet.JSON()
+
+	default:
+		return parquet.String()
+	}
+}
+
+func intValue(val any) (parquet.Value, bool) {
+	switch v := val.(type) {
+	case int64:
+		return parquet.Int64Value(v), true
+
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+			return parquet.Int64Value(int64(v)), true
+		}
+
+	case string:
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return parquet.Int64Value(n), true
+		}
+	}
+
+	return parquet.Value{}, false
+}
+
+func uintValue(val any) (parquet.Value, bool) {
+	switch v := val.(type) {
+	case int64:
+		if v >= 0 {
+			return parquet.Int64Value(v), true
+		}
+
+	case uint64:
+		return parquet.Int64Value(int64(v)), true
+
+	case string:
+		if n, err := strconv.ParseUint(v, 10, 64); err == nil {
+			return parquet.Int64Value(int64(n)), true
+		}
+	}
+
+	return parquet.Value{}, false
+}
+
+func doubleValue(val any) (parquet.Value, bool) {
+	switch v := val.(type) {
+	case float64:
+		return parquet.DoubleValue(v), true
+
+	case float32:
+		return parquet.DoubleValue(float64(v)), true
+
+	case int64:
+		return parquet.DoubleValue(float64(v)), true
+
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return parquet.DoubleValue(f), true
+		}
+	}
+
+	return parquet.Value{}, false
+}
+
+func boolValue(val any) (parquet.Value, bool) {
+	switch v := val.(type) {
+	case bool:
+		return parquet.BooleanValue(v), true
+
+	case int64:
+		return parquet.BooleanValue(v != 0), true
+
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return parquet.BooleanValue(b), true
+		}
+	}
+
+	return parquet.Value{}, false
+}
+
+// decimalValue converts value to unscaled integer of decimal(precision, scale), values with more significant
+// fraction digits than scale or more digits than precision don't match
+func decimalValue(val any, precision, scale int) (parquet.Value, bool) {
+	var text string
+
+	switch v := val.(type) {
+	case string:
+		text = v
+
+	case []byte:
+		text = string(v)
+
+	case int64:
+		text = strconv.FormatInt(v, 10)
+
+	case float64:
+		text = strconv.FormatFloat(v, 'f', -1, 64)
+
+	default:
+		return parquet.Value{}, false
+	}
+
+	unscaled, ok := unscaledDecimal(text, precision, scale)
+	if !ok {
+		return parquet.Value{}, false
+	}
+
+	if precision <= maxInt64DecimalPrecision {
+		return parquet.Int64Value(unscaled.Int64()), true
+	}
+
+	size := decimalSize(precision)
+	b := make([]byte, size)
+
+	// negative values are stored as two's complement
+	if unscaled.Sign() < 0 {
+		unscaled.Add(unscaled, new(big.Int).Lsh(big.NewInt(1), uint(8*size)))
+	}
+
+	return parquet.FixedLenByteArrayValue(unscaled.FillBytes(b)), true
+}
+
+func unscaledDecimal(text string, precision, scale int) (*big.Int, bool) {
+	digits, negative := strings.CutPrefix(text, "-")
+
+	intPart, fracPart, _ := strings.Cut(digits, ".")
+	if intPart+fracPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return nil, false
+	}
+
+	if len(fracPart) > scale {
+		if strings.Trim(fracPart[scale:], "0") != "" {
+			return nil, false
+		}
+
+		fracPart = fracPart[:scale]
+	}
+
+	digits = strings.TrimLeft(intPart+fracPart+strings.Repeat("0", scale-len(fracPart)), "0")
+	if len(digits) > precision {
+		return nil, false
+	}
+
+	unscaled := new(big.Int)
+	if digits != "" {
+		unscaled.SetString(digits, 10)
+	}
+
+	if negative {
+		unscaled.Neg(unscaled)
+	}
+
+	return unscaled, true
+}
+
+// decimalSize returns length of the shortest two's complement which holds decimals of precision
+func decimalSize(precision int) int {
+	return int(math.Ceil((float64(precision)*math.Log2(10) + 1) / 8))
+}
+
+// parquetGroup keeps fields in order of result columns, parquet.Group sorts them by name
+type parquetGroup struct {
+	parquet.Group
+	fields []parquet.Field
+}
+
+func (g parquetGroup) Fields() []parquet.Field {
+	return g.fields
+}
+
+type parquetField struct {
+	parquet.Node
+	name string
+}
+
+func (f parquetField) Name() string {
+	return f.name
+}
+
+// Value is not used since rows are written as parquet values rather than go structs
+func (f parquetField) Value(reflect.Value) reflect.Value {
+	return reflect.Value{}
+}
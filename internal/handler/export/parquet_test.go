@@ -0,0 +1,197 @@
+package export
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+func writeParquet(t *testing.T, columns []entity.QueryColumn, rows [][]any) (*parquet.File, error) {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	w := newParquetWriter(&buf)
+
+	if err := w.WriteColumns(columns); err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if err := w.WriteRow(row); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return parquet.OpenFile(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+}
+
+func readParquet(t *testing.T, f *parquet.File) []parquet.Row {
+	t.Helper()
+
+	var rows []parquet.Row
+
+	for _, rowGroup := range f.RowGroups() {
+		reader := rowGroup.Rows()
+
+		buf := make([]parquet.Row, 16)
+
+		for {
+			n, err := reader.ReadRows(buf)
+			for _, row := range buf[:n] {
+				rows = append(rows, row.Clone())
+			}
+
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		reader.Close()
+	}
+
+	return rows
+}
+
+func TestParquetWriter(t *testing.T) {
+	columns := []entity.QueryColumn{
+		{Name: "id", Type: "int8"},
+		{Name: "price", Type: "numeric", Precision: 10, Scale: 2},
+		{Name: "total", Type: "numeric", Precision: 30, Scale: 5},
+		{Name: "ratio", Type: "numeric"},
+		{Name: "day", Type: "date"},
+		{Name: "at", Type: "timestamptz"},
+		{Name: "id", Type: "text"},
+	}
+
+	at := time.Date(2024, 5, 30, 10, 24, 15, 123456000, time.UTC)
+
+	f, err := writeParquet(t, columns, [][]any{
+		{int64(1), "12.50", "-1234567890123456789012.5", "0.333", at, at, "a"},
+		{int64(2), "-0.01", "1", "NaN", nil, nil, nil},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := f.Schema().Fields()
+
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name()
+	}
+
+	if want := []string{"id", "price", "total", "ratio", "day", "at", "id_2"}; !slices.Equal(names, want) {
+		t.Errorf("fields = %v, want %v", names, want)
+	}
+
+	price := fields[1].Type().LogicalType()
+	if price == nil || price.Decimal == nil || price.Decimal.Precision != 10 || price.Decimal.Scale != 2 ||
+		fields[1].Type().Kind() != parquet.Int64 {
+		t.Errorf("price type = %v, want int64 decimal(10, 2)", fields[1].Type())
+	}
+
+	total := fields[2].Type().LogicalType()
+	if total == nil || total.Decimal == nil || total.Decimal.Precision != 30 || fields[2].Type().Kind() != parquet.FixedLenByteArray {
+		t.Errorf("total type = %v, want fixed length decimal(30, 5)", fields[2].Type())
+	}
+
+	if ratio := fields[3].Type().LogicalType(); ratio == nil || ratio.UTF8 == nil {
+		t.Errorf("ratio type = %v, want string", fields[3].Type())
+	}
+
+	rows := readParquet(t, f)
+	if len(rows) != 2 {
+		t.Fatalf("rows = %d, want 2", len(rows))
+	}
+
+	if got := rows[0][1].Int64(); got != 1250 {
+		t.Errorf("price = %d, want 1250", got)
+	}
+
+	if got := rows[1][1].Int64(); got != -1 {
+		t.Errorf("price = %d, want -1", got)
+	}
+
+	// two's complement of -123456789012345678901250000
+	wantTotal := []byte{0xff, 0xff, 0x99, 0xe1, 0x02, 0x0e, 0xa7, 0x0d, 0x57, 0xd3, 0x60, 0x78, 0x30}
+	if got := rows[0][2].ByteArray(); !bytes.Equal(got, wantTotal) {
+		t.Errorf("total = %x, want %x", got, wantTotal)
+	}
+
+	if got := rows[1][3].String(); got != "NaN" {
+		t.Errorf("ratio = %q, want NaN", got)
+	}
+
+	if got := rows[0][4].Int32(); got != 19873 {
+		t.Errorf("day = %d, want 19873", got)
+	}
+
+	if got := rows[0][5].Int64(); got != at.UnixMicro() {
+		t.Errorf("at = %d, want %d", got, at.UnixMicro())
+	}
+
+	if !rows[1][4].IsNull() || !rows[1][6].IsNull() {
+		t.Errorf("row = %v, want nulls", rows[1])
+	}
+}
+
+func TestParquetWriterMismatch(t *testing.T) {
+	t.Run("sampled values fall back to string", func(t *testing.T) {
+		// sqlite keeps text stored in integer column
+		f, err := writeParquet(t, []entity.QueryColumn{{Name: "n", Type: "integer"}, {Name: "price", Type: "decimal", Precision: 4, Scale: 1}}, [][]any{
+			{int64(1), "1.5"},
+			{"abc", "1.25"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, field := range f.Schema().Fields() {
+			if logical := field.Type().LogicalType(); logical == nil || logical.UTF8 == nil {
+				t.Errorf("%s type = %v, want string", field.Name(), field.Type())
+			}
+		}
+
+		rows := readParquet(t, f)
+
+		if got := []string{rows[0][0].String(), rows[1][0].String(), rows[1][1].String()}; !slices.Equal(got, []string{"1", "abc", "1.25"}) {
+			t.Errorf("values = %v", got)
+		}
+	})
+
+	t.Run("values after sample fail", func(t *testing.T) {
+		rows := make([][]any, parquetRowGroupRows+1)
+		for i := range rows {
+			rows[i] = []any{int64(i)}
+		}
+
+		rows[parquetRowGroupRows] = []any{"abc"}
+
+		_, err := writeParquet(t, []entity.QueryColumn{{Name: "n", Type: "integer"}}, rows)
+		if !errors.Is(err, ErrValueTypeMismatch) {
+			t.Errorf("err = %v, want %v", err, ErrValueTypeMismatch)
+		}
+	})
+
+	t.Run("no columns", func(t *testing.T) {
+		if _, err := writeParquet(t, nil, nil); !errors.Is(err, ErrNoColumns) {
+			t.Errorf("err = %v, want %v", err, ErrNoColumns)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package export
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+
+	enginerepo "db-dashboards/internal/repository/engine"
+)
+
+const (
+	RowCountTrailer  = "X-Row-Count"
+	TruncatedTrailer = "X-Rows-Truncated"
+)
+
+// Response streams export document to client. Headers are sent with the first byte of document,
+// so that errors occurred before it are still answered with error status
+type Response struct {
+	rw        http.ResponseWriter
+	format    Format
+	committed bool
+}
+
+func NewResponse(rw http.ResponseWriter, format Format) *Response {
+	return &Response{
+		rw:     rw,
+		format: format,
+	}
+}
+
+func (r *Response) Write(p []byte) (int, error) {
+	if !r.committed {
+		r.committed = true
+
+		header := r.rw.Header()
+
+		header.Set("Content-Type", r.format.ContentType())
+		header.Set("Trailer", RowCountTrailer+", "+TruncatedTrailer)
+
+		r.rw.WriteHeader(http.StatusOK)
+	}
+
+	return r.rw.Write(p)
+}
+
+// Committed tells whether status and headers are already sent
+func (r *Response) Committed() bool {
+	return r.committed
+}
+
+// Finish reports streamed rows in trailers, document must be completed before
+func (r *Response) Finish(result *enginerepo.StreamResult) {
+	// headers are sent even if writer produced no output
+	if !r.committed {
+		r.Write(nil)
+	}
+
+	r.rw.Header().Set(RowCountTrailer, strconv.Itoa(result.Rows))
+	r.rw.Header().Set(TruncatedTrailer, strconv.FormatBool(result.Truncated))
+}
+
+// Serve streams rows passed by stream to rw in format. Error is returned if nothing was sent yet so that caller
+// answers with error status. Once document is partially sent errors can not be reported anymore,
+// connection is aborted then so that client does not take document as complete
+func Serve(rw http.ResponseWriter,
+	logger *logrus.Logger,
+	format Format,
+	opts Options,
+	stream func(w enginerepo.RowWriter) (*enginerepo.StreamResult, error),
+) error {
+	resp := NewResponse(rw, format)
+
+	w, err := NewWriter(format, resp, opts)
+	if err != nil {
+		return err
+	}
+
+	result, err := stream(w)
+	if err == nil {
+		err = w.Close()
+	}
+
+	if err != nil {
+		if !resp.Committed() {
+			return err
+		}
+
+		logger.WithError(err).Errorf("error occurred streaming %v export, aborting partially sent response", format)
+		panic(http.ErrAbortHandler)
+	}
+
+	resp.Finish(result)
+
+	return nil
+}
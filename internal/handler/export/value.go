@@ -0,0 +1,452 @@
+package export
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"db-dashboards/internal/domain/entity"
+
+	enginerepo "db-dashboards/internal/repository/engine"
+)
+
+// integers beyond are not exact in float64 and are encoded as strings
+const maxSafeInteger = 1<<53 - 1
+
+// decimals of up to 15 significant digits survive conversion to float64
+const maxSafeDigits = 15
+
+type valueKind int
+
+const (
+	valueText valueKind = iota
+	valueInt
+	valueUint
+	valueFloat
+	valueDecimal
+	valueBool
+	valueDate
+	valueTimestamp
+	valueTimestampTZ
+	valueUUID
+	valueBytes
+	valueJSON
+	valueArray
+)
+
+// base type names of supported engines, types missing here are treated as text
+var valueKinds = map[string]valueKind{
+	"smallint": valueInt, "int": valueInt, "integer": valueInt, "bigint": valueInt,
+	"tinyint": valueInt, "mediumint": valueInt, "int2": valueInt, "int4": valueInt, "int8": valueInt,
+	"serial": valueInt, "bigserial": valueInt, "smallserial": valueInt, "year": valueInt,
+
+	"real": valueFloat, "float": valueFloat, "float4": valueFloat, "float8": valueFloat, "double": valueFloat,
+
+	"decimal": valueDecimal, "numeric": valueDecimal,
+
+	"bool": valueBool, "boolean": valueBool,
+
+	"date": valueDate, "datetime": valueTimestamp, "timestamp": valueTimestamp, "timestamptz": valueTimestampTZ,
+
+	"uuid": valueUUID,
+
+	"bytea": valueBytes, "blob": valueBytes, "tinyblob": valueBytes, "mediumblob": valueBytes,
+	"longblob": valueBytes, "binary": valueBytes, "varbinary": valueBytes,
+
+	"json": valueJSON, "jsonb": valueJSON,
+}
+
+// columnKind is kind of column values, element kind is set for postgres arrays whose type names start with underscore
+type columnKind struct {
+	kind    valueKind
+	element valueKind
+}
+
+func columnKindOf(typeName string) columnKind {
+	if element, ok := strings.CutPrefix(strings.ToLower(typeName), "_"); ok {
+		return columnKind{kind: valueArray, element: valueKindOf(element)}
+	}
+
+	return columnKind{kind: valueKindOf(typeName)}
+}
+
+func valueKindOf(typeName string) valueKind {
+	kind := valueKinds[enginerepo.BaseType(typeName)]
+
+	// unsigned bigint exceeds int64
+	if kind == valueInt && strings.Contains(strings.ToLower(typeName), "unsigned") {
+		return valueUint
+	}
+
+	return kind
+}
+
+// JSONEncoder converts driver values of result columns to values which encoding/json renders the same way
+// regardless of engine: integers and decimals beyond float64 precision become strings, binary values
+// become hex literals, json values are embedded and postgres arrays become json arrays
+type JSONEncoder struct {
+	kinds []columnKind
+}
+
+func NewJSONEncoder(columns []entity.QueryColumn) *JSONEncoder {
+	kinds := make([]columnKind, len(columns))
+
+	for i, column := range columns {
+		kinds[i] = columnKindOf(column.Type)
+	}
+
+	return &JSONEncoder{kinds: kinds}
+}
+
+// Encode returns encoded copy of row, values of columns are ordered as columns
+func (e *JSONEncoder) Encode(row []any) []any {
+	encoded := make([]any, len(row))
+
+	for i, val := range row {
+		if i < len(e.kinds) {
+			encoded[i] = e.kinds[i].jsonValue(val)
+		} else {
+			encoded[i] = jsonValue(valueText, val)
+		}
+	}
+
+	return encoded
+}
+
+func (k columnKind) jsonValue(val any) any {
+	if k.kind != valueArray {
+		return jsonValue(k.kind, val)
+	}
+
+	text, ok := val.(string)
+	if !ok {
+		return jsonValue(valueText, val)
+	}
+
+	items, ok := parseArray(text)
+	if !ok {
+		return text
+	}
+
+	return jsonArray(k.element, items)
+}
+
+func jsonArray(element valueKind, items []any) []any {
+	// elements of binary arrays are already hex literals
+	if element == valueBytes {
+		element = valueText
+	}
+
+	for i, item := range items {
+		switch v := item.(type) {
+		case []any:
+			items[i] = jsonArray(element, v)
+
+		case string:
+			items[i] = jsonValue(element, v)
+		}
+	}
+
+	return items
+}
+
+// jsonValue converts value of kind, values not matching kind are converted by their go type
+func jsonValue(kind valueKind, val any) any {
+	switch kind {
+	case valueInt, valueUint:
+		switch v := val.(type) {
+		case int64:
+			return jsonInteger(v)
+
+		case uint64:
+			if v <= maxSafeInteger {
+				return int64(v)
+			}
+
+			return strconv.FormatUint(v, 10)
+
+		case string:
+			if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return jsonInteger(n)
+			}
+
+			if n, err := strconv.ParseUint(v, 10, 64); err == nil && n > maxSafeInteger {
+				return v
+			}
+		}
+
+	case valueFloat:
+		switch v := val.(type) {
+		case float32:
+			return jsonFloat(float64(v))
+
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return jsonFloat(f)
+			}
+		}
+
+	case valueDecimal:
+		if text, ok := val.(string); ok {
+			if isSafeDecimal(text) {
+				return json.Number(text)
+			}
+
+			// NaN, infinities and decimals which would lose digits
+			return text
+		}
+
+	case valueBool:
+		switch v := val.(type) {
+		case int64:
+			return v != 0
+
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b
+			}
+		}
+
+	case valueDate:
+		if t, ok := val.(time.Time); ok {
+			return t.Format(time.DateOnly)
+		}
+
+	case valueTimestamp:
+		if t, ok := val.(time.Time); ok {
+			return t.Format("2006-01-02T15:04:05.999999999")
+		}
+
+	case valueUUID:
+		if v, ok := val.([]byte); ok && len(v) == 16 {
+			text := hex.EncodeToString(v)
+
+			return text[:8] + "-" + text[8:12] + "-" + text[12:16] + "-" + text[16:20] + "-" + text[20:]
+		}
+
+	case valueBytes:
+		switch v := val.(type) {
+		case []byte:
+			return `\x` + hex.EncodeToString(v)
+
+		case string:
+			return `\x` + hex.EncodeToString([]byte(v))
+		}
+
+	case valueJSON:
+		switch v := val.(type) {
+		case []byte:
+			if json.Valid(v) {
+				return json.RawMessage(v)
+			}
+
+		case string:
+			if json.Valid([]byte(v)) {
+				return json.RawMessage(v)
+			}
+		}
+	}
+
+	switch v := val.(type) {
+	case int64:
+		return jsonInteger(v)
+
+	case float64:
+		return jsonFloat(v)
+
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+
+	case []byte:
+		if utf8.Valid(v) {
+			return string(v)
+		}
+
+		return `\x` + hex.EncodeToString(v)
+	}
+
+	return val
+}
+
+func jsonInteger(n int64) any {
+	if n > maxSafeInteger || n < -maxSafeInteger {
+		return strconv.FormatInt(n, 10)
+	}
+
+	return n
+}
+
+// jsonFloat keeps values json has no numbers for as strings spelled as by postgres
+func jsonFloat(f float64) any {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+
+	case math.IsInf(f, 1):
+		return "Infinity"
+
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+
+	return f
+}
+
+// isSafeDecimal reports whether text is plain decimal number of at most maxSafeDigits significant digits
+func isSafeDecimal(text string) bool {
+	digits := strings.TrimPrefix(text, "-")
+
+	intPart, fracPart, hasPoint := strings.Cut(digits, ".")
+	if intPart == "" || !isDigits(intPart) || !isDigits(fracPart) || hasPoint && fracPart == "" {
+		return false
+	}
+
+	// json doesn't allow leading zeros
+	if len(intPart) > 1 && intPart[0] == '0' {
+		return false
+	}
+
+	significant := strings.Trim(intPart+fracPart, "0")
+
+	return len(significant) <= maxSafeDigits
+}
+
+func isDigits(s string) bool {
+	for _, c := range []byte(s) {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// parseArray parses text representation of postgres array, e.g. {1,NULL,"a \"b\""} or {{1,2},{3,4}}.
+// Elements are returned as strings, nested arrays as slices and NULL as nil
+func parseArray(text string) ([]any, bool) {
+	// arrays with non default bounds are prefixed with dimensions, e.g. [0:1]={1,2}
+	if strings.HasPrefix(text, "[") {
+		_, after, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, false
+		}
+
+		text = after
+	}
+
+	p := arrayParser{text: text}
+
+	items, ok := p.array()
+	if !ok || p.pos != len(text) {
+		return nil, false
+	}
+
+	return items, true
+}
+
+type arrayParser struct {
+	text string
+	pos  int
+}
+
+func (p *arrayParser) array() ([]any, bool) {
+	if p.pos >= len(p.text) || p.text[p.pos] != '{' {
+		return nil, false
+	}
+
+	p.pos++
+
+	items := []any{}
+
+	if p.pos < len(p.text) && p.text[p.pos] == '}' {
+		p.pos++
+		return items, true
+	}
+
+	for p.pos < len(p.text) {
+		var (
+			item any
+			ok   bool
+		)
+
+		switch p.text[p.pos] {
+		case '{':
+			item, ok = p.array()
+
+		case '"':
+			item, ok = p.quoted()
+
+		default:
+			item, ok = p.unquoted()
+		}
+
+		if !ok || p.pos >= len(p.text) {
+			return nil, false
+		}
+
+		items = append(items, item)
+
+		delimiter := p.text[p.pos]
+		p.pos++
+
+		switch delimiter {
+		case '}':
+			return items, true
+
+		case ',':
+
+		default:
+			return nil, false
+		}
+	}
+
+	return nil, false
+}
+
+func (p *arrayParser) quoted() (any, bool) {
+	var b strings.Builder
+
+	for p.pos++; p.pos < len(p.text); p.pos++ {
+		switch c := p.text[p.pos]; c {
+		case '\\':
+			p.pos++
+
+			if p.pos < len(p.text) {
+				b.WriteByte(p.text[p.pos])
+			}
+
+		case '"':
+			p.pos++
+			return b.String(), true
+
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return nil, false
+}
+
+func (p *arrayParser) unquoted() (any, bool) {
+	start := p.pos
+
+	for p.pos < len(p.text) && p.text[p.pos] != ',' && p.text[p.pos] != '}' {
+		p.pos++
+	}
+
+	item := strings.TrimSpace(p.text[start:p.pos])
+
+	switch {
+	case item == "":
+		return nil, false
+
+	case strings.EqualFold(item, "NULL"):
+		return nil, true
+	}
+
+	return item, true
+}
@@ -0,0 +1,221 @@
+package export
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+func TestIsSafeDecimal(t *testing.T) {
+	tests := []struct {
+		text string
+		want bool
+	}{
+		{"0", true},
+		{"-0.5", true},
+		{"123.450", true},
+		{"999999999999999", true},
+		{"-99999999999999.9", true},
+		{"0.000000000000000000001", true},
+		{"1000000000000000000000", true},
+		{"1234567890123456", false},
+		{"1.234567890123456", false},
+		{"01", false},
+		{"-01.5", false},
+		{".5", false},
+		{"1.", false},
+		{"-", false},
+		{"", false},
+		{"1e5", false},
+		{"+1", false},
+		{"NaN", false},
+		{"Infinity", false},
+		{"1.2.3", false},
+	}
+
+	for _, tt := range tests {
+		if got := isSafeDecimal(tt.text); got != tt.want {
+			t.Errorf("isSafeDecimal(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseArray(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		want   []any
+		wantOK bool
+	}{
+		{"empty", "{}", []any{}, true},
+		{"integers", "{1,2,3}", []any{"1", "2", "3"}, true},
+		{"null", "{1,NULL,null}", []any{"1", nil, nil}, true},
+		{"quoted null is text", `{"NULL"}`, []any{"NULL"}, true},
+		{"quoted", `{"a b","c,d","{e}"}`, []any{"a b", "c,d", "{e}"}, true},
+		{"escaped quotes and backslashes", `{"a \"b\"","c\\d"}`, []any{`a "b"`, `c\d`}, true},
+		{"empty quoted", `{""}`, []any{""}, true},
+		{"nested", "{{1,2},{3,NULL}}", []any{[]any{"1", "2"}, []any{"3", nil}}, true},
+		{"nested empty", "{{},{}}", []any{[]any{}, []any{}}, true},
+		{"dimension prefix", "[0:1]={7,8}", []any{"7", "8"}, true},
+		{"multidimensional prefix", "[1:2][1:1]={{1},{2}}", []any{[]any{"1"}, []any{"2"}}, true},
+		{"spaces around unquoted", "{ a , b }", []any{"a", "b"}, true},
+
+		{"not array", "1,2", nil, false},
+		{"unterminated", "{1,2", nil, false},
+		{"unterminated quote", `{"a}`, nil, false},
+		{"trailing text", "{1}x", nil, false},
+		{"missing element", "{1,,2}", nil, false},
+		{"trailing delimiter", "{1,}", nil, false},
+		{"prefix without array", "[0:1]", nil, false},
+		{"garbage after quoted", `{"a"b}`, nil, false},
+		{"empty", "", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseArray(tt.text)
+			if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseArray(%q) = %#v, %v, want %#v, %v", tt.text, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestJSONValue(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 13, 14, 15, 500_000_000, time.UTC)
+
+	tests := []struct {
+		name string
+		kind valueKind
+		val  any
+		want any
+	}{
+		{"int", valueInt, int64(42), int64(42)},
+		{"int beyond float precision", valueInt, int64(1 << 60), "1152921504606846976"},
+		{"negative int beyond float precision", valueInt, int64(-(1 << 60)), "-1152921504606846976"},
+		{"max safe int", valueInt, int64(maxSafeInteger), int64(maxSafeInteger)},
+		{"int as text", valueInt, "17", int64(17)},
+		{"big int as text", valueInt, "9007199254740993", "9007199254740993"},
+		{"uint", valueUint, uint64(5), int64(5)},
+		{"uint beyond int64", valueUint, uint64(math.MaxUint64), "18446744073709551615"},
+		{"uint beyond int64 as text", valueUint, "18446744073709551615", "18446744073709551615"},
+
+		{"float", valueFloat, 1.5, 1.5},
+		{"float32", valueFloat, float32(0.5), 0.5},
+		{"float as text", valueFloat, "2.25", 2.25},
+		{"nan", valueFloat, math.NaN(), "NaN"},
+		{"infinity", valueFloat, math.Inf(1), "Infinity"},
+		{"negative infinity as text", valueFloat, "-Infinity", "-Infinity"},
+
+		{"decimal", valueDecimal, "12.50", json.Number("12.50")},
+		{"decimal beyond float precision", valueDecimal, "12345678901234567.89", "12345678901234567.89"},
+		{"decimal nan", valueDecimal, "NaN", "NaN"},
+
+		{"bool", valueBool, true, true},
+		{"bool as tinyint", valueBool, int64(0), false},
+		{"bool as text", valueBool, "1", true},
+
+		{"date", valueDate, ts, "2024-05-01"},
+		{"timestamp", valueTimestamp, ts, "2024-05-01T13:14:15.5"},
+		{"timestamptz", valueTimestampTZ, ts, "2024-05-01T13:14:15.5Z"},
+		{"timestamp as text", valueTimestamp, "2024-05-01 13:14:15", "2024-05-01 13:14:15"},
+
+		{
+			"uuid bytes",
+			valueUUID,
+			[]byte{0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0, 0x12, 0x34, 0x56, 0x78, 0x9a, 0xbc, 0xde, 0xf0},
+			"12345678-9abc-def0-1234-56789abcdef0",
+		},
+		{"uuid text", valueUUID, "12345678-9abc-def0-1234-56789abcdef0", "12345678-9abc-def0-1234-56789abcdef0"},
+		{"uuid bytes of text", valueUUID, []byte("12345678-9abc-def0-1234-56789abcdef0"), "12345678-9abc-def0-1234-56789abcdef0"},
+
+		{"bytea", valueBytes, []byte{0x00, 0xff}, `\x00ff`},
+		{"blob as text", valueBytes, "hi", `\x6869`},
+		{"empty bytes", valueBytes, []byte{}, `\x`},
+
+		{"json bytes", valueJSON, []byte(`{"a":[1,2]}`), json.RawMessage(`{"a":[1,2]}`)},
+		{"json text", valueJSON, `[true,null]`, json.RawMessage(`[true,null]`)},
+		{"invalid json", valueJSON, `{"a":`, `{"a":`},
+
+		{"text", valueText, "plain", "plain"},
+		{"text bytes", valueText, []byte("plain"), "plain"},
+		{"binary bytes of text column", valueText, []byte{0xff, 0xfe}, `\xfffe`},
+		{"untyped int", valueText, int64(1 << 60), "1152921504606846976"},
+		{"untyped time", valueText, ts, "2024-05-01T13:14:15.5Z"},
+		{"null", valueInt, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := jsonValue(tt.kind, tt.val); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("jsonValue(%#v) = %#v, want %#v", tt.val, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJSONEncoder(t *testing.T) {
+	columns := []entity.QueryColumn{
+		{Name: "id", Type: "INT8"},
+		{Name: "amount", Type: "NUMERIC"},
+		{Name: "tags", Type: "_TEXT"},
+		{Name: "matrix", Type: "_INT4"},
+		{Name: "blobs", Type: "_BYTEA"},
+		{Name: "ids", Type: "_INT8"},
+		{Name: "counter", Type: "BIGINT UNSIGNED"},
+		{Name: "payload", Type: "JSONB"},
+	}
+
+	tests := []struct {
+		name string
+		row  []any
+		want string
+	}{
+		{
+			"typed values",
+			[]any{
+				int64(1),
+				"10.25",
+				`{a,"b c",NULL}`,
+				"{{1,2},{3,4}}",
+				`{"\\x00ff"}`,
+				"{9007199254740993,1}",
+				uint64(math.MaxUint64),
+				[]byte(`{"k":"v"}`),
+			},
+			`[1,10.25,["a","b c",null],[[1,2],[3,4]],["\\x00ff"],["9007199254740993",1],"18446744073709551615",{"k":"v"}]`,
+		},
+		{
+			"nulls",
+			[]any{nil, nil, nil, nil, nil, nil, nil, nil},
+			`[null,null,null,null,null,null,null,null]`,
+		},
+		{
+			"malformed arrays fall back to text",
+			[]any{int64(2), "1", "{a,", "not array", `{"x"`, []byte("{1}"), nil, nil},
+			`[2,1,"{a,","not array","{\"x\"","{1}",null,null]`,
+		},
+		{
+			"values beyond columns",
+			[]any{int64(3), "0", "{}", "{}", "{}", "{}", nil, nil, []byte("extra")},
+			`[3,0,[],[],[],[],null,null,"extra"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := json.Marshal(NewJSONEncoder(columns).Encode(tt.row))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(encoded) != tt.want {
+				t.Errorf("Encode() = %s, want %s", encoded, tt.want)
+			}
+		})
+	}
+}
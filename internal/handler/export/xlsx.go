@@ -0,0 +1,361 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+
+	"db-dashboards/internal/domain/entity"
+
+	enginerepo "db-dashboards/internal/repository/engine"
+)
+
+const (
+	// column widths are computed from header and first rows as sheet is written before all rows are known
+	xlsxSampleRows = 100
+	xlsxMaxWidth   = 60
+	// data rows of sheet, the first row is header
+	xlsxSheetRows = excelize.TotalRows - 1
+)
+
+// layouts of temporal values returned as text by drivers
+var temporalLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+// xlsxWriter writes rows as typed cells of workbook, rows exceeding sheet limit continue on next sheet.
+// Sheets are spooled by excelize and workbook is written to w on Close
+type xlsxWriter struct {
+	w    io.Writer
+	file *excelize.File
+
+	columns    []entity.QueryColumn
+	kinds      []enginerepo.TypeKind
+	valueKinds []valueKind // formatting of values written as text
+	widths     []int
+
+	headerStyle   int
+	dateStyle     int
+	dateTimeStyle int
+
+	sheet        *excelize.StreamWriter
+	sheets       int
+	sheetRows    int
+	maxSheetRows int
+	sample       [][]any
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	file := excelize.NewFile()
+
+	headerStyle, err := file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return nil, err
+	}
+
+	dateStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: strPtr("yyyy-mm-dd")})
+	if err != nil {
+		return nil, err
+	}
+
+	dateTimeStyle, err := file.NewStyle(&excelize.Style{CustomNumFmt: strPtr("yyyy-mm-dd hh:mm:ss")})
+	if err != nil {
+		return nil, err
+	}
+
+	return &xlsxWriter{
+		w:             w,
+		file:          file,
+		headerStyle:   headerStyle,
+		dateStyle:     dateStyle,
+		dateTimeStyle: dateTimeStyle,
+		maxSheetRows:  xlsxSheetRows,
+	}, nil
+}
+
+func (w *xlsxWriter) WriteColumns(columns []entity.QueryColumn) error {
+	w.columns = columns
+	w.kinds = make([]enginerepo.TypeKind, len(columns))
+	w.valueKinds = make([]valueKind, len(columns))
+	w.widths = make([]int, len(columns))
+
+	for i, column := range columns {
+		w.kinds[i] = enginerepo.KindOf(column.Type)
+		w.valueKinds[i] = columnKindOf(column.Type).kind
+		w.widths[i] = utf8.RuneCountInString(column.Name)
+	}
+
+	return nil
+}
+
+func (w *xlsxWriter) WriteRow(row []any) error {
+	cells := make([]any, len(row))
+
+	for i, val := range row {
+		cell, err := w.cell(i, val)
+		if err != nil {
+			return err
+		}
+
+		cells[i] = cell
+	}
+
+	if w.sheet == nil && len(w.sample) < xlsxSampleRows {
+		w.sample = append(w.sample, cells)
+		return nil
+	}
+
+	return w.writeCells(cells)
+}
+
+func (w *xlsxWriter) Close() error {
+	defer w.file.Close()
+
+	if w.sheet == nil {
+		if err := w.nextSheet(); err != nil {
+			return err
+		}
+	}
+
+	if err := w.sheet.Flush(); err != nil {
+		return err
+	}
+
+	return w.file.Write(w.w)
+}
+
+func (w *xlsxWriter) writeCells(cells []any) error {
+	if w.sheet == nil || w.sheetRows == w.maxSheetRows {
+		if err := w.nextSheet(); err != nil {
+			return err
+		}
+	}
+
+	w.sheetRows++
+
+	cell, err := excelize.CoordinatesToCellName(1, w.sheetRows+1)
+	if err != nil {
+		return err
+	}
+
+	return w.sheet.SetRow(cell, cells)
+}
+
+// nextSheet completes current sheet and starts new one with frozen header, sampled rows go first
+func (w *xlsxWriter) nextSheet() error {
+	if w.sheet != nil {
+		if err := w.sheet.Flush(); err != nil {
+			return err
+		}
+	}
+
+	w.sheets++
+
+	name := fmt.Sprintf("Sheet%d", w.sheets)
+
+	// the first sheet comes with new file
+	if w.sheets > 1 {
+		if _, err := w.file.NewSheet(name); err != nil {
+			return err
+		}
+	}
+
+	sheet, err := w.file.NewStreamWriter(name)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range w.sample {
+		for i, cell := range row {
+			w.widen(i, cell)
+		}
+	}
+
+	for i, width := range w.widths {
+		if err = sheet.SetColWidth(i+1, i+1, float64(min(width+2, xlsxMaxWidth))); err != nil {
+			return err
+		}
+	}
+
+	if len(w.columns) > 0 {
+		if err = sheet.SetPanes(&excelize.Panes{
+			Freeze:      true,
+			YSplit:      1,
+			TopLeftCell: "A2",
+			ActivePane:  "bottomLeft",
+		}); err != nil {
+			return err
+		}
+	}
+
+	header := make([]any, len(w.columns))
+
+	for i, column := range w.columns {
+		header[i] = excelize.Cell{StyleID: w.headerStyle, Value: column.Name}
+	}
+
+	if err = sheet.SetRow("A1", header); err != nil {
+		return err
+	}
+
+	w.sheet = sheet
+	w.sheetRows = 0
+
+	sample := w.sample
+	w.sample = nil
+
+	for _, cells := range sample {
+		if err = w.writeCells(cells); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cell converts value of column i to typed cell value, values not matching column type are written as text
+func (w *xlsxWriter) cell(i int, val any) (any, error) {
+	if val == nil {
+		return nil, nil
+	}
+
+	switch w.kinds[i] {
+	case enginerepo.KindNumeric:
+		if number, ok := xlsxNumber(val); ok {
+			return number, nil
+		}
+
+		switch v := val.(type) {
+		case string, []byte:
+			text := fmt.Sprintf("%s", v)
+
+			if n, err := strconv.ParseInt(text, 10, 64); err == nil {
+				return xlsxInteger(n), nil
+			}
+
+			// decimals which would lose digits stay text
+			if isSafeDecimal(text) {
+				if f, err := strconv.ParseFloat(text, 64); err == nil {
+					return f, nil
+				}
+			}
+
+			return text, nil
+		}
+
+	case enginerepo.KindTemporal:
+		t, ok := val.(time.Time)
+
+		if text, isText := val.(string); isText {
+			t, ok = parseTemporal(text)
+		}
+
+		if ok {
+			style := w.dateTimeStyle
+			if w.columns[i].Type == "date" {
+				style = w.dateStyle
+			}
+
+			return excelize.Cell{StyleID: style, Value: t}, nil
+		}
+
+	case enginerepo.KindBoolean:
+		switch v := val.(type) {
+		case bool:
+			return v, nil
+
+		case int64:
+			return v != 0, nil
+
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, nil
+			}
+		}
+	}
+
+	// untyped columns of sqlite keep typed values
+	if number, ok := xlsxNumber(val); ok {
+		return number, nil
+	}
+
+	switch v := val.(type) {
+	case bool:
+		return v, nil
+
+	case time.Time:
+		return excelize.Cell{StyleID: w.dateTimeStyle, Value: v}, nil
+	}
+
+	return formatValue(w.valueKinds[i], val)
+}
+
+// xlsxNumber returns cell of numeric value, integers beyond maxSafeInteger are written as text
+// as excel keeps 15 significant digits
+func xlsxNumber(val any) (any, bool) {
+	switch v := val.(type) {
+	case int64:
+		return xlsxInteger(v), true
+
+	case uint64:
+		if v <= maxSafeInteger {
+			return int64(v), true
+		}
+
+		return strconv.FormatUint(v, 10), true
+
+	case float64, float32:
+		return v, true
+	}
+
+	return nil, false
+}
+
+func xlsxInteger(n int64) any {
+	if n > maxSafeInteger || n < -maxSafeInteger {
+		return strconv.FormatInt(n, 10)
+	}
+
+	return n
+}
+
+func (w *xlsxWriter) widen(i int, cell any) {
+	var width int
+
+	switch v := cell.(type) {
+	case nil:
+		return
+
+	case string:
+		width = utf8.RuneCountInString(v)
+
+	case excelize.Cell:
+		width = len("2006-01-02 15:04:05")
+
+	default:
+		width = len(fmt.Sprint(v))
+	}
+
+	w.widths[i] = max(w.widths[i], width)
+}
+
+func parseTemporal(text string) (time.Time, bool) {
+	for _, layout := range temporalLayouts {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func strPtr(s string) *string {
+	return &s
+}
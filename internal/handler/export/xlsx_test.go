@@ -0,0 +1,176 @@
+package export
+
+import (
+	"bytes"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/xuri/excelize/v2"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+func writeXLSX(t *testing.T, maxSheetRows int, columns []entity.QueryColumn, rows [][]any) *excelize.File {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	w, err := newXLSXWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if maxSheetRows > 0 {
+		w.maxSheetRows = maxSheetRows
+	}
+
+	if err = w.WriteColumns(columns); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, row := range rows {
+		if err = w.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := excelize.OpenReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { f.Close() })
+
+	return f
+}
+
+func TestXLSXWriterCells(t *testing.T) {
+	columns := []entity.QueryColumn{
+		{Name: "id", Type: "int8"},
+		{Name: "amount", Type: "numeric"},
+		{Name: "at", Type: "timestamp"},
+		{Name: "active", Type: "bool"},
+		{Name: "name", Type: "text"},
+	}
+
+	at := time.Date(2024, 5, 30, 10, 24, 15, 0, time.UTC)
+
+	f := writeXLSX(t, 0, columns, [][]any{
+		{int64(42), "12.50", at, true, "a"},
+		{int64(9007199254740993), "12345678901234567.89", "2024-05-30 10:24:15", "false", "007"},
+		{nil, "NaN", nil, nil, nil},
+	})
+
+	tests := []struct {
+		cell     string
+		wantType excelize.CellType
+		want     string
+	}{
+		// numbers are written without type attribute
+		{"A2", excelize.CellTypeUnset, "42"},
+		{"B2", excelize.CellTypeUnset, "12.5"},
+		{"D2", excelize.CellTypeBool, "TRUE"},
+		{"E2", excelize.CellTypeInlineString, "a"},
+		{"A3", excelize.CellTypeInlineString, "9007199254740993"},
+		{"B3", excelize.CellTypeInlineString, "12345678901234567.89"},
+		{"D3", excelize.CellTypeBool, "FALSE"},
+		{"E3", excelize.CellTypeInlineString, "007"},
+		{"B4", excelize.CellTypeInlineString, "NaN"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.cell, func(t *testing.T) {
+			cellType, err := f.GetCellType("Sheet1", tt.cell)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if cellType != tt.wantType {
+				t.Errorf("type = %v, want %v", cellType, tt.wantType)
+			}
+
+			value, err := f.GetCellValue("Sheet1", tt.cell, excelize.Options{RawCellValue: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if cellType == excelize.CellTypeBool {
+				value = map[string]string{"1": "TRUE", "0": "FALSE"}[value]
+			}
+
+			if value != tt.want {
+				t.Errorf("value = %q, want %q", value, tt.want)
+			}
+		})
+	}
+
+	// temporal values are dates with number format, typed the same regardless of driver representation
+	for _, cell := range []string{"C2", "C3"} {
+		value, err := f.GetCellValue("Sheet1", cell)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if value != "2024-05-30 10:24:15" {
+			t.Errorf("%s = %q, want 2024-05-30 10:24:15", cell, value)
+		}
+	}
+}
+
+func TestXLSXWriterSheets(t *testing.T) {
+	rows := make([][]any, 5)
+	for i := range rows {
+		rows[i] = []any{int64(i + 1)}
+	}
+
+	f := writeXLSX(t, 2, []entity.QueryColumn{{Name: "id", Type: "int8"}}, rows)
+
+	if got, want := f.GetSheetList(), []string{"Sheet1", "Sheet2", "Sheet3"}; !slices.Equal(got, want) {
+		t.Fatalf("sheets = %v, want %v", got, want)
+	}
+
+	wantRows := [][]string{
+		{"id", "1", "2"},
+		{"id", "3", "4"},
+		{"id", "5"},
+	}
+
+	for i, sheet := range f.GetSheetList() {
+		got, err := f.GetRows(sheet)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		values := make([]string, len(got))
+		for j, row := range got {
+			values[j] = row[0]
+		}
+
+		if !slices.Equal(values, wantRows[i]) {
+			t.Errorf("%s rows = %v, want %v", sheet, values, wantRows[i])
+		}
+
+		panes, err := f.GetPanes(sheet)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !panes.Freeze || panes.YSplit != 1 || panes.TopLeftCell != "A2" {
+			t.Errorf("%s panes = %+v, want header frozen", sheet, panes)
+		}
+
+		style, err := f.GetCellStyle(sheet, "A1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if header, err := f.GetStyle(style); err != nil || header.Font == nil || !header.Font.Bold {
+			t.Errorf("%s header style = %+v, want bold", sheet, header)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/mapper"
+
+	connectionrepo "db-dashboards/internal/repository/connection"
+	historyrepo "db-dashboards/internal/repository/history"
+	auditservice "db-dashboards/internal/service/audit"
+	connectionservice "db-dashboards/internal/service/connection"
+	historyservice "db-dashboards/internal/service/history"
+
+	handlerinternalutils "db-dashboards/internal/handler/utils"
+	handlerutils "db-dashboards/pkg/utils/handler"
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+type Service interface {
+	GetEntries(ctx context.Context, filter entity.HistoryFilter) ([]*entity.HistoryEntry, error)
+	SetStarred(ctx context.Context, userID, id int, starred bool) (*entity.HistoryEntry, error)
+	Rerun(ctx context.Context, userID, id int) (*entity.QueryResult, error)
+}
+
+type Middleware = func(http.Handler) http.Handler
+
+type Handler struct {
+	Service     Service
+	Middlewares []Middleware
+
+	logger    *logrus.Logger
+	validator *validator.Validate
+}
+
+func New(service Service,
+	logger *logrus.Logger,
+	validator *validator.Validate,
+	middlewares ...Middleware,
+) *Handler {
+	return &Handler{
+		Service:     service,
+		Middlewares: middlewares,
+		logger:      logger,
+		validator:   validator,
+	}
+}
+
+func (h *Handler) Routes() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(h.Middlewares...)
+
+		r.Get("/", h.GetAll)
+		r.Put("/{id}/star", h.Star)
+		r.Delete("/{id}/star", h.Unstar)
+		r.Post("/{id}/rerun", h.Rerun)
+	})
+
+	return router
+}
+
+// GetAll godoc
+//
+//	@Summary		Get query history
+//	@Description	Get queries executed by user from query console, saved queries and dashboard widgets, the most recent first
+//	@Security		JWT
+//	@Tags			History
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			connection_id	query		int		false	"connection id"
+//	@Param			starred			query		bool	false	"only starred entries"
+//	@Param			search			query		string	false	"substring of sql"
+//	@Param			offset			query		int		false	"Offset"
+//	@Param			limit			query		int		false	"Limit"
+//	@Success		200				{object}	[]response.HistoryEntryResponse
+//	@Failure		400				{string}	invalid		filter	provided
+//	@Failure		401				{string}	Unauthorized
+//	@Failure		500				{string}	internal	error
+//	@Router			/db-dashboards/api/v1/history [get]
+func (h *Handler) GetAll(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	getReq := handlerinternalutils.GetHistoryRequestFromQuery(req)
+
+	if err = getReq.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid filter provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	filter, err := mapper.MapGetHistoryRequestToHistoryFilter(&getReq, userID)
+	if err != nil {
+		msg := fmt.Sprintf("invalid filter provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	entries, err := h.Service.GetEntries(req.Context(), filter)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred fetching query history: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+		return
+	}
+
+	render.JSON(rw, req, sliceutils.Map(entries, mapper.MapHistoryEntryToHistoryEntryResponse))
+}
+
+// Star godoc
+//
+//	@Summary		Star history entry
+//	@Description	Star history entry so that it can be found among starred queries
+//	@Security		JWT
+//	@Tags			History
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			id	path		int	true	"history entry id"
+//	@Success		200	{object}	response.HistoryEntryResponse
+//	@Failure		400	{string}	invalid	history	entry	id	provided
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		404	{string}	history		entry	not	found
+//	@Failure		500	{string}	internal	error
+//	@Router			/db-dashboards/api/v1/history/{id}/star [put]
+func (h *Handler) Star(rw http.ResponseWriter, req *http.Request) {
+	h.setStarred(rw, req, true)
+}
+
+// Unstar godoc
+//
+//	@Summary		Unstar history entry
+//	@Description	Remove star from history entry
+//	@Security		JWT
+//	@Tags			History
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			id	path		int	true	"history entry id"
+//	@Success		200	{object}	response.HistoryEntryResponse
+//	@Failure		400	{string}	invalid	history	entry	id	provided
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		404	{string}	history		entry	not	found
+//	@Failure		500	{string}	internal	error
+//	@Router			/db-dashboards/api/v1/history/{id}/star [delete]
+func (h *Handler) Unstar(rw http.ResponseWriter, req *http.Request) {
+	h.setStarred(rw, req, false)
+}
+
+func (h *Handler) setStarred(rw http.ResponseWriter, req *http.Request, starred bool) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid history entry id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	entry, err := h.Service.SetStarred(req.Context(), userID, id, starred)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred starring history entry: %v", err)
+
+		status := http.StatusInternalServerError
+		if errors.Is(err, historyrepo.ErrHistoryEntryNotFound) {
+			status = http.StatusNotFound
+		}
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, status, msg, msg)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapHistoryEntryToHistoryEntryResponse(entry))
+}
+
+// Rerun godoc
+//
+//	@Summary		Rerun query from history
+//	@Description	Replay query of history entry with the same params against the same connection,
+//	@Description	access to connection is checked again. Replay is recorded to history as new entry
+//	@Security		JWT
+//	@Tags			History
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			id	path		int	true	"history entry id"
+//	@Success		200	{object}	response.QueryResultResponse
+//	@Failure		400	{string}	invalid	query
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	insufficient	access
+//	@Failure		404	{string}	history			entry	or	connection	not	found
+//	@Failure		502	{string}	cannot			connect	to	database
+//	@Failure		504	{string}	query			timed	out
+//	@Router			/db-dashboards/api/v1/history/{id}/rerun [post]
+func (h *Handler) Rerun(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid history entry id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	result, err := h.Service.Rerun(req.Context(), userID, id)
+
+	switch {
+	case errors.Is(err, historyservice.ErrNotRecorded):
+		h.logger.WithError(err).Errorf("can't record query to history")
+
+	case err != nil:
+		h.writeExecuteErr(rw, fmt.Sprintf("cannot rerun query: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapQueryResultToQueryResultResponse(result))
+}
+
+// writeExecuteErr treats errors returned by target database as client errors
+func (h *Handler) writeExecuteErr(rw http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, historyrepo.ErrHistoryEntryNotFound), errors.Is(err, connectionrepo.ErrConnectionNotFound):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusNotFound, msg, msg)
+
+	case errors.Is(err, connectionservice.ErrAccessDenied):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusForbidden, msg, msg)
+
+	case errors.Is(err, connectionservice.ErrCannotConnect):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadGateway, msg, msg)
+
+	case errors.Is(err, context.DeadlineExceeded):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusGatewayTimeout, msg, msg)
+
+	case errors.Is(err, auditservice.ErrAuditFailed):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+
+	default:
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+	}
+}
@@ -0,0 +1,44 @@
+package mapper
+
+import (
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/request"
+	"db-dashboards/internal/handler/response"
+
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+func MapAggregateRequestToAggregateQuery(aggReq *request.AggregateRequest) entity.AggregateQuery {
+	q := entity.AggregateQuery{
+		Schema:     aggReq.Schema,
+		Table:      aggReq.Table,
+		Dimensions: aggReq.Dimensions,
+		Measures: sliceutils.Map(aggReq.Measures, func(m request.MeasureRequest) entity.Measure {
+			return entity.Measure{Func: entity.MeasureFunc(m.Func), Column: m.Column, Alias: m.Alias}
+		}),
+		Filters: sliceutils.Map(aggReq.Filters, func(f request.FilterRequest) entity.Filter {
+			return entity.Filter{Column: f.Column, Op: entity.FilterOp(f.Op), Values: f.Values}
+		}),
+		Order: sliceutils.Map(aggReq.Order, func(o request.OrderRequest) entity.Sort {
+			return entity.Sort{Column: o.Column, Desc: o.Desc}
+		}),
+		Limit: aggReq.Limit,
+	}
+
+	if aggReq.TimeGrain != nil {
+		q.TimeGrain = &entity.TimeGrain{
+			Column: aggReq.TimeGrain.Column,
+			Grain:  entity.TimeBucket(aggReq.TimeGrain.Grain),
+		}
+	}
+
+	return q
+}
+
+func MapAggregateResultToAggregateResponse(result *entity.AggregateResult) response.AggregateResponse {
+	return response.AggregateResponse{
+		SQL:    result.SQL,
+		Params: result.Args,
+		Result: MapQueryResultToQueryResultResponse(result.Result),
+	}
+}
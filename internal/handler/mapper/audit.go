@@ -0,0 +1,80 @@
+package mapper
+
+import (
+	"strconv"
+	"time"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/request"
+	"db-dashboards/internal/handler/response"
+)
+
+func MapGetAuditRequestToAuditFilter(getReq *request.GetAuditRequest) (entity.AuditFilter, error) {
+	filter := entity.AuditFilter{
+		Operation: entity.AuditOperation(getReq.Operation),
+		Search:    getReq.Search,
+		Offset:    getReq.Offset,
+		Limit:     getReq.Limit,
+	}
+
+	if getReq.UserID != "" {
+		userID, err := strconv.Atoi(getReq.UserID)
+		if err != nil {
+			return entity.AuditFilter{}, err
+		}
+
+		filter.UserID = &userID
+	}
+
+	if getReq.ConnectionID != "" {
+		connectionID, err := strconv.Atoi(getReq.ConnectionID)
+		if err != nil {
+			return entity.AuditFilter{}, err
+		}
+
+		filter.ConnectionID = &connectionID
+	}
+
+	if getReq.Status != "" {
+		failed := getReq.Status == "failed"
+		filter.Failed = &failed
+	}
+
+	if getReq.From != "" {
+		from, err := time.Parse(time.RFC3339, getReq.From)
+		if err != nil {
+			return entity.AuditFilter{}, err
+		}
+
+		filter.From = &from
+	}
+
+	if getReq.To != "" {
+		to, err := time.Parse(time.RFC3339, getReq.To)
+		if err != nil {
+			return entity.AuditFilter{}, err
+		}
+
+		filter.To = &to
+	}
+
+	return filter, nil
+}
+
+func MapAuditEntryToAuditEntryResponse(entry *entity.AuditEntry) response.AuditEntryResponse {
+	return response.AuditEntryResponse{
+		ID:           entry.ID,
+		UserID:       entry.UserID,
+		UserEmail:    entry.UserEmail,
+		ConnectionID: entry.ConnectionID,
+		WorkspaceID:  entry.WorkspaceID,
+		Engine:       entry.Engine,
+		Operation:    string(entry.Operation),
+		Statement:    entry.Statement,
+		Params:       entry.Params,
+		DurationMs:   entry.DurationMs,
+		RowsReturned: entry.RowsReturned,
+		Error:        entry.Error,
+		CreatedAt:    entry.CreatedAt,
+	}
+}
@@ -0,0 +1,37 @@
+package mapper
+
+import (
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/request"
+	"db-dashboards/internal/handler/response"
+)
+
+func MapConnectionToConnectionResponse(conn *entity.Connection) response.GetConnectionResponse {
+	return response.GetConnectionResponse{
+		ID:          conn.ID,
+		Name:        conn.Name,
+		Engine:      conn.Engine,
+		OwnerID:     conn.UserID,
+		WorkspaceID: conn.WorkspaceID,
+		Access:      string(conn.Access),
+		CreatedAt:   conn.CreatedAt,
+		UpdatedAt:   conn.UpdatedAt,
+	}
+}
+
+func MapCreateConnectionRequestToConnectionEntity(createReq *request.CreateConnectionRequest, userID int) entity.Connection {
+	return entity.Connection{
+		UserID:       userID,
+		Name:         createReq.Name,
+		Engine:       createReq.Engine,
+		EncryptedDSN: createReq.DSN,
+	}
+}
+
+func MapUpdateConnectionRequestToConnectionEntity(updateReq *request.UpdateConnectionRequest, id int) entity.Connection {
+	return entity.Connection{
+		ID:           id,
+		Name:         updateReq.Name,
+		EncryptedDSN: updateReq.DSN,
+	}
+}
@@ -0,0 +1,118 @@
+package mapper
+
+import (
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/request"
+	"db-dashboards/internal/handler/response"
+
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+func MapDashboardToDashboardResponse(dashboard *entity.Dashboard) response.GetDashboardResponse {
+	return response.GetDashboardResponse{
+		ID:          dashboard.ID,
+		Title:       dashboard.Title,
+		Description: dashboard.Description,
+		OwnerID:     dashboard.UserID,
+		WorkspaceID: dashboard.WorkspaceID,
+		Access:      string(dashboard.Access),
+		CreatedAt:   dashboard.CreatedAt,
+		UpdatedAt:   dashboard.UpdatedAt,
+	}
+}
+
+func MapDashboardToDashboardWithWidgetsResponse(dashboard *entity.Dashboard, widgets []*entity.Widget) response.GetDashboardWithWidgetsResponse {
+	return response.GetDashboardWithWidgetsResponse{
+		GetDashboardResponse: MapDashboardToDashboardResponse(dashboard),
+		Widgets:              sliceutils.Map(widgets, MapWidgetToWidgetResponse),
+	}
+}
+
+func MapCreateDashboardRequestToDashboardEntity(createReq *request.CreateDashboardRequest, userID int) entity.Dashboard {
+	return entity.Dashboard{
+		UserID:      userID,
+		Title:       createReq.Title,
+		Description: createReq.Description,
+	}
+}
+
+func MapUpdateDashboardRequestToDashboardEntity(updateReq *request.UpdateDashboardRequest, id int) entity.Dashboard {
+	return entity.Dashboard{
+		ID:          id,
+		Title:       updateReq.Title,
+		Description: updateReq.Description,
+	}
+}
+
+func MapWidgetToWidgetResponse(widget *entity.Widget) response.GetWidgetResponse {
+	return response.GetWidgetResponse{
+		ID:           widget.ID,
+		DashboardID:  widget.DashboardID,
+		Title:        widget.Title,
+		Type:         string(widget.Type),
+		SavedQueryID: widget.SavedQueryID,
+		ConnectionID: widget.ConnectionID,
+		SQL:          widget.SQL,
+		Layout: response.WidgetLayoutResponse{
+			X:      widget.GridX,
+			Y:      widget.GridY,
+			Width:  widget.Width,
+			Height: widget.Height,
+		},
+		Position:        widget.Position,
+		RefreshInterval: widget.RefreshInterval,
+		Options: response.WidgetOptionsResponse{
+			XColumn:    widget.Options.XColumn,
+			YColumns:   widget.Options.YColumns,
+			GroupBy:    widget.Options.GroupBy,
+			TimeBucket: string(widget.Options.TimeBucket),
+			Aggregate:  string(widget.Options.Aggregate),
+		},
+		CreatedAt: widget.CreatedAt,
+		UpdatedAt: widget.UpdatedAt,
+	}
+}
+
+func MapWidgetRequestToWidgetEntity(widgetReq *request.WidgetRequest, dashboardID, id int) entity.Widget {
+	return entity.Widget{
+		ID:              id,
+		DashboardID:     dashboardID,
+		Title:           widgetReq.Title,
+		Type:            entity.WidgetType(widgetReq.Type),
+		SavedQueryID:    widgetReq.SavedQueryID,
+		ConnectionID:    widgetReq.ConnectionID,
+		SQL:             widgetReq.SQL,
+		GridX:           widgetReq.Layout.X,
+		GridY:           widgetReq.Layout.Y,
+		Width:           widgetReq.Layout.Width,
+		Height:          widgetReq.Layout.Height,
+		RefreshInterval: widgetReq.RefreshInterval,
+		Options: entity.WidgetOptions{
+			XColumn:    widgetReq.Options.XColumn,
+			YColumns:   widgetReq.Options.YColumns,
+			GroupBy:    widgetReq.Options.GroupBy,
+			TimeBucket: entity.TimeBucket(widgetReq.Options.TimeBucket),
+			Aggregate:  entity.Aggregate(widgetReq.Options.Aggregate),
+		},
+	}
+}
+
+func MapChartSeriesToChartSeriesResponse(series entity.ChartSeries) response.ChartSeriesResponse {
+	return response.ChartSeriesResponse{
+		Name: series.Name,
+		Points: sliceutils.Map(series.Points, func(point entity.ChartPoint) response.ChartPointResponse {
+			return response.ChartPointResponse{X: point.X, Y: point.Y}
+		}),
+	}
+}
+
+func MapWidgetDataToWidgetDataResponse(data *entity.WidgetData) response.WidgetDataResponse {
+	return response.WidgetDataResponse{
+		Type:      string(data.Type),
+		Columns:   sliceutils.Map(data.Columns, MapQueryColumnToQueryColumnResponse),
+		Rows:      MapRowsToJSONRows(data.Columns, data.Rows),
+		XColumn:   data.XColumn,
+		Series:    sliceutils.Map(data.Series, MapChartSeriesToChartSeriesResponse),
+		Truncated: data.Truncated,
+	}
+}
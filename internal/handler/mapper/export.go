@@ -0,0 +1,20 @@
+package mapper
+
+import (
+	"unicode/utf8"
+
+	"db-dashboards/internal/handler/export"
+	"db-dashboards/internal/handler/request"
+)
+
+func MapExportRequestToExportOptions(exportReq *request.ExportRequest) (export.Format, export.Options) {
+	opts := export.Options{
+		Null: exportReq.Null,
+	}
+
+	if exportReq.Delimiter != "" {
+		opts.Delimiter, _ = utf8.DecodeRuneInString(exportReq.Delimiter)
+	}
+
+	return export.Format(exportReq.Format), opts
+}
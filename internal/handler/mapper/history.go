@@ -0,0 +1,59 @@
+package mapper
+
+import (
+	"strconv"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/request"
+	"db-dashboards/internal/handler/response"
+)
+
+func MapGetHistoryRequestToHistoryFilter(getReq *request.GetHistoryRequest, userID int) (entity.HistoryFilter, error) {
+	filter := entity.HistoryFilter{
+		UserID: userID,
+		Search: getReq.Search,
+		Offset: getReq.Offset,
+		Limit:  getReq.Limit,
+	}
+
+	if getReq.ConnectionID != "" {
+		connectionID, err := strconv.Atoi(getReq.ConnectionID)
+		if err != nil {
+			return entity.HistoryFilter{}, err
+		}
+
+		filter.ConnectionID = &connectionID
+	}
+
+	if getReq.Starred != "" {
+		starred, err := strconv.ParseBool(getReq.Starred)
+		if err != nil {
+			return entity.HistoryFilter{}, err
+		}
+
+		filter.Starred = starred
+	}
+
+	return filter, nil
+}
+
+func MapHistoryEntryToHistoryEntryResponse(entry *entity.HistoryEntry) response.HistoryEntryResponse {
+	status := "succeeded"
+	if entry.Error != "" {
+		status = "failed"
+	}
+
+	return response.HistoryEntryResponse{
+		ID:           entry.ID,
+		WorkspaceID:  entry.WorkspaceID,
+		ConnectionID: entry.ConnectionID,
+		SQL:          entry.SQL,
+		Params:       entry.Params,
+		DurationMs:   entry.DurationMs,
+		RowCount:     entry.RowCount,
+		Status:       status,
+		Error:        entry.Error,
+		Starred:      entry.Starred,
+		CreatedAt:    entry.CreatedAt,
+	}
+}
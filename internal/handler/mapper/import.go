@@ -0,0 +1,68 @@
+package mapper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/request"
+	"db-dashboards/internal/handler/response"
+
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+var ErrInvalidMapping = errors.New("invalid mapping, expected file_column:table_column")
+
+func MapImportRequestToImport(importReq *request.ImportRequest) (entity.Import, error) {
+	imp := entity.Import{
+		Schema: importReq.Schema,
+		Table:  importReq.Table,
+		Format: entity.ImportFormat(importReq.Format),
+		Null:   importReq.Null,
+		DryRun: importReq.DryRun,
+	}
+
+	if importReq.Delimiter != "" {
+		imp.Delimiter, _ = utf8.DecodeRuneInString(importReq.Delimiter)
+	}
+
+	if len(importReq.Mapping) > 0 {
+		imp.Mapping = make(map[string]string, len(importReq.Mapping))
+	}
+
+	for _, raw := range importReq.Mapping {
+		// file columns are less constrained than table ones, so the last colon separates them
+		i := strings.LastIndexByte(raw, ':')
+		if i <= 0 || i == len(raw)-1 {
+			return entity.Import{}, fmt.Errorf("%w: %v", ErrInvalidMapping, raw)
+		}
+
+		fileColumn, tableColumn := raw[:i], raw[i+1:]
+
+		if _, ok := imp.Mapping[fileColumn]; ok {
+			return entity.Import{}, fmt.Errorf("%w: %v is mapped twice", ErrInvalidMapping, fileColumn)
+		}
+
+		imp.Mapping[fileColumn] = tableColumn
+	}
+
+	return imp, nil
+}
+
+func MapImportResultToImportResultResponse(result *entity.ImportResult) response.ImportResultResponse {
+	return response.ImportResultResponse{
+		Rows:        result.Rows,
+		Inserted:    result.Inserted,
+		InvalidRows: result.InvalidRows,
+		Errors: sliceutils.Map(result.Errors, func(importErr entity.ImportError) response.ImportErrorResponse {
+			return response.ImportErrorResponse{
+				Row:     importErr.Row,
+				Column:  importErr.Column,
+				Message: importErr.Message,
+			}
+		}),
+		DryRun: result.DryRun,
+	}
+}
@@ -0,0 +1,43 @@
+package mapper
+
+import (
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/request"
+	"db-dashboards/internal/handler/response"
+)
+
+func MapConnectionPermissionToConnectionPermissionResponse(perm *entity.ConnectionPermission) response.ConnectionPermissionResponse {
+	return response.ConnectionPermissionResponse{
+		ConnectionID: perm.ConnectionID,
+		UserID:       perm.UserID,
+		Access:       string(perm.Access),
+		CreatedAt:    perm.CreatedAt,
+		UpdatedAt:    perm.UpdatedAt,
+	}
+}
+
+func MapConnectionPermissionRequestToConnectionPermissionEntity(permReq *request.ConnectionPermissionRequest, connectionID int) entity.ConnectionPermission {
+	return entity.ConnectionPermission{
+		ConnectionID: connectionID,
+		UserID:       permReq.UserID,
+		Access:       entity.ConnectionAccess(permReq.Access),
+	}
+}
+
+func MapDashboardPermissionToDashboardPermissionResponse(perm *entity.DashboardPermission) response.DashboardPermissionResponse {
+	return response.DashboardPermissionResponse{
+		DashboardID: perm.DashboardID,
+		UserID:      perm.UserID,
+		Access:      string(perm.Access),
+		CreatedAt:   perm.CreatedAt,
+		UpdatedAt:   perm.UpdatedAt,
+	}
+}
+
+func MapDashboardPermissionRequestToDashboardPermissionEntity(permReq *request.DashboardPermissionRequest, dashboardID int) entity.DashboardPermission {
+	return entity.DashboardPermission{
+		DashboardID: dashboardID,
+		UserID:      permReq.UserID,
+		Access:      entity.DashboardAccess(permReq.Access),
+	}
+}
@@ -0,0 +1,51 @@
+package mapper
+
+import (
+	"encoding/json"
+	"math"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/export"
+	"db-dashboards/internal/handler/response"
+
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+// MapQueryParams turns whole json numbers into integers so that drivers bind them to integer columns
+func MapQueryParams(params []any) []any {
+	return sliceutils.Map(params, func(param any) any {
+		switch v := param.(type) {
+		case json.Number:
+			return entity.ParamFromNumber(v)
+
+		case float64:
+			if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+				return int64(v)
+			}
+		}
+
+		return param
+	})
+}
+
+func MapQueryColumnToQueryColumnResponse(column entity.QueryColumn) response.QueryColumnResponse {
+	return response.QueryColumnResponse{
+		Name: column.Name,
+		Type: column.Type,
+	}
+}
+
+func MapQueryResultToQueryResultResponse(result *entity.QueryResult) response.QueryResultResponse {
+	return response.QueryResultResponse{
+		Columns:   sliceutils.Map(result.Columns, MapQueryColumnToQueryColumnResponse),
+		Rows:      MapRowsToJSONRows(result.Columns, result.Rows),
+		Truncated: result.Truncated,
+	}
+}
+
+// MapRowsToJSONRows converts driver values of rows to stable json values, see export.JSONEncoder
+func MapRowsToJSONRows(columns []entity.QueryColumn, rows [][]any) [][]any {
+	encoder := export.NewJSONEncoder(columns)
+
+	return sliceutils.Map(rows, encoder.Encode)
+}
@@ -0,0 +1,143 @@
+package mapper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/request"
+	"db-dashboards/internal/handler/response"
+
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+var (
+	ErrInvalidFilter = errors.New("invalid filter, expected column:op[:value]")
+	ErrInvalidSort   = errors.New("invalid sort, expected comma separated columns prefixed with - for descending order")
+)
+
+func MapGetRowsRequestToRowsQuery(getReq *request.GetRowsRequest) (entity.RowsQuery, error) {
+	q := entity.RowsQuery{
+		Schema: getReq.Schema,
+		Table:  getReq.Table,
+		Limit:  getReq.Limit,
+		Offset: getReq.Offset,
+		Cursor: getReq.Cursor,
+		Count:  entity.CountMode(getReq.Count),
+	}
+
+	var err error
+
+	if q.Sort, err = mapSort(getReq.Sort); err != nil {
+		return entity.RowsQuery{}, err
+	}
+
+	if q.Filters, err = mapFilters(getReq.Filters); err != nil {
+		return entity.RowsQuery{}, err
+	}
+
+	return q, nil
+}
+
+func MapStreamRowsRequestToRowsQuery(streamReq *request.StreamRowsRequest) (entity.RowsQuery, error) {
+	q := entity.RowsQuery{
+		Schema: streamReq.Schema,
+		Table:  streamReq.Table,
+		Limit:  streamReq.Limit,
+	}
+
+	var err error
+
+	if q.Sort, err = mapSort(streamReq.Sort); err != nil {
+		return entity.RowsQuery{}, err
+	}
+
+	if q.Filters, err = mapFilters(streamReq.Filters); err != nil {
+		return entity.RowsQuery{}, err
+	}
+
+	return q, nil
+}
+
+// mapSort parses comma separated columns prefixed with - for descending order
+func mapSort(raw string) ([]entity.Sort, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var sort []entity.Sort
+
+	for _, column := range strings.Split(raw, ",") {
+		desc := strings.HasPrefix(column, "-")
+		column = strings.TrimPrefix(column, "-")
+
+		if column == "" {
+			return nil, ErrInvalidSort
+		}
+
+		sort = append(sort, entity.Sort{Column: column, Desc: desc})
+	}
+
+	return sort, nil
+}
+
+func mapFilters(raws []string) ([]entity.Filter, error) {
+	var filters []entity.Filter
+
+	for _, raw := range raws {
+		filter, err := mapFilter(raw)
+		if err != nil {
+			return nil, err
+		}
+
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+// mapFilter parses column:op[:value], value of in operator is comma separated list
+func mapFilter(raw string) (entity.Filter, error) {
+	parts := strings.SplitN(raw, ":", 3)
+	if len(parts) < 2 || parts[0] == "" {
+		return entity.Filter{}, fmt.Errorf("%w: %v", ErrInvalidFilter, raw)
+	}
+
+	filter := entity.Filter{
+		Column: parts[0],
+		Op:     entity.FilterOp(parts[1]),
+	}
+
+	switch filter.Op {
+	case entity.FilterOpEq, entity.FilterOpNe, entity.FilterOpLt, entity.FilterOpGt, entity.FilterOpLike:
+		if len(parts) == 3 {
+			filter.Values = []string{parts[2]}
+		}
+
+	case entity.FilterOpIn:
+		if len(parts) == 3 {
+			filter.Values = strings.Split(parts[2], ",")
+		}
+
+	case entity.FilterOpIsNull, entity.FilterOpNotNull:
+		if len(parts) == 3 {
+			return entity.Filter{}, fmt.Errorf("%w: %v", ErrInvalidFilter, raw)
+		}
+
+	default:
+		return entity.Filter{}, fmt.Errorf("%w: unknown operator %v", ErrInvalidFilter, parts[1])
+	}
+
+	return filter, nil
+}
+
+func MapRowsPageToGetRowsResponse(page *entity.RowsPage) response.GetRowsResponse {
+	return response.GetRowsResponse{
+		Columns:        sliceutils.Map(page.Columns, MapQueryColumnToQueryColumnResponse),
+		Rows:           MapRowsToJSONRows(page.Columns, page.Rows),
+		Total:          page.Total,
+		TotalEstimated: page.TotalEstimated,
+		NextCursor:     page.NextCursor,
+	}
+}
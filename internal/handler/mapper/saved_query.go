@@ -0,0 +1,76 @@
+package mapper
+
+import (
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/request"
+	"db-dashboards/internal/handler/response"
+
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+func MapQueryParameterRequestToQueryParameter(param request.QueryParameterRequest) entity.QueryParameter {
+	return entity.QueryParameter{
+		Name:    param.Name,
+		Type:    entity.ParamType(param.Type),
+		Default: param.Default,
+	}
+}
+
+func MapQueryParameterToQueryParameterResponse(param entity.QueryParameter) response.QueryParameterResponse {
+	return response.QueryParameterResponse{
+		Name:    param.Name,
+		Type:    string(param.Type),
+		Default: param.Default,
+	}
+}
+
+func MapSavedQueryToSavedQueryResponse(query *entity.SavedQuery) response.GetSavedQueryResponse {
+	tags := query.Tags
+	if tags == nil {
+		tags = entity.Tags{}
+	}
+
+	return response.GetSavedQueryResponse{
+		ID:           query.ID,
+		OwnerID:      query.UserID,
+		WorkspaceID:  query.WorkspaceID,
+		ConnectionID: query.ConnectionID,
+		Name:         query.Name,
+		Description:  query.Description,
+		SQL:          query.SQL,
+		Parameters:   sliceutils.Map(query.Parameters, MapQueryParameterToQueryParameterResponse),
+		Tags:         tags,
+		CreatedAt:    query.CreatedAt,
+		UpdatedAt:    query.UpdatedAt,
+	}
+}
+
+func MapCreateSavedQueryRequestToSavedQueryEntity(createReq *request.CreateSavedQueryRequest, userID int) entity.SavedQuery {
+	return entity.SavedQuery{
+		UserID:       userID,
+		ConnectionID: createReq.ConnectionID,
+		Name:         createReq.Name,
+		Description:  createReq.Description,
+		SQL:          createReq.SQL,
+		Parameters:   sliceutils.Map(createReq.Parameters, MapQueryParameterRequestToQueryParameter),
+		Tags:         createReq.Tags,
+	}
+}
+
+// MapUpdateSavedQueryRequestToSavedQueryEntity keeps nil parameters and tags so that service does not overwrite them
+func MapUpdateSavedQueryRequestToSavedQueryEntity(updateReq *request.UpdateSavedQueryRequest, id int) entity.SavedQuery {
+	query := entity.SavedQuery{
+		ID:           id,
+		ConnectionID: updateReq.ConnectionID,
+		Name:         updateReq.Name,
+		Description:  updateReq.Description,
+		SQL:          updateReq.SQL,
+		Tags:         updateReq.Tags,
+	}
+
+	if updateReq.Parameters != nil {
+		query.Parameters = sliceutils.Map(updateReq.Parameters, MapQueryParameterRequestToQueryParameter)
+	}
+
+	return query
+}
@@ -10,6 +10,8 @@ func MapUserToUserResponse(user *entity.User) response.GetUserResponse {
 	return response.GetUserResponse{
 		ID:        user.ID,
 		Email:     user.Email,
+		Role:      string(user.Role),
+		Disabled:  user.DisabledAt != nil,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
 	}
@@ -0,0 +1,54 @@
+package mapper
+
+import (
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/request"
+	"db-dashboards/internal/handler/response"
+)
+
+func MapWorkspaceToWorkspaceResponse(ws *entity.Workspace) response.GetWorkspaceResponse {
+	return response.GetWorkspaceResponse{
+		ID:        ws.ID,
+		Name:      ws.Name,
+		Role:      string(ws.Role),
+		CreatedAt: ws.CreatedAt,
+		UpdatedAt: ws.UpdatedAt,
+	}
+}
+
+func MapWorkspaceRequestToWorkspaceEntity(wsReq *request.WorkspaceRequest, id int) entity.Workspace {
+	return entity.Workspace{
+		ID:   id,
+		Name: wsReq.Name,
+	}
+}
+
+func MapMembershipToMembershipResponse(member *entity.Membership) response.MembershipResponse {
+	return response.MembershipResponse{
+		WorkspaceID: member.WorkspaceID,
+		UserID:      member.UserID,
+		Email:       member.Email,
+		Role:        string(member.Role),
+		CreatedAt:   member.CreatedAt,
+		UpdatedAt:   member.UpdatedAt,
+	}
+}
+
+func MapInvitationToInvitationResponse(inv *entity.Invitation) response.InvitationResponse {
+	return response.InvitationResponse{
+		ID:          inv.ID,
+		WorkspaceID: inv.WorkspaceID,
+		Email:       inv.Email,
+		Role:        string(inv.Role),
+		InvitedBy:   inv.InvitedBy,
+		CreatedAt:   inv.CreatedAt,
+	}
+}
+
+func MapInviteMemberRequestToInvitationEntity(inviteReq *request.InviteMemberRequest, workspaceID int) entity.Invitation {
+	return entity.Invitation{
+		WorkspaceID: workspaceID,
+		Email:       inviteReq.Email,
+		Role:        entity.WorkspaceRole(inviteReq.Role),
+	}
+}
@@ -2,22 +2,35 @@ package middleware
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
 	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/domain/identity"
+
 	handlerutils "db-dashboards/pkg/utils/handler"
 	jwtutils "db-dashboards/pkg/utils/jwt"
-	"fmt"
-	"github.com/sirupsen/logrus"
-	"net/http"
-	"strconv"
+)
+
+var (
+	ErrNoID      = errors.New("invalid payload: not contains id")
+	ErrNoEmail   = errors.New("invalid payload: not contains email")
+	ErrNoSession = errors.New("invalid payload: not contains session id")
+	ErrNoRole    = errors.New("invalid payload: not contains valid role")
 )
 
 type Handler = func(http.Handler) http.Handler
 
-type Service interface {
-	Login(ctx context.Context, email, password string) (*entity.User, error)
+type SessionService interface {
+	IsSessionActive(ctx context.Context, sessionID string) (bool, error)
 }
 
-func JWTAuthMiddleware(secret string, logger *logrus.Logger) Handler {
+// JWTAuthMiddleware authenticates request by bearer access token of active session and puts user identity to request context
+func JWTAuthMiddleware(secret string, sessionService SessionService, logger *logrus.Logger) Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
 			authHeader := req.Header.Get("Authorization")
@@ -28,7 +41,13 @@ func JWTAuthMiddleware(secret string, logger *logrus.Logger) Handler {
 				return
 			}
 
-			token := authHeader[len("Bearer "):]
+			token, ok := strings.CutPrefix(authHeader, "Bearer ")
+			if !ok {
+				msg := "authorization header is not a bearer token"
+
+				handlerutils.WriteErrResponseAndLog(rw, logger, http.StatusUnauthorized, msg, msg)
+				return
+			}
 
 			payload, err := jwtutils.ValidateToken(token, secret)
 			if err != nil {
@@ -38,43 +57,60 @@ func JWTAuthMiddleware(secret string, logger *logrus.Logger) Handler {
 				return
 			}
 
-			// todo: to private func
-			idAny, exists := payload["id"]
-			if !exists {
-				msg := "invalid payload: not contains id"
+			id, err := identityFromPayload(payload)
+			if err != nil {
+				msg := err.Error()
 
 				handlerutils.WriteErrResponseAndLog(rw, logger, http.StatusUnauthorized, msg, msg)
 				return
 			}
 
-			id, ok := idAny.(float64)
-			if !ok {
-				msg := "cannot parse id from payload to float64"
+			active, err := sessionService.IsSessionActive(req.Context(), id.SessionID)
+			if err != nil {
+				msg := fmt.Sprintf("error occurred checking session: %v", err)
 
-				handlerutils.WriteErrResponseAndLog(rw, logger, http.StatusUnauthorized, msg, msg)
+				handlerutils.WriteErrResponseAndLog(rw, logger, http.StatusInternalServerError, msg, msg)
 				return
 			}
 
-			emailAny, exists := payload["email"]
-			if !exists {
-				msg := "invalid payload: not contains email"
+			if !active {
+				msg := "session revoked or expired"
 
 				handlerutils.WriteErrResponseAndLog(rw, logger, http.StatusUnauthorized, msg, msg)
 				return
 			}
 
-			email, ok := emailAny.(string)
-			if !ok {
-				msg := "cannot parse email from payload to string"
+			next.ServeHTTP(rw, req.WithContext(identity.WithIdentity(req.Context(), id)))
+		})
+	}
+}
 
-				handlerutils.WriteErrResponseAndLog(rw, logger, http.StatusUnauthorized, msg, msg)
-				return
-			}
+func identityFromPayload(payload map[string]any) (identity.Identity, error) {
+	// json numbers are decoded to float64
+	id, ok := payload["id"].(float64)
+	if !ok {
+		return identity.Identity{}, ErrNoID
+	}
+
+	email, ok := payload["email"].(string)
+	if !ok {
+		return identity.Identity{}, ErrNoEmail
+	}
 
-			req.Header.Set("id", strconv.Itoa(int(id)))
-			req.Header.Set("email", email)
+	sessionID, ok := payload["sid"].(string)
+	if !ok || sessionID == "" {
+		return identity.Identity{}, ErrNoSession
+	}
 
-			next.ServeHTTP(rw, req)
-		})
+	role, _ := payload["role"].(string)
+	if !entity.Role(role).Valid() {
+		return identity.Identity{}, ErrNoRole
 	}
+
+	return identity.Identity{
+		UserID:    int(id),
+		Email:     email,
+		SessionID: sessionID,
+		Role:      entity.Role(role),
+	}, nil
 }
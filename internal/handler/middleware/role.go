@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/domain/identity"
+
+	handlerutils "db-dashboards/pkg/utils/handler"
+)
+
+// RequireRole rejects requests of users having none of roles, it must be mounted after JWTAuthMiddleware
+func RequireRole(logger *logrus.Logger, roles ...entity.Role) Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			if _, err := identity.FromContext(req.Context()); err != nil {
+				msg := fmt.Sprintf("unauthenticated: %v", err)
+
+				handlerutils.WriteErrResponseAndLog(rw, logger, http.StatusUnauthorized, msg, msg)
+				return
+			}
+
+			if !identity.HasRole(req.Context(), roles...) {
+				msg := fmt.Sprintf("forbidden: one of roles %v required", roles)
+
+				handlerutils.WriteErrResponseAndLog(rw, logger, http.StatusForbidden, msg, msg)
+				return
+			}
+
+			next.ServeHTTP(rw, req)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/domain/identity"
+
+	workspacerepo "db-dashboards/internal/repository/workspace"
+	handlerutils "db-dashboards/pkg/utils/handler"
+)
+
+const (
+	WorkspaceHeader   = "X-Workspace-ID"
+	WorkspaceURLParam = "workspace_id"
+)
+
+var ErrWorkspaceMismatch = errors.New("workspace of path and header differ")
+
+type WorkspaceService interface {
+	GetWorkspaceByID(ctx context.Context, userID, id int) (*entity.Workspace, error)
+}
+
+// WorkspaceMiddleware selects active workspace of request by workspace_id url param or X-Workspace-ID header
+// and ensures authenticated user is its member, requests selecting no workspace are scoped to personal resources.
+// It must be mounted after JWTAuthMiddleware
+func WorkspaceMiddleware(workspaceService WorkspaceService, logger *logrus.Logger) Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+			workspaceID, ok, err := workspaceIDFromRequest(req)
+			if err != nil {
+				msg := fmt.Sprintf("invalid workspace id provided: %v", err)
+
+				handlerutils.WriteErrResponseAndLog(rw, logger, http.StatusBadRequest, msg, msg)
+				return
+			}
+
+			if !ok {
+				next.ServeHTTP(rw, req)
+				return
+			}
+
+			userID, err := identity.UserID(req.Context())
+			if err != nil {
+				msg := fmt.Sprintf("unauthenticated: %v", err)
+
+				handlerutils.WriteErrResponseAndLog(rw, logger, http.StatusUnauthorized, msg, msg)
+				return
+			}
+
+			ws, err := workspaceService.GetWorkspaceByID(req.Context(), userID, workspaceID)
+			if err != nil {
+				msg := fmt.Sprintf("error occurred selecting workspace: %v", err)
+
+				status := http.StatusInternalServerError
+				if errors.Is(err, workspacerepo.ErrWorkspaceNotFound) {
+					status = http.StatusNotFound
+				}
+
+				handlerutils.WriteErrResponseAndLog(rw, logger, status, msg, msg)
+				return
+			}
+
+			ctx := identity.WithWorkspace(req.Context(), identity.Workspace{ID: ws.ID, Role: ws.Role})
+
+			next.ServeHTTP(rw, req.WithContext(ctx))
+		})
+	}
+}
+
+func workspaceIDFromRequest(req *http.Request) (int, bool, error) {
+	param := chi.URLParam(req, WorkspaceURLParam)
+	header := req.Header.Get(WorkspaceHeader)
+
+	if param == "" && header == "" {
+		return 0, false, nil
+	}
+
+	if param != "" && header != "" && param != header {
+		return 0, false, ErrWorkspaceMismatch
+	}
+
+	raw := param
+	if raw == "" {
+		raw = header
+	}
+
+	id, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, false, err
+	}
+
+	return id, true, nil
+}
@@ -0,0 +1,41 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+type MeasureRequest struct {
+	Func   string `json:"func" validate:"required,oneof=count count_distinct sum avg min max"`
+	Column string `json:"column"`
+	Alias  string `json:"alias" validate:"max=64"`
+}
+
+type TimeGrainRequest struct {
+	Column string `json:"column" validate:"required"`
+	Grain  string `json:"grain" validate:"required,oneof=minute hour day week month year"`
+}
+
+type FilterRequest struct {
+	Column string   `json:"column" validate:"required"`
+	Op     string   `json:"op" validate:"required,oneof=eq ne lt gt like in is_null not_null"`
+	Values []string `json:"values"`
+}
+
+// OrderRequest column refers to dimension, time grain column or measure output name
+type OrderRequest struct {
+	Column string `json:"column" validate:"required"`
+	Desc   bool   `json:"desc"`
+}
+
+type AggregateRequest struct {
+	Schema     string            `json:"schema"`
+	Table      string            `json:"table" validate:"required"`
+	Dimensions []string          `json:"dimensions" validate:"dive,required"`
+	Measures   []MeasureRequest  `json:"measures" validate:"dive"`
+	TimeGrain  *TimeGrainRequest `json:"time_grain"`
+	Filters    []FilterRequest   `json:"filters" validate:"dive"`
+	Order      []OrderRequest    `json:"order" validate:"dive"`
+	Limit      int               `json:"limit" validate:"min=0"`
+}
+
+func (ar *AggregateRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(ar)
+}
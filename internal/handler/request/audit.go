@@ -0,0 +1,20 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+// GetAuditRequest holds audit log filters as passed in query string
+type GetAuditRequest struct {
+	UserID       string `validate:"omitempty,number"`
+	ConnectionID string `validate:"omitempty,number"`
+	Operation    string `validate:"omitempty,oneof=default_schema schemas tables columns rows stream count estimate query import import_dry_run"`
+	Status       string `validate:"omitempty,oneof=failed succeeded"`
+	From         string `validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	To           string `validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Search       string
+	Offset       int `validate:"min=0"`
+	Limit        int `validate:"min=1,max=1000"`
+}
+
+func (gr *GetAuditRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(gr)
+}
@@ -0,0 +1,22 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+type CreateConnectionRequest struct {
+	Name   string `json:"name" validate:"required,min=1,max=256"`
+	Engine string `json:"engine" validate:"required,oneof=postgres mysql sqlite"`
+	DSN    string `json:"dsn" validate:"required"`
+}
+
+func (cr *CreateConnectionRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(cr)
+}
+
+type UpdateConnectionRequest struct {
+	Name string `json:"name" validate:"omitempty,min=1,max=256"`
+	DSN  string `json:"dsn"`
+}
+
+func (ur *UpdateConnectionRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(ur)
+}
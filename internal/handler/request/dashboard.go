@@ -0,0 +1,61 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+type CreateDashboardRequest struct {
+	Title       string `json:"title" validate:"required,min=1,max=256"`
+	Description string `json:"description"`
+}
+
+func (cr *CreateDashboardRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(cr)
+}
+
+type UpdateDashboardRequest struct {
+	Title       string `json:"title" validate:"omitempty,min=1,max=256"`
+	Description string `json:"description"`
+}
+
+func (ur *UpdateDashboardRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(ur)
+}
+
+type WidgetLayoutRequest struct {
+	X      int `json:"x" validate:"min=0"`
+	Y      int `json:"y" validate:"min=0"`
+	Width  int `json:"width" validate:"min=1,max=24"`
+	Height int `json:"height" validate:"min=1,max=100"`
+}
+
+// WidgetOptionsRequest omitted columns are picked by column types of query result
+type WidgetOptionsRequest struct {
+	XColumn    string   `json:"x_column"`
+	YColumns   []string `json:"y_columns" validate:"omitempty,dive,min=1"`
+	GroupBy    string   `json:"group_by"`
+	TimeBucket string   `json:"time_bucket" validate:"omitempty,oneof=minute hour day week month year"`
+	Aggregate  string   `json:"aggregate" validate:"omitempty,oneof=sum avg min max count"`
+}
+
+// WidgetRequest binds widget either to saved_query_id or to connection_id with sql
+type WidgetRequest struct {
+	Title           string               `json:"title" validate:"required,min=1,max=256"`
+	Type            string               `json:"type" validate:"required,oneof=table line bar pie single_stat"`
+	SavedQueryID    *int                 `json:"saved_query_id" validate:"omitempty,min=1"`
+	ConnectionID    *int                 `json:"connection_id" validate:"omitempty,min=1"`
+	SQL             string               `json:"sql"`
+	Layout          WidgetLayoutRequest  `json:"layout"`
+	RefreshInterval int                  `json:"refresh_interval" validate:"min=0"`
+	Options         WidgetOptionsRequest `json:"options"`
+}
+
+func (wr *WidgetRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(wr)
+}
+
+type ReorderWidgetsRequest struct {
+	WidgetIDs []int `json:"widget_ids" validate:"required,dive,min=1"`
+}
+
+func (rr *ReorderWidgetsRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(rr)
+}
@@ -0,0 +1,12 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+type ExecuteQueryRequest struct {
+	SQL    string `json:"sql" validate:"required"`
+	Params []any  `json:"params"`
+}
+
+func (er *ExecuteQueryRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(er)
+}
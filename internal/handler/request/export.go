@@ -0,0 +1,14 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+// ExportRequest selects format rows are streamed in
+type ExportRequest struct {
+	Format    string `validate:"oneof=json ndjson csv xlsx parquet"`
+	Delimiter string `validate:"omitempty,len=1"` // csv only
+	Null      string // csv only
+}
+
+func (er *ExportRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(er)
+}
@@ -0,0 +1,18 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+type GetRowsRequest struct {
+	Schema  string
+	Table   string `validate:"required"`
+	Sort    string
+	Filters []string
+	Cursor  string
+	Count   string `validate:"oneof=none exact estimated"`
+	Offset  int    `validate:"min=0"`
+	Limit   int    `validate:"min=1,max=1000"`
+}
+
+func (gr *GetRowsRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(gr)
+}
@@ -0,0 +1,16 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+// GetHistoryRequest holds query history filters as passed in query string
+type GetHistoryRequest struct {
+	ConnectionID string `validate:"omitempty,number"`
+	Starred      string `validate:"omitempty,boolean"`
+	Search       string
+	Offset       int `validate:"min=0"`
+	Limit        int `validate:"min=1,max=1000"`
+}
+
+func (gr *GetHistoryRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(gr)
+}
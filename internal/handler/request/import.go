@@ -0,0 +1,18 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+// ImportRequest describes upload of table rows, file is sent as request body
+type ImportRequest struct {
+	Schema    string
+	Table     string   `validate:"required"`
+	Format    string   `validate:"oneof=csv ndjson"`
+	Delimiter string   `validate:"omitempty,len=1"` // csv only
+	Null      string   // csv only
+	Mapping   []string // file_column:table_column
+	DryRun    bool
+}
+
+func (ir *ImportRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(ir)
+}
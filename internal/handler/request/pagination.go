@@ -0,0 +1,12 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+type PaginationOptions struct {
+	Offset int `validate:"min=0"`
+	Limit  int `validate:"min=1,max=1000"`
+}
+
+func (po *PaginationOptions) Validate(valid *validator.Validate) error {
+	return valid.Struct(po)
+}
@@ -0,0 +1,29 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+type ConnectionPermissionRequest struct {
+	UserID int    `json:"user_id" validate:"required,min=1"`
+	Access string `json:"access" validate:"required,oneof=read_only query write"`
+}
+
+func (pr *ConnectionPermissionRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(pr)
+}
+
+type DashboardPermissionRequest struct {
+	UserID int    `json:"user_id" validate:"required,min=1"`
+	Access string `json:"access" validate:"required,oneof=view edit own"`
+}
+
+func (pr *DashboardPermissionRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(pr)
+}
+
+type SetRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=admin editor viewer"`
+}
+
+func (sr *SetRoleRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(sr)
+}
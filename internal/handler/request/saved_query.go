@@ -0,0 +1,44 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+type QueryParameterRequest struct {
+	Name    string `json:"name" validate:"required,max=64"`
+	Type    string `json:"type" validate:"required,oneof=string integer number boolean timestamp"`
+	Default any    `json:"default,omitempty"`
+}
+
+type CreateSavedQueryRequest struct {
+	ConnectionID int                     `json:"connection_id" validate:"required,min=1"`
+	Name         string                  `json:"name" validate:"required,min=1,max=256"`
+	Description  string                  `json:"description"`
+	SQL          string                  `json:"sql" validate:"required"`
+	Parameters   []QueryParameterRequest `json:"parameters" validate:"dive"`
+	Tags         []string                `json:"tags" validate:"dive,min=1,max=64"`
+}
+
+func (cr *CreateSavedQueryRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(cr)
+}
+
+// UpdateSavedQueryRequest omitted fields keep stored values
+type UpdateSavedQueryRequest struct {
+	ConnectionID int                     `json:"connection_id" validate:"omitempty,min=1"`
+	Name         string                  `json:"name" validate:"omitempty,min=1,max=256"`
+	Description  string                  `json:"description"`
+	SQL          string                  `json:"sql"`
+	Parameters   []QueryParameterRequest `json:"parameters" validate:"omitempty,dive"`
+	Tags         []string                `json:"tags" validate:"omitempty,dive,min=1,max=64"`
+}
+
+func (ur *UpdateSavedQueryRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(ur)
+}
+
+type ExecuteSavedQueryRequest struct {
+	Params map[string]any `json:"params"`
+}
+
+func (er *ExecuteSavedQueryRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(er)
+}
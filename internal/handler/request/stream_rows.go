@@ -0,0 +1,18 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+// StreamRowsRequest is GetRowsRequest served as export, paging is replaced by server stream limit
+type StreamRowsRequest struct {
+	ExportRequest
+
+	Schema  string
+	Table   string `validate:"required"`
+	Sort    string
+	Filters []string
+	Limit   int `validate:"min=0"` // 0 streams up to server limit
+}
+
+func (sr *StreamRowsRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(sr)
+}
@@ -0,0 +1,15 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+// UpdateUserRequest changes email and/or password, current password must always be confirmed
+type UpdateUserRequest struct {
+	CurrentPassword    string `json:"current_password" validate:"required"`
+	Email              string `json:"email" validate:"required_without=NewPassword,omitempty,email"`
+	NewPassword        string `json:"new_password" validate:"omitempty,min=8,max=128"`
+	ConfirmNewPassword string `json:"confirm_new_password" validate:"eqfield=NewPassword"`
+}
+
+func (ur *UpdateUserRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(ur)
+}
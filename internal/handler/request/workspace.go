@@ -0,0 +1,28 @@
+package request
+
+import "github.com/go-playground/validator/v10"
+
+type WorkspaceRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=256"`
+}
+
+func (wr *WorkspaceRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(wr)
+}
+
+type InviteMemberRequest struct {
+	Email string `json:"email" validate:"required,email"`
+	Role  string `json:"role" validate:"required,oneof=owner editor viewer"`
+}
+
+func (ir *InviteMemberRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(ir)
+}
+
+type SetMemberRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=owner editor viewer"`
+}
+
+func (sr *SetMemberRoleRequest) Validate(valid *validator.Validate) error {
+	return valid.Struct(sr)
+}
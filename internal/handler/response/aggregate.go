@@ -0,0 +1,7 @@
+package response
+
+type AggregateResponse struct {
+	SQL    string              `json:"sql"`
+	Params []any               `json:"params"`
+	Result QueryResultResponse `json:"result"`
+}
@@ -0,0 +1,19 @@
+package response
+
+import "time"
+
+type AuditEntryResponse struct {
+	ID           int       `json:"id"`
+	UserID       *int      `json:"user_id"`
+	UserEmail    string    `json:"user_email"`
+	ConnectionID int       `json:"connection_id"`
+	WorkspaceID  *int      `json:"workspace_id"`
+	Engine       string    `json:"engine"`
+	Operation    string    `json:"operation"`
+	Statement    string    `json:"statement"`
+	Params       []string  `json:"params"`
+	DurationMs   int64     `json:"duration_ms"`
+	RowsReturned int       `json:"rows_returned"`
+	Error        string    `json:"error,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
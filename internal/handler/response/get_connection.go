@@ -0,0 +1,14 @@
+package response
+
+import "time"
+
+type GetConnectionResponse struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Engine      string    `json:"engine"`
+	OwnerID     int       `json:"owner_id"`
+	WorkspaceID *int      `json:"workspace_id"`
+	Access      string    `json:"access"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
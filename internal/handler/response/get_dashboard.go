@@ -0,0 +1,20 @@
+package response
+
+import "time"
+
+type GetDashboardResponse struct {
+	ID          int       `json:"id"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	OwnerID     int       `json:"owner_id"`
+	WorkspaceID *int      `json:"workspace_id"`
+	Access      string    `json:"access"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type GetDashboardWithWidgetsResponse struct {
+	GetDashboardResponse
+
+	Widgets []GetWidgetResponse `json:"widgets"`
+}
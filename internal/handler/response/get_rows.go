@@ -0,0 +1,9 @@
+package response
+
+type GetRowsResponse struct {
+	Columns        []QueryColumnResponse `json:"columns"`
+	Rows           [][]any               `json:"rows"`
+	Total          *int64                `json:"total,omitempty"`
+	TotalEstimated bool                  `json:"total_estimated,omitempty"`
+	NextCursor     string                `json:"next_cursor,omitempty"`
+}
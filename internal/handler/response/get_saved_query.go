@@ -0,0 +1,23 @@
+package response
+
+import "time"
+
+type QueryParameterResponse struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Default any    `json:"default,omitempty"`
+}
+
+type GetSavedQueryResponse struct {
+	ID           int                      `json:"id"`
+	OwnerID      int                      `json:"owner_id"`
+	WorkspaceID  *int                     `json:"workspace_id"`
+	ConnectionID int                      `json:"connection_id"`
+	Name         string                   `json:"name"`
+	Description  string                   `json:"description"`
+	SQL          string                   `json:"sql"`
+	Parameters   []QueryParameterResponse `json:"parameters"`
+	Tags         []string                 `json:"tags"`
+	CreatedAt    time.Time                `json:"created_at"`
+	UpdatedAt    time.Time                `json:"updated_at"`
+}
@@ -7,6 +7,8 @@ import (
 type GetUserResponse struct {
 	ID        int       `json:"id"`
 	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	Disabled  bool      `json:"disabled"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -0,0 +1,34 @@
+package response
+
+import "time"
+
+type WidgetLayoutResponse struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+type WidgetOptionsResponse struct {
+	XColumn    string   `json:"x_column,omitempty"`
+	YColumns   []string `json:"y_columns,omitempty"`
+	GroupBy    string   `json:"group_by,omitempty"`
+	TimeBucket string   `json:"time_bucket,omitempty"`
+	Aggregate  string   `json:"aggregate,omitempty"`
+}
+
+type GetWidgetResponse struct {
+	ID              int                   `json:"id"`
+	DashboardID     int                   `json:"dashboard_id"`
+	Title           string                `json:"title"`
+	Type            string                `json:"type"`
+	SavedQueryID    *int                  `json:"saved_query_id"`
+	ConnectionID    *int                  `json:"connection_id"`
+	SQL             string                `json:"sql"`
+	Layout          WidgetLayoutResponse  `json:"layout"`
+	Position        int                   `json:"position"`
+	RefreshInterval int                   `json:"refresh_interval"`
+	Options         WidgetOptionsResponse `json:"options"`
+	CreatedAt       time.Time             `json:"created_at"`
+	UpdatedAt       time.Time             `json:"updated_at"`
+}
@@ -0,0 +1,17 @@
+package response
+
+import "time"
+
+type HistoryEntryResponse struct {
+	ID           int       `json:"id"`
+	WorkspaceID  *int      `json:"workspace_id"`
+	ConnectionID int       `json:"connection_id"`
+	SQL          string    `json:"sql"`
+	Params       []any     `json:"params"`
+	DurationMs   int64     `json:"duration_ms"`
+	RowCount     int       `json:"row_count"`
+	Status       string    `json:"status"`
+	Error        string    `json:"error,omitempty"`
+	Starred      bool      `json:"starred"`
+	CreatedAt    time.Time `json:"created_at"`
+}
@@ -0,0 +1,15 @@
+package response
+
+type ImportErrorResponse struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column"`
+	Message string `json:"message"`
+}
+
+type ImportResultResponse struct {
+	Rows        int                   `json:"rows"`
+	Inserted    int64                 `json:"inserted"`
+	InvalidRows int                   `json:"invalid_rows"`
+	Errors      []ImportErrorResponse `json:"errors"`
+	DryRun      bool                  `json:"dry_run"`
+}
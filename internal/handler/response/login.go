@@ -1,5 +1,11 @@
 package response
 
+import "time"
+
+// LoginResponse token is short-lived access token, refresh token is exchanged for new pair at /auth/refresh
 type LoginResponse struct {
-	Token string `json:"token"`
+	Token            string    `json:"token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshToken     string    `json:"refresh_token"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"`
 }
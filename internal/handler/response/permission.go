@@ -0,0 +1,19 @@
+package response
+
+import "time"
+
+type ConnectionPermissionResponse struct {
+	ConnectionID int       `json:"connection_id"`
+	UserID       int       `json:"user_id"`
+	Access       string    `json:"access"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type DashboardPermissionResponse struct {
+	DashboardID int       `json:"dashboard_id"`
+	UserID      int       `json:"user_id"`
+	Access      string    `json:"access"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,12 @@
+package response
+
+type QueryColumnResponse struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type QueryResultResponse struct {
+	Columns   []QueryColumnResponse `json:"columns"`
+	Rows      [][]any               `json:"rows"`
+	Truncated bool                  `json:"truncated"`
+}
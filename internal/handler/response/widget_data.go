@@ -0,0 +1,21 @@
+package response
+
+type ChartPointResponse struct {
+	X any      `json:"x"`
+	Y *float64 `json:"y"`
+}
+
+type ChartSeriesResponse struct {
+	Name   string               `json:"name"`
+	Points []ChartPointResponse `json:"points"`
+}
+
+// WidgetDataResponse table widgets get columns with rows, other widgets get series
+type WidgetDataResponse struct {
+	Type      string                `json:"type"`
+	Columns   []QueryColumnResponse `json:"columns"`
+	Rows      [][]any               `json:"rows,omitempty"`
+	XColumn   string                `json:"x_column,omitempty"`
+	Series    []ChartSeriesResponse `json:"series"`
+	Truncated bool                  `json:"truncated"`
+}
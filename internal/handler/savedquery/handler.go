@@ -0,0 +1,485 @@
+package savedquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/export"
+	"db-dashboards/internal/handler/mapper"
+	"db-dashboards/internal/handler/request"
+
+	connectionrepo "db-dashboards/internal/repository/connection"
+	enginerepo "db-dashboards/internal/repository/engine"
+	savedqueryrepo "db-dashboards/internal/repository/savedquery"
+	auditservice "db-dashboards/internal/service/audit"
+	connectionservice "db-dashboards/internal/service/connection"
+	historyservice "db-dashboards/internal/service/history"
+	savedqueryservice "db-dashboards/internal/service/savedquery"
+
+	handlerinternalutils "db-dashboards/internal/handler/utils"
+	handlerutils "db-dashboards/pkg/utils/handler"
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+type Service interface {
+	GetAllSavedQueries(ctx context.Context, userID int, tag string, offset, limit int) ([]*entity.SavedQuery, error)
+	GetSavedQueryByID(ctx context.Context, userID, id int) (*entity.SavedQuery, error)
+	CreateSavedQuery(ctx context.Context, query entity.SavedQuery) (*entity.SavedQuery, error)
+	UpdateSavedQuery(ctx context.Context, userID int, query entity.SavedQuery) (*entity.SavedQuery, error)
+	DeleteSavedQuery(ctx context.Context, userID, id int) (*entity.SavedQuery, error)
+	ExecuteSavedQuery(ctx context.Context, userID, id int, values map[string]any) (*entity.QueryResult, error)
+	StreamSavedQuery(ctx context.Context,
+		userID, id int,
+		values map[string]any,
+		w enginerepo.RowWriter,
+	) (*enginerepo.StreamResult, error)
+}
+
+type Middleware = func(http.Handler) http.Handler
+
+type Handler struct {
+	Service     Service
+	Middlewares []Middleware
+
+	logger    *logrus.Logger
+	validator *validator.Validate
+}
+
+func New(service Service,
+	logger *logrus.Logger,
+	validator *validator.Validate,
+	middlewares ...Middleware,
+) *Handler {
+	return &Handler{
+		Service:     service,
+		Middlewares: middlewares,
+		logger:      logger,
+		validator:   validator,
+	}
+}
+
+func (h *Handler) Routes() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(h.Middlewares...)
+
+		r.Get("/", h.GetAll)
+		r.Post("/", h.Create)
+		r.Get("/{id}", h.GetByID)
+		r.Put("/{id}", h.Update)
+		r.Delete("/{id}", h.Delete)
+		r.Post("/{id}/execute", h.Execute)
+	})
+
+	return router
+}
+
+// GetAll godoc
+//
+//	@Summary		Get all saved queries
+//	@Description	Get all saved queries of current user, optionally filtered by tag
+//	@Security		JWT
+//	@Tags			SavedQuery
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			tag		query		string	false	"tag"
+//	@Param			offset	query		int		false	"offset"
+//	@Param			limit	query		int		false	"limit"
+//	@Success		200		{object}	[]response.GetSavedQueryResponse
+//	@Failure		400		{string}	invalid	pagination	options
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/saved-queries [get]
+func (h *Handler) GetAll(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	paginationOpts := handlerinternalutils.GetPaginationOptsFromQuery(req, handlerutils.DefaultOffset, handlerutils.DefaultLimit)
+
+	if err = paginationOpts.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid pagination options provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	queries, err := h.Service.GetAllSavedQueries(req.Context(), userID, req.URL.Query().Get("tag"), paginationOpts.Offset, paginationOpts.Limit)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred fetching saved queries: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+		return
+	}
+
+	render.JSON(rw, req, sliceutils.Map(queries, mapper.MapSavedQueryToSavedQueryResponse))
+}
+
+// GetByID godoc
+//
+//	@Summary		Get saved query
+//	@Description	Get saved query by id
+//	@Security		JWT
+//	@Tags			SavedQuery
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			id	path		int	true	"saved query id"
+//	@Success		200	{object}	response.GetSavedQueryResponse
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		404	{string}	saved	query	not	found
+//	@Failure		500	{string}	internal	error
+//	@Router			/db-dashboards/api/v1/saved-queries/{id} [get]
+func (h *Handler) GetByID(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid saved query id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	query, err := h.Service.GetSavedQueryByID(req.Context(), userID, id)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred fetching saved query: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapSavedQueryToSavedQueryResponse(query))
+}
+
+// Create godoc
+//
+//	@Summary		Save new query
+//	@Description	Save new query bound to saved connection. Sql may reference declared parameters as :name
+//	@Security		JWT
+//	@Tags			SavedQuery
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		request.CreateSavedQueryRequest	true	"saved query info"
+//	@Success		201		{object}	response.GetSavedQueryResponse
+//	@Failure		400		{string}	invalid		saved	query	data	provided
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		403		{string}	insufficient	access
+//	@Failure		404		{string}	connection	not		found
+//	@Failure		409		{string}	saved		query	already	exists
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/saved-queries [post]
+func (h *Handler) Create(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	var createReq request.CreateSavedQueryRequest
+
+	if err = render.DecodeJSON(req.Body, &createReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to CreateSavedQueryRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid saved query data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err = createReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating CreateSavedQueryRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid saved query data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	query, err := h.Service.CreateSavedQuery(req.Context(), mapper.MapCreateSavedQueryRequestToSavedQueryEntity(&createReq, userID))
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred creating saved query: %v", err), err)
+		return
+	}
+
+	render.Status(req, http.StatusCreated)
+	render.JSON(rw, req, mapper.MapSavedQueryToSavedQueryResponse(query))
+}
+
+// Update godoc
+//
+//	@Summary		Update saved query
+//	@Description	Update saved query, omitted fields keep stored values
+//	@Security		JWT
+//	@Tags			SavedQuery
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int								true	"saved query id"
+//	@Param			input	body		request.UpdateSavedQueryRequest	true	"saved query info"
+//	@Success		200		{object}	response.GetSavedQueryResponse
+//	@Failure		400		{string}	invalid		saved	query	data	provided
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		403		{string}	insufficient	access
+//	@Failure		404		{string}	saved		query	not		found
+//	@Failure		409		{string}	saved		query	already	exists
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/saved-queries/{id} [put]
+func (h *Handler) Update(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid saved query id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	var updateReq request.UpdateSavedQueryRequest
+
+	if err = render.DecodeJSON(req.Body, &updateReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to UpdateSavedQueryRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid saved query data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err = updateReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating UpdateSavedQueryRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid saved query data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	query, err := h.Service.UpdateSavedQuery(req.Context(), userID, mapper.MapUpdateSavedQueryRequestToSavedQueryEntity(&updateReq, id))
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred updating saved query: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapSavedQueryToSavedQueryResponse(query))
+}
+
+// Delete godoc
+//
+//	@Summary		Delete saved query
+//	@Description	Delete saved query by id
+//	@Security		JWT
+//	@Tags			SavedQuery
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Produce		json
+//	@Param			id	path		int	true	"saved query id"
+//	@Success		200	{object}	response.GetSavedQueryResponse
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	insufficient	access
+//	@Failure		404	{string}	saved	query	not	found
+//	@Failure		500	{string}	internal	error
+//	@Router			/db-dashboards/api/v1/saved-queries/{id} [delete]
+func (h *Handler) Delete(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid saved query id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	query, err := h.Service.DeleteSavedQuery(req.Context(), userID, id)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred deleting saved query: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapSavedQueryToSavedQueryResponse(query))
+}
+
+// Execute godoc
+//
+//	@Summary		Execute saved query
+//	@Description	Execute saved query against its connection in read only transaction.
+//	@Description	Params are bound by name, omitted params take declared defaults.
+//	@Description	When format param is provided or Accept header is one of export media types result is streamed
+//	@Description	as it is read up to server stream limit, row count and truncation are reported in X-Row-Count and X-Rows-Truncated trailers.
+//	@Security		JWT
+//	@Tags			SavedQuery
+//	@Param			X-Workspace-ID	header	int	false	"active workspace id, resources are personal if omitted"
+//	@Accept			json
+//	@Produce		json
+//	@Produce		application/x-ndjson
+//	@Produce		text/csv
+//	@Produce		application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+//	@Produce		application/vnd.apache.parquet
+//	@Param			id			path		int									true	"saved query id"
+//	@Param			input		body		request.ExecuteSavedQueryRequest	true	"param values"
+//	@Param			format		query		string								false	"stream result in export format"	Enums(json, ndjson, csv, xlsx, parquet)
+//	@Param			delimiter	query		string								false	"csv delimiter, comma by default"
+//	@Param			null		query		string								false	"csv rendering of NULL, empty by default"
+//	@Success		200		{object}	response.QueryResultResponse
+//	@Failure		400		{string}	invalid	params
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		403		{string}	insufficient	access
+//	@Failure		404		{string}	saved	query	not	found
+//	@Failure		502		{string}	cannot	connect	to	database
+//	@Failure		504		{string}	query	timed	out
+//	@Router			/db-dashboards/api/v1/saved-queries/{id}/execute [post]
+func (h *Handler) Execute(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid saved query id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	var executeReq request.ExecuteSavedQueryRequest
+
+	// empty body executes query with defaults
+	if req.ContentLength != 0 {
+		if err = render.DecodeJSON(req.Body, &executeReq); err != nil {
+			logMsg := fmt.Sprintf("error occurred decoding request body to ExecuteSavedQueryRequest struct: %v", err)
+			respMsg := fmt.Sprintf("invalid params provided: %v", err)
+
+			handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+			return
+		}
+	}
+
+	if err = executeReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating ExecuteSavedQueryRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid params provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if format, ok := handlerinternalutils.GetExportFormat(req); ok {
+		h.streamSavedQuery(rw, req, userID, id, executeReq.Params, format)
+		return
+	}
+
+	result, err := h.Service.ExecuteSavedQuery(req.Context(), userID, id, executeReq.Params)
+
+	switch {
+	case errors.Is(err, historyservice.ErrNotRecorded):
+		h.logger.WithError(err).Errorf("can't record query to history")
+
+	case err != nil:
+		h.writeExecuteErr(rw, fmt.Sprintf("cannot execute saved query: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapQueryResultToQueryResultResponse(result))
+}
+
+func (h *Handler) streamSavedQuery(rw http.ResponseWriter, req *http.Request, userID, id int, values map[string]any, format string) {
+	exportReq := handlerinternalutils.GetExportRequestFromQuery(req, format)
+
+	if err := exportReq.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid export options provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	exportFormat, opts := mapper.MapExportRequestToExportOptions(&exportReq)
+
+	err := export.Serve(rw, h.logger, exportFormat, opts, func(w enginerepo.RowWriter) (*enginerepo.StreamResult, error) {
+		result, err := h.Service.StreamSavedQuery(req.Context(), userID, id, values, w)
+		if errors.Is(err, historyservice.ErrNotRecorded) {
+			h.logger.WithError(err).Errorf("can't record query to history")
+			return result, nil
+		}
+
+		return result, err
+	})
+	if err != nil {
+		h.writeExecuteErr(rw, fmt.Sprintf("cannot execute saved query: %v", err), err)
+	}
+}
+
+func (h *Handler) writeServiceErr(rw http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, savedqueryrepo.ErrSavedQueryNotFound), errors.Is(err, connectionrepo.ErrConnectionNotFound):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusNotFound, msg, msg)
+
+	case errors.Is(err, savedqueryservice.ErrAccessDenied), errors.Is(err, connectionservice.ErrAccessDenied):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusForbidden, msg, msg)
+
+	case errors.Is(err, savedqueryrepo.ErrSavedQueryNameExists):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusConflict, msg, msg)
+
+	case errors.Is(err, savedqueryservice.ErrInvalidParamName),
+		errors.Is(err, savedqueryservice.ErrInvalidParamType),
+		errors.Is(err, savedqueryservice.ErrDuplicateParam),
+		errors.Is(err, savedqueryservice.ErrInvalidParamValue):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+
+	default:
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+	}
+}
+
+// writeExecuteErr treats errors returned by target database as client errors
+func (h *Handler) writeExecuteErr(rw http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, savedqueryrepo.ErrSavedQueryNotFound), errors.Is(err, connectionrepo.ErrConnectionNotFound):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusNotFound, msg, msg)
+
+	case errors.Is(err, connectionservice.ErrAccessDenied):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusForbidden, msg, msg)
+
+	case errors.Is(err, connectionservice.ErrCannotConnect):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadGateway, msg, msg)
+
+	case errors.Is(err, context.DeadlineExceeded):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusGatewayTimeout, msg, msg)
+
+	case errors.Is(err, auditservice.ErrAuditFailed):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+
+	default:
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+	}
+}
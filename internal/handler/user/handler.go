@@ -2,21 +2,34 @@ package user
 
 import (
 	"context"
-	"github.com/go-chi/chi/v5"
+	"errors"
+	"fmt"
 	"net/http"
 
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
 	"github.com/go-playground/validator/v10"
 	"github.com/sirupsen/logrus"
 
 	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/mapper"
+	"db-dashboards/internal/handler/request"
+
+	userrepo "db-dashboards/internal/repository/user"
+	userservice "db-dashboards/internal/service/user"
+
+	handlerinternalutils "db-dashboards/internal/handler/utils"
+	handlerutils "db-dashboards/pkg/utils/handler"
+	sliceutils "db-dashboards/pkg/utils/slice"
 )
 
 type Service interface {
-	RegisterUser(ctx context.Context, user entity.User) (*entity.User, error)
 	GetUserByID(ctx context.Context, id int) (*entity.User, error)
-	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
-	GetAllUsers(ctx context.Context, offset, limit int) ([]*entity.User, error)
+	GetAllUsers(ctx context.Context, search string, offset, limit int) ([]*entity.User, error)
+	UpdateUser(ctx context.Context, id int, currentPassword, email, newPassword string) (*entity.User, error)
 	DeleteUser(ctx context.Context, id int) (*entity.User, error)
+	DisableUser(ctx context.Context, id int) (*entity.User, error)
+	EnableUser(ctx context.Context, id int) (*entity.User, error)
 }
 
 type Middleware = func(http.Handler) http.Handler
@@ -47,24 +60,285 @@ func (h *Handler) Routes() *chi.Mux {
 
 	router.Group(func(r chi.Router) {
 		r.Use(h.Middlewares...)
-		// r.Get("/all", h.GetAll)
+
+		r.Get("/", h.GetAll)
+		r.Get("/me", h.GetMe)
+		r.Put("/me", h.UpdateMe)
+		r.Get("/{id}", h.GetByID)
+		r.Delete("/{id}", h.Delete)
+		r.Put("/{id}/disable", h.Disable)
+		r.Put("/{id}/enable", h.Enable)
 	})
 
 	return router
 }
 
-// todo:
-//func (h *Handler) GetAll(rw http.ResponseWriter, req *http.Request) {
-//	paginationOpts := handlerinternalutils.GetPaginationOptsFromQuery(req, handler.DefaultOffset, handler.DefaultLimit)
+// GetAll godoc
+//
+//	@Summary		Get all users
+//	@Description	Get page of users, search filters users by email substring
+//	@Security		JWT
+//	@Tags			User
+//	@Produce		json
+//	@Param			search	query		string	false	"email substring"
+//	@Param			offset	query		int		false	"offset"
+//	@Param			limit	query		int		false	"limit"
+//	@Success		200		{object}	[]response.GetUserResponse
+//	@Failure		400		{string}	invalid	pagination	options
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/users [get]
+func (h *Handler) GetAll(rw http.ResponseWriter, req *http.Request) {
+	paginationOpts := handlerinternalutils.GetPaginationOptsFromQuery(req, handlerutils.DefaultOffset, handlerutils.DefaultLimit)
+
+	if err := paginationOpts.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid pagination options provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	users, err := h.Service.GetAllUsers(req.Context(), req.URL.Query().Get("search"), paginationOpts.Offset, paginationOpts.Limit)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred fetching users: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+		return
+	}
+
+	render.JSON(rw, req, sliceutils.Map(users, mapper.MapUserToUserResponse))
+}
+
+// GetMe godoc
+//
+//	@Summary		Get current user
+//	@Description	Get user authenticated by access token
+//	@Security		JWT
+//	@Tags			User
+//	@Produce		json
+//	@Success		200	{object}	response.GetUserResponse
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		404	{string}	user		not	found
+//	@Failure		500	{string}	internal	error
+//	@Router			/db-dashboards/api/v1/users/me [get]
+func (h *Handler) GetMe(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	user, err := h.Service.GetUserByID(req.Context(), userID)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred fetching user: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapUserToUserResponse(user))
+}
+
+// UpdateMe godoc
+//
+//	@Summary		Update current user
+//	@Description	Change email and/or password of current user, current password must be confirmed.
+//	@Description	Email can not be changed to address with pending workspace invitations.
+//	@Description	Password change revokes every other session of user
+//	@Security		JWT
+//	@Tags			User
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		request.UpdateUserRequest	true	"user info"
+//	@Success		200		{object}	response.GetUserResponse
+//	@Failure		400		{string}	invalid	user	data	provided
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		403		{string}	current		password	is		wrong
+//	@Failure		409		{string}	user		with		this	email	already	exists
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/users/me [put]
+func (h *Handler) UpdateMe(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	var updateReq request.UpdateUserRequest
+
+	if err = render.DecodeJSON(req.Body, &updateReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to UpdateUserRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid user data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err = updateReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating UpdateUserRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid user data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	user, err := h.Service.UpdateUser(req.Context(), userID, updateReq.CurrentPassword, updateReq.Email, updateReq.NewPassword)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred updating user: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapUserToUserResponse(user))
+}
+
+// GetByID godoc
 //
-//	if err := paginationOpts.Validate(h.validator); err != nil {
-//		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, "", err.Error())
+//	@Summary		Get user
+//	@Description	Get user by id
+//	@Security		JWT
+//	@Tags			User
+//	@Produce		json
+//	@Param			id	path		int	true	"user id"
+//	@Success		200	{object}	response.GetUserResponse
+//	@Failure		400	{string}	invalid	user	id	provided
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		404	{string}	user		not	found
+//	@Failure		500	{string}	internal	error
+//	@Router			/db-dashboards/api/v1/users/{id} [get]
+func (h *Handler) GetByID(rw http.ResponseWriter, req *http.Request) {
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid user id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	user, err := h.Service.GetUserByID(req.Context(), id)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred fetching user: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapUserToUserResponse(user))
+}
+
+// Delete godoc
 //
-//		return
-//	}
+//	@Summary		Delete user
+//	@Description	Delete user with everything user owns, available to admins
+//	@Security		JWT
+//	@Tags			User
+//	@Produce		json
+//	@Param			id	path		int	true	"user id"
+//	@Success		200	{object}	response.GetUserResponse
+//	@Failure		400	{string}	invalid	user	id	provided
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	admin		rights	required
+//	@Failure		404	{string}	user		not		found
+//	@Failure		500	{string}	internal	error
+//	@Router			/db-dashboards/api/v1/users/{id} [delete]
+func (h *Handler) Delete(rw http.ResponseWriter, req *http.Request) {
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid user id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	user, err := h.Service.DeleteUser(req.Context(), id)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred deleting user: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapUserToUserResponse(user))
+}
+
+// Disable godoc
 //
-//	users := h.UserService.GetAllUsers(req.Context(), paginationOpts.Offset, paginationOpts.Limit)
+//	@Summary		Disable user
+//	@Description	Disable user account, user sessions are revoked and login is refused until user is enabled. Available to admins
+//	@Security		JWT
+//	@Tags			User
+//	@Produce		json
+//	@Param			id	path		int	true	"user id"
+//	@Success		200	{object}	response.GetUserResponse
+//	@Failure		400	{string}	invalid	user	id	provided
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	admin		rights	required
+//	@Failure		404	{string}	user		not		found
+//	@Failure		500	{string}	internal	error
+//	@Router			/db-dashboards/api/v1/users/{id}/disable [put]
+func (h *Handler) Disable(rw http.ResponseWriter, req *http.Request) {
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid user id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	user, err := h.Service.DisableUser(req.Context(), id)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred disabling user: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapUserToUserResponse(user))
+}
+
+// Enable godoc
 //
-//	render.JSON(rw, req, sliceutils.Map(users, mapper.MapUserToUserResponse))
-//	rw.WriteHeader(http.StatusOK)
-//}
+//	@Summary		Enable user
+//	@Description	Enable disabled user account, available to admins
+//	@Security		JWT
+//	@Tags			User
+//	@Produce		json
+//	@Param			id	path		int	true	"user id"
+//	@Success		200	{object}	response.GetUserResponse
+//	@Failure		400	{string}	invalid	user	id	provided
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		403	{string}	admin		rights	required
+//	@Failure		404	{string}	user		not		found
+//	@Failure		500	{string}	internal	error
+//	@Router			/db-dashboards/api/v1/users/{id}/enable [put]
+func (h *Handler) Enable(rw http.ResponseWriter, req *http.Request) {
+	id, err := handlerutils.GetIntURLParam(req, "id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid user id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	user, err := h.Service.EnableUser(req.Context(), id)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred enabling user: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapUserToUserResponse(user))
+}
+
+func (h *Handler) writeServiceErr(rw http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, userrepo.ErrUserNotFound):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusNotFound, msg, msg)
+
+	case errors.Is(err, userrepo.ErrEmailExists), errors.Is(err, userservice.ErrEmailInvited):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusConflict, msg, msg)
+
+	case errors.Is(err, userservice.ErrAdminRequired), errors.Is(err, userservice.ErrWrongPassword):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusForbidden, msg, msg)
+
+	case errors.Is(err, userservice.ErrManageSelf):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+
+	default:
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+	}
+}
@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"net/http"
+	"strconv"
+
+	"db-dashboards/internal/domain/identity"
+	"db-dashboards/internal/handler/export"
+	"db-dashboards/internal/handler/request"
+
+	handlerutils "db-dashboards/pkg/utils/handler"
+)
+
+// GetUserIDFromContext returns id of user authenticated by auth middleware
+func GetUserIDFromContext(req *http.Request) (int, error) {
+	return identity.UserID(req.Context())
+}
+
+func GetPaginationOptsFromQuery(req *http.Request, defaultOffset, defaultLimit int) request.PaginationOptions {
+	opts := request.PaginationOptions{
+		Offset: defaultOffset,
+		Limit:  defaultLimit,
+	}
+
+	if offset, err := handlerutils.GetIntParamFromQuery(req, "offset"); err == nil {
+		opts.Offset = offset
+	}
+
+	if limit, err := handlerutils.GetIntParamFromQuery(req, "limit"); err == nil {
+		opts.Limit = limit
+	}
+
+	return opts
+}
+
+func GetRowsRequestFromQuery(req *http.Request, tableName string) request.GetRowsRequest {
+	paginationOpts := GetPaginationOptsFromQuery(req, handlerutils.DefaultOffset, handlerutils.DefaultLimit)
+
+	query := req.URL.Query()
+
+	getReq := request.GetRowsRequest{
+		Schema:  query.Get("schema"),
+		Table:   tableName,
+		Sort:    query.Get("sort"),
+		Filters: query["filter"],
+		Cursor:  query.Get("cursor"),
+		Count:   query.Get("count"),
+		Offset:  paginationOpts.Offset,
+		Limit:   paginationOpts.Limit,
+	}
+
+	if getReq.Count == "" {
+		getReq.Count = "none"
+	}
+
+	return getReq
+}
+
+func GetAuditRequestFromQuery(req *http.Request) request.GetAuditRequest {
+	paginationOpts := GetPaginationOptsFromQuery(req, handlerutils.DefaultOffset, handlerutils.DefaultLimit)
+
+	query := req.URL.Query()
+
+	return request.GetAuditRequest{
+		UserID:       query.Get("user_id"),
+		ConnectionID: query.Get("connection_id"),
+		Operation:    query.Get("operation"),
+		Status:       query.Get("status"),
+		From:         query.Get("from"),
+		To:           query.Get("to"),
+		Search:       query.Get("search"),
+		Offset:       paginationOpts.Offset,
+		Limit:        paginationOpts.Limit,
+	}
+}
+
+func GetHistoryRequestFromQuery(req *http.Request) request.GetHistoryRequest {
+	paginationOpts := GetPaginationOptsFromQuery(req, handlerutils.DefaultOffset, handlerutils.DefaultLimit)
+
+	query := req.URL.Query()
+
+	return request.GetHistoryRequest{
+		ConnectionID: query.Get("connection_id"),
+		Starred:      query.Get("starred"),
+		Search:       query.Get("search"),
+		Offset:       paginationOpts.Offset,
+		Limit:        paginationOpts.Limit,
+	}
+}
+
+// GetExportFormat returns format requested by format query param or Accept header, ok is false if response
+// is not an export
+func GetExportFormat(req *http.Request) (format string, ok bool) {
+	if format = req.URL.Query().Get("format"); format != "" {
+		return format, true
+	}
+
+	if f, ok := export.FormatFromMediaType(req.Header.Get("Accept")); ok {
+		return string(f), true
+	}
+
+	return "", false
+}
+
+func GetExportRequestFromQuery(req *http.Request, format string) request.ExportRequest {
+	query := req.URL.Query()
+
+	return request.ExportRequest{
+		Format:    format,
+		Delimiter: query.Get("delimiter"),
+		Null:      query.Get("null"),
+	}
+}
+
+func GetStreamRowsRequestFromQuery(req *http.Request, tableName, format string) request.StreamRowsRequest {
+	query := req.URL.Query()
+
+	streamReq := request.StreamRowsRequest{
+		ExportRequest: GetExportRequestFromQuery(req, format),
+		Schema:        query.Get("schema"),
+		Table:         tableName,
+		Sort:          query.Get("sort"),
+		Filters:       query["filter"],
+	}
+
+	if limit, err := handlerutils.GetIntParamFromQuery(req, "limit"); err == nil {
+		streamReq.Limit = limit
+	}
+
+	return streamReq
+}
+
+// GetImportRequestFromQuery reads import options, format defaults to media type of uploaded file
+func GetImportRequestFromQuery(req *http.Request, tableName string) request.ImportRequest {
+	query := req.URL.Query()
+
+	importReq := request.ImportRequest{
+		Schema:    query.Get("schema"),
+		Table:     tableName,
+		Format:    query.Get("format"),
+		Delimiter: query.Get("delimiter"),
+		Null:      query.Get("null"),
+		Mapping:   query["mapping"],
+	}
+
+	if importReq.Format == "" {
+		if f, ok := export.FormatFromMediaType(req.Header.Get("Content-Type")); ok {
+			importReq.Format = string(f)
+		}
+	}
+
+	if dryRun, err := strconv.ParseBool(query.Get("dry_run")); err == nil {
+		importReq.DryRun = dryRun
+	}
+
+	return importReq
+}
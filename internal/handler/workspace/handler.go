@@ -0,0 +1,786 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/go-playground/validator/v10"
+	"github.com/sirupsen/logrus"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/handler/mapper"
+	"db-dashboards/internal/handler/request"
+
+	userrepo "db-dashboards/internal/repository/user"
+	workspacerepo "db-dashboards/internal/repository/workspace"
+	workspaceservice "db-dashboards/internal/service/workspace"
+
+	handlerinternalutils "db-dashboards/internal/handler/utils"
+	handlerutils "db-dashboards/pkg/utils/handler"
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+type Service interface {
+	GetUserWorkspaces(ctx context.Context, userID, offset, limit int) ([]*entity.Workspace, error)
+	GetWorkspaceByID(ctx context.Context, userID, id int) (*entity.Workspace, error)
+	CreateWorkspace(ctx context.Context, userID int, ws entity.Workspace) (*entity.Workspace, error)
+	UpdateWorkspace(ctx context.Context, userID int, ws entity.Workspace) (*entity.Workspace, error)
+	DeleteWorkspace(ctx context.Context, userID, id int) (*entity.Workspace, error)
+
+	GetMembers(ctx context.Context, userID, id int) ([]*entity.Membership, error)
+	SetMemberRole(ctx context.Context, userID, id, memberID int, role entity.WorkspaceRole) (*entity.Membership, error)
+	RemoveMember(ctx context.Context, userID, id, memberID int) (*entity.Membership, error)
+
+	GetInvitations(ctx context.Context, userID, id int) ([]*entity.Invitation, error)
+	InviteMember(ctx context.Context, userID int, inv entity.Invitation) (*entity.Invitation, error)
+	RevokeInvitation(ctx context.Context, userID, id, invitationID int) (*entity.Invitation, error)
+	GetUserInvitations(ctx context.Context, userID int) ([]*entity.Invitation, error)
+	AcceptInvitation(ctx context.Context, userID, invitationID int) (*entity.Membership, error)
+	DeclineInvitation(ctx context.Context, userID, invitationID int) (*entity.Invitation, error)
+}
+
+type Middleware = func(http.Handler) http.Handler
+
+type Handler struct {
+	Service     Service
+	Middlewares []Middleware
+
+	logger    *logrus.Logger
+	validator *validator.Validate
+}
+
+func New(service Service,
+	logger *logrus.Logger,
+	validator *validator.Validate,
+	middlewares ...Middleware,
+) *Handler {
+	return &Handler{
+		Service:     service,
+		Middlewares: middlewares,
+		logger:      logger,
+		validator:   validator,
+	}
+}
+
+// Routes use {workspace_id} url param, routers of workspace resources are expected to be mounted under /{workspace_id}
+func (h *Handler) Routes() *chi.Mux {
+	router := chi.NewRouter()
+
+	router.Group(func(r chi.Router) {
+		r.Use(h.Middlewares...)
+
+		r.Get("/", h.GetAll)
+		r.Post("/", h.Create)
+
+		r.Get("/invitations", h.GetUserInvitations)
+		r.Post("/invitations/{invitation_id}/accept", h.AcceptInvitation)
+		r.Post("/invitations/{invitation_id}/decline", h.DeclineInvitation)
+
+		r.Get("/{workspace_id}", h.GetByID)
+		r.Put("/{workspace_id}", h.Update)
+		r.Delete("/{workspace_id}", h.Delete)
+
+		r.Get("/{workspace_id}/members", h.GetMembers)
+		r.Put("/{workspace_id}/members/{user_id}", h.SetMemberRole)
+		r.Delete("/{workspace_id}/members/{user_id}", h.RemoveMember)
+
+		r.Get("/{workspace_id}/invitations", h.GetInvitations)
+		r.Post("/{workspace_id}/invitations", h.Invite)
+		r.Delete("/{workspace_id}/invitations/{invitation_id}", h.RevokeInvitation)
+	})
+
+	return router
+}
+
+// GetAll godoc
+//
+//	@Summary		Get workspaces
+//	@Description	Get page of workspaces user is member of with role of user.
+//	@Description	Resources of workspace are selected by X-Workspace-ID header or served under /workspaces/{workspace_id} path
+//	@Security		JWT
+//	@Tags			Workspace
+//	@Produce		json
+//	@Param			offset	query		int	false	"offset"
+//	@Param			limit	query		int	false	"limit"
+//	@Success		200		{object}	[]response.GetWorkspaceResponse
+//	@Failure		400		{string}	invalid	pagination	options
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/workspaces [get]
+func (h *Handler) GetAll(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	paginationOpts := handlerinternalutils.GetPaginationOptsFromQuery(req, handlerutils.DefaultOffset, handlerutils.DefaultLimit)
+
+	if err = paginationOpts.Validate(h.validator); err != nil {
+		msg := fmt.Sprintf("invalid pagination options provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	workspaces, err := h.Service.GetUserWorkspaces(req.Context(), userID, paginationOpts.Offset, paginationOpts.Limit)
+	if err != nil {
+		msg := fmt.Sprintf("error occurred fetching workspaces: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+		return
+	}
+
+	render.JSON(rw, req, sliceutils.Map(workspaces, mapper.MapWorkspaceToWorkspaceResponse))
+}
+
+// GetByID godoc
+//
+//	@Summary		Get workspace
+//	@Description	Get workspace user is member of
+//	@Security		JWT
+//	@Tags			Workspace
+//	@Produce		json
+//	@Param			workspace_id	path		int	true	"workspace id"
+//	@Success		200				{object}	response.GetWorkspaceResponse
+//	@Failure		400				{string}	invalid	workspace	id	provided
+//	@Failure		401				{string}	Unauthorized
+//	@Failure		404				{string}	workspace	not	found
+//	@Failure		500				{string}	internal	error
+//	@Router			/db-dashboards/api/v1/workspaces/{workspace_id} [get]
+func (h *Handler) GetByID(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "workspace_id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid workspace id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	ws, err := h.Service.GetWorkspaceByID(req.Context(), userID, id)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred fetching workspace: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapWorkspaceToWorkspaceResponse(ws))
+}
+
+// Create godoc
+//
+//	@Summary		Create workspace
+//	@Description	Create workspace, user becomes its owner
+//	@Security		JWT
+//	@Tags			Workspace
+//	@Accept			json
+//	@Produce		json
+//	@Param			input	body		request.WorkspaceRequest	true	"workspace info"
+//	@Success		201		{object}	response.GetWorkspaceResponse
+//	@Failure		400		{string}	invalid	workspace	data	provided
+//	@Failure		401		{string}	Unauthorized
+//	@Failure		500		{string}	internal	error
+//	@Router			/db-dashboards/api/v1/workspaces [post]
+func (h *Handler) Create(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	var createReq request.WorkspaceRequest
+
+	if err = render.DecodeJSON(req.Body, &createReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to WorkspaceRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid workspace data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err = createReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating WorkspaceRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid workspace data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	ws, err := h.Service.CreateWorkspace(req.Context(), userID, mapper.MapWorkspaceRequestToWorkspaceEntity(&createReq, 0))
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred creating workspace: %v", err), err)
+		return
+	}
+
+	render.Status(req, http.StatusCreated)
+	render.JSON(rw, req, mapper.MapWorkspaceToWorkspaceResponse(ws))
+}
+
+// Update godoc
+//
+//	@Summary		Rename workspace
+//	@Description	Rename workspace, available to workspace owners
+//	@Security		JWT
+//	@Tags			Workspace
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	path		int							true	"workspace id"
+//	@Param			input			body		request.WorkspaceRequest	true	"workspace info"
+//	@Success		200				{object}	response.GetWorkspaceResponse
+//	@Failure		400				{string}	invalid	workspace	data	provided
+//	@Failure		401				{string}	Unauthorized
+//	@Failure		403				{string}	insufficient	workspace	role
+//	@Failure		404				{string}	workspace		not			found
+//	@Failure		500				{string}	internal		error
+//	@Router			/db-dashboards/api/v1/workspaces/{workspace_id} [put]
+func (h *Handler) Update(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "workspace_id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid workspace id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	var updateReq request.WorkspaceRequest
+
+	if err = render.DecodeJSON(req.Body, &updateReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to WorkspaceRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid workspace data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err = updateReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating WorkspaceRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid workspace data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	ws, err := h.Service.UpdateWorkspace(req.Context(), userID, mapper.MapWorkspaceRequestToWorkspaceEntity(&updateReq, id))
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred updating workspace: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapWorkspaceToWorkspaceResponse(ws))
+}
+
+// Delete godoc
+//
+//	@Summary		Delete workspace
+//	@Description	Delete workspace with its connections, saved queries and dashboards, available to workspace owners
+//	@Security		JWT
+//	@Tags			Workspace
+//	@Produce		json
+//	@Param			workspace_id	path		int	true	"workspace id"
+//	@Success		200				{object}	response.GetWorkspaceResponse
+//	@Failure		400				{string}	invalid	workspace	id	provided
+//	@Failure		401				{string}	Unauthorized
+//	@Failure		403				{string}	insufficient	workspace	role
+//	@Failure		404				{string}	workspace		not			found
+//	@Failure		500				{string}	internal		error
+//	@Router			/db-dashboards/api/v1/workspaces/{workspace_id} [delete]
+func (h *Handler) Delete(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "workspace_id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid workspace id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	ws, err := h.Service.DeleteWorkspace(req.Context(), userID, id)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred deleting workspace: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapWorkspaceToWorkspaceResponse(ws))
+}
+
+// GetMembers godoc
+//
+//	@Summary		Get workspace members
+//	@Description	Get members of workspace with their roles
+//	@Security		JWT
+//	@Tags			Workspace
+//	@Produce		json
+//	@Param			workspace_id	path		int	true	"workspace id"
+//	@Success		200				{object}	[]response.MembershipResponse
+//	@Failure		400				{string}	invalid	workspace	id	provided
+//	@Failure		401				{string}	Unauthorized
+//	@Failure		404				{string}	workspace	not	found
+//	@Failure		500				{string}	internal	error
+//	@Router			/db-dashboards/api/v1/workspaces/{workspace_id}/members [get]
+func (h *Handler) GetMembers(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "workspace_id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid workspace id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	members, err := h.Service.GetMembers(req.Context(), userID, id)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred fetching workspace members: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, sliceutils.Map(members, mapper.MapMembershipToMembershipResponse))
+}
+
+// SetMemberRole godoc
+//
+//	@Summary		Change member role
+//	@Description	Change role of workspace member, available to workspace owners. Workspace keeps at least one owner
+//	@Security		JWT
+//	@Tags			Workspace
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	path		int								true	"workspace id"
+//	@Param			user_id			path		int								true	"member user id"
+//	@Param			input			body		request.SetMemberRoleRequest	true	"role"
+//	@Success		200				{object}	response.MembershipResponse
+//	@Failure		400				{string}	invalid	role	data	provided
+//	@Failure		401				{string}	Unauthorized
+//	@Failure		403				{string}	insufficient	workspace	role
+//	@Failure		404				{string}	workspace		or			member	not	found
+//	@Failure		409				{string}	workspace		must		keep	owner
+//	@Failure		500				{string}	internal		error
+//	@Router			/db-dashboards/api/v1/workspaces/{workspace_id}/members/{user_id} [put]
+func (h *Handler) SetMemberRole(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "workspace_id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid workspace id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	memberID, err := handlerutils.GetIntURLParam(req, "user_id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid user id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	var roleReq request.SetMemberRoleRequest
+
+	if err = render.DecodeJSON(req.Body, &roleReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to SetMemberRoleRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid role data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err = roleReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating SetMemberRoleRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid role data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	member, err := h.Service.SetMemberRole(req.Context(), userID, id, memberID, entity.WorkspaceRole(roleReq.Role))
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred changing member role: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapMembershipToMembershipResponse(member))
+}
+
+// RemoveMember godoc
+//
+//	@Summary		Remove member
+//	@Description	Remove member from workspace, owners remove any member and every member can leave workspace.
+//	@Description	Workspace keeps at least one owner
+//	@Security		JWT
+//	@Tags			Workspace
+//	@Produce		json
+//	@Param			workspace_id	path		int	true	"workspace id"
+//	@Param			user_id			path		int	true	"member user id"
+//	@Success		200				{object}	response.MembershipResponse
+//	@Failure		400				{string}	invalid	id	provided
+//	@Failure		401				{string}	Unauthorized
+//	@Failure		403				{string}	insufficient	workspace	role
+//	@Failure		404				{string}	workspace		or			member	not	found
+//	@Failure		409				{string}	workspace		must		keep	owner
+//	@Failure		500				{string}	internal		error
+//	@Router			/db-dashboards/api/v1/workspaces/{workspace_id}/members/{user_id} [delete]
+func (h *Handler) RemoveMember(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "workspace_id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid workspace id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	memberID, err := handlerutils.GetIntURLParam(req, "user_id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid user id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	member, err := h.Service.RemoveMember(req.Context(), userID, id, memberID)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred removing member: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapMembershipToMembershipResponse(member))
+}
+
+// GetInvitations godoc
+//
+//	@Summary		Get workspace invitations
+//	@Description	Get pending invitations to workspace, available to workspace owners
+//	@Security		JWT
+//	@Tags			Workspace
+//	@Produce		json
+//	@Param			workspace_id	path		int	true	"workspace id"
+//	@Success		200				{object}	[]response.InvitationResponse
+//	@Failure		400				{string}	invalid	workspace	id	provided
+//	@Failure		401				{string}	Unauthorized
+//	@Failure		403				{string}	insufficient	workspace	role
+//	@Failure		404				{string}	workspace		not			found
+//	@Failure		500				{string}	internal		error
+//	@Router			/db-dashboards/api/v1/workspaces/{workspace_id}/invitations [get]
+func (h *Handler) GetInvitations(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "workspace_id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid workspace id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	invitations, err := h.Service.GetInvitations(req.Context(), userID, id)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred fetching invitations: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, sliceutils.Map(invitations, mapper.MapInvitationToInvitationResponse))
+}
+
+// Invite godoc
+//
+//	@Summary		Invite member
+//	@Description	Invite user with email to workspace with role, available to workspace owners.
+//	@Description	Invited user does not have to be registered, invitation is accepted by user with the email
+//	@Security		JWT
+//	@Tags			Workspace
+//	@Accept			json
+//	@Produce		json
+//	@Param			workspace_id	path		int							true	"workspace id"
+//	@Param			input			body		request.InviteMemberRequest	true	"invitation"
+//	@Success		201				{object}	response.InvitationResponse
+//	@Failure		400				{string}	invalid	invitation	data	provided
+//	@Failure		401				{string}	Unauthorized
+//	@Failure		403				{string}	insufficient	workspace	role
+//	@Failure		404				{string}	workspace		not			found
+//	@Failure		409				{string}	user			already		invited	or	member
+//	@Failure		500				{string}	internal		error
+//	@Router			/db-dashboards/api/v1/workspaces/{workspace_id}/invitations [post]
+func (h *Handler) Invite(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "workspace_id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid workspace id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	var inviteReq request.InviteMemberRequest
+
+	if err = render.DecodeJSON(req.Body, &inviteReq); err != nil {
+		logMsg := fmt.Sprintf("error occurred decoding request body to InviteMemberRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid invitation data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	if err = inviteReq.Validate(h.validator); err != nil {
+		logMsg := fmt.Sprintf("error occurred validating InviteMemberRequest struct: %v", err)
+		respMsg := fmt.Sprintf("invalid invitation data provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, logMsg, respMsg)
+		return
+	}
+
+	inv, err := h.Service.InviteMember(req.Context(), userID, mapper.MapInviteMemberRequestToInvitationEntity(&inviteReq, id))
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred inviting member: %v", err), err)
+		return
+	}
+
+	render.Status(req, http.StatusCreated)
+	render.JSON(rw, req, mapper.MapInvitationToInvitationResponse(inv))
+}
+
+// RevokeInvitation godoc
+//
+//	@Summary		Revoke invitation
+//	@Description	Revoke pending invitation to workspace, available to workspace owners
+//	@Security		JWT
+//	@Tags			Workspace
+//	@Produce		json
+//	@Param			workspace_id	path		int	true	"workspace id"
+//	@Param			invitation_id	path		int	true	"invitation id"
+//	@Success		200				{object}	response.InvitationResponse
+//	@Failure		400				{string}	invalid	id	provided
+//	@Failure		401				{string}	Unauthorized
+//	@Failure		403				{string}	insufficient	workspace	role
+//	@Failure		404				{string}	workspace		or			invitation	not	found
+//	@Failure		500				{string}	internal		error
+//	@Router			/db-dashboards/api/v1/workspaces/{workspace_id}/invitations/{invitation_id} [delete]
+func (h *Handler) RevokeInvitation(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	id, err := handlerutils.GetIntURLParam(req, "workspace_id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid workspace id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	invitationID, err := handlerutils.GetIntURLParam(req, "invitation_id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid invitation id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	inv, err := h.Service.RevokeInvitation(req.Context(), userID, id, invitationID)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred revoking invitation: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapInvitationToInvitationResponse(inv))
+}
+
+// GetUserInvitations godoc
+//
+//	@Summary		Get my invitations
+//	@Description	Get pending invitations addressed to email of user
+//	@Security		JWT
+//	@Tags			Workspace
+//	@Produce		json
+//	@Success		200	{object}	[]response.InvitationResponse
+//	@Failure		401	{string}	Unauthorized
+//	@Failure		500	{string}	internal	error
+//	@Router			/db-dashboards/api/v1/workspaces/invitations [get]
+func (h *Handler) GetUserInvitations(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	invitations, err := h.Service.GetUserInvitations(req.Context(), userID)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred fetching invitations: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, sliceutils.Map(invitations, mapper.MapInvitationToInvitationResponse))
+}
+
+// AcceptInvitation godoc
+//
+//	@Summary		Accept invitation
+//	@Description	Accept invitation addressed to email of user and become workspace member
+//	@Security		JWT
+//	@Tags			Workspace
+//	@Produce		json
+//	@Param			invitation_id	path		int	true	"invitation id"
+//	@Success		200				{object}	response.MembershipResponse
+//	@Failure		400				{string}	invalid	invitation	id	provided
+//	@Failure		401				{string}	Unauthorized
+//	@Failure		404				{string}	invitation	not			found
+//	@Failure		409				{string}	user		already		member
+//	@Failure		500				{string}	internal	error
+//	@Router			/db-dashboards/api/v1/workspaces/invitations/{invitation_id}/accept [post]
+func (h *Handler) AcceptInvitation(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	invitationID, err := handlerutils.GetIntURLParam(req, "invitation_id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid invitation id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	member, err := h.Service.AcceptInvitation(req.Context(), userID, invitationID)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred accepting invitation: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapMembershipToMembershipResponse(member))
+}
+
+// DeclineInvitation godoc
+//
+//	@Summary		Decline invitation
+//	@Description	Decline invitation addressed to email of user
+//	@Security		JWT
+//	@Tags			Workspace
+//	@Produce		json
+//	@Param			invitation_id	path		int	true	"invitation id"
+//	@Success		200				{object}	response.InvitationResponse
+//	@Failure		400				{string}	invalid	invitation	id	provided
+//	@Failure		401				{string}	Unauthorized
+//	@Failure		404				{string}	invitation	not	found
+//	@Failure		500				{string}	internal	error
+//	@Router			/db-dashboards/api/v1/workspaces/invitations/{invitation_id}/decline [post]
+func (h *Handler) DeclineInvitation(rw http.ResponseWriter, req *http.Request) {
+	userID, err := handlerinternalutils.GetUserIDFromContext(req)
+	if err != nil {
+		msg := fmt.Sprintf("unauthenticated: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusUnauthorized, msg, msg)
+		return
+	}
+
+	invitationID, err := handlerutils.GetIntURLParam(req, "invitation_id")
+	if err != nil {
+		msg := fmt.Sprintf("invalid invitation id provided: %v", err)
+
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+		return
+	}
+
+	inv, err := h.Service.DeclineInvitation(req.Context(), userID, invitationID)
+	if err != nil {
+		h.writeServiceErr(rw, fmt.Sprintf("error occurred declining invitation: %v", err), err)
+		return
+	}
+
+	render.JSON(rw, req, mapper.MapInvitationToInvitationResponse(inv))
+}
+
+func (h *Handler) writeServiceErr(rw http.ResponseWriter, msg string, err error) {
+	switch {
+	case errors.Is(err, workspacerepo.ErrWorkspaceNotFound),
+		errors.Is(err, workspacerepo.ErrMemberNotFound),
+		errors.Is(err, workspacerepo.ErrInvitationNotFound),
+		errors.Is(err, userrepo.ErrUserNotFound):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusNotFound, msg, msg)
+
+	case errors.Is(err, workspaceservice.ErrAccessDenied):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusForbidden, msg, msg)
+
+	case errors.Is(err, workspaceservice.ErrInvalidRole):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusBadRequest, msg, msg)
+
+	case errors.Is(err, workspaceservice.ErrLastOwner),
+		errors.Is(err, workspacerepo.ErrMemberExists),
+		errors.Is(err, workspacerepo.ErrInvitationExists):
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusConflict, msg, msg)
+
+	default:
+		handlerutils.WriteErrResponseAndLog(rw, h.logger, http.StatusInternalServerError, msg, msg)
+	}
+}
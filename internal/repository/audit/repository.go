@@ -0,0 +1,114 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+// likeEscaper makes search text match literally inside LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+type Repo struct {
+	DB *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Repo {
+	return &Repo{
+		DB: db,
+	}
+}
+
+func (r *Repo) CreateEntry(ctx context.Context, entry entity.AuditEntry) error {
+	_, err := r.DB.ExecContext(ctx,
+		`INSERT INTO audit_log (user_id, user_email, connection_id, workspace_id, engine, operation, statement, params, duration_ms, rows_returned, error, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		entry.UserID, entry.UserEmail, entry.ConnectionID, entry.WorkspaceID, entry.Engine, entry.Operation,
+		entry.Statement, entry.Params, entry.DurationMs, entry.RowsReturned, entry.Error, entry.CreatedAt)
+
+	return err
+}
+
+// GetEntries returns page of entries matching filter, newest first
+func (r *Repo) GetEntries(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error) {
+	var (
+		conds []string
+		args  []any
+	)
+
+	bind := func(cond string, val any) {
+		args = append(args, val)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.UserID != nil {
+		bind("user_id = $%d", *filter.UserID)
+	}
+
+	if filter.ConnectionID != nil {
+		bind("connection_id = $%d", *filter.ConnectionID)
+	}
+
+	if filter.Operation != "" {
+		bind("operation = $%d", filter.Operation)
+	}
+
+	if filter.Failed != nil {
+		bind("(error <> '') = $%d", *filter.Failed)
+	}
+
+	if filter.From != nil {
+		bind("created_at >= $%d", *filter.From)
+	}
+
+	if filter.To != nil {
+		bind("created_at < $%d", *filter.To)
+	}
+
+	if filter.Search != "" {
+		bind("statement ILIKE $%d", "%"+likeEscaper.Replace(filter.Search)+"%")
+	}
+
+	query := "SELECT * FROM audit_log"
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.DB.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*entity.AuditEntry
+
+	for rows.Next() {
+		var entry entity.AuditEntry
+
+		if err = rows.StructScan(&entry); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// DeleteEntriesBefore deletes entries created before t and returns their count
+func (r *Repo) DeleteEntriesBefore(ctx context.Context, t time.Time) (int64, error) {
+	res, err := r.DB.ExecContext(ctx, "DELETE FROM audit_log WHERE created_at < $1", t)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
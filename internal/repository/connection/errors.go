@@ -0,0 +1,10 @@
+package connection
+
+import "errors"
+
+var (
+	ErrConnectionNotFound   = errors.New("connection not found")
+	ErrConnectionNameExists = errors.New("connection with this name already exists")
+	ErrPermissionNotFound   = errors.New("connection permission not found")
+	ErrGranteeNotFound      = errors.New("user to grant access to not found")
+)
@@ -0,0 +1,207 @@
+package connection
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+const (
+	uniqueViolationCode     = "23505"
+	foreignKeyViolationCode = "23503"
+)
+
+type Repo struct {
+	DB *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Repo {
+	return &Repo{
+		DB: db,
+	}
+}
+
+// GetAllConnections returns connections of workspace or, if workspaceID is nil, personal connections owned by user
+// and shared with user. Access is set from permission of user
+func (r *Repo) GetAllConnections(ctx context.Context, userID int, workspaceID *int, offset, limit int) ([]*entity.Connection, error) {
+	rows, err := r.DB.QueryxContext(ctx,
+		`SELECT c.*, COALESCE(p.access, '') AS access 
+FROM connections c 
+LEFT JOIN connection_permissions p ON p.connection_id = c.id AND p.user_id = $1 
+WHERE c.workspace_id IS NOT DISTINCT FROM $2 AND ($2 IS NOT NULL OR c.user_id = $1 OR p.user_id IS NOT NULL) 
+ORDER BY c.created_at LIMIT $3 OFFSET $4`,
+		userID, workspaceID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var connections []*entity.Connection
+
+	for rows.Next() {
+		var conn entity.Connection
+
+		if err = rows.StructScan(&conn); err != nil {
+			return nil, err
+		}
+
+		connections = append(connections, &conn)
+	}
+
+	return connections, rows.Err()
+}
+
+func (r *Repo) GetConnectionByID(ctx context.Context, id int) (*entity.Connection, error) {
+	var conn entity.Connection
+
+	err := r.DB.QueryRowxContext(ctx, "SELECT * FROM connections WHERE id = $1", id).StructScan(&conn)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &conn, nil
+}
+
+func (r *Repo) CreateConnection(ctx context.Context, conn entity.Connection) (*entity.Connection, error) {
+	result, err := r.DB.NamedQueryContext(ctx,
+		`INSERT INTO connections (user_id, workspace_id, name, engine, encrypted_dsn, created_at, updated_at) 
+VALUES (:user_id, :workspace_id, :name, :engine, :encrypted_dsn, :created_at, :updated_at) 
+RETURNING *`,
+		&conn)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+	defer result.Close()
+
+	var created entity.Connection
+
+	if result.Next() {
+		if err = result.StructScan(&created); err != nil {
+			return nil, err
+		}
+	}
+
+	return &created, nil
+}
+
+func (r *Repo) UpdateConnection(ctx context.Context, conn entity.Connection) (*entity.Connection, error) {
+	var updated entity.Connection
+
+	err := r.DB.QueryRowxContext(ctx,
+		`UPDATE connections SET name = $1, encrypted_dsn = $2, updated_at = $3 WHERE id = $4 RETURNING *`,
+		conn.Name, conn.EncryptedDSN, conn.UpdatedAt, conn.ID).StructScan(&updated)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &updated, nil
+}
+
+func (r *Repo) DeleteConnection(ctx context.Context, id int) (*entity.Connection, error) {
+	var deleted entity.Connection
+
+	err := r.DB.QueryRowxContext(ctx, "DELETE FROM connections WHERE id = $1 RETURNING *", id).StructScan(&deleted)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &deleted, nil
+}
+
+func (r *Repo) GetConnectionPermissions(ctx context.Context, connectionID int) ([]*entity.ConnectionPermission, error) {
+	rows, err := r.DB.QueryxContext(ctx,
+		"SELECT * FROM connection_permissions WHERE connection_id = $1 ORDER BY created_at",
+		connectionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var perms []*entity.ConnectionPermission
+
+	for rows.Next() {
+		var perm entity.ConnectionPermission
+
+		if err = rows.StructScan(&perm); err != nil {
+			return nil, err
+		}
+
+		perms = append(perms, &perm)
+	}
+
+	return perms, rows.Err()
+}
+
+func (r *Repo) GetConnectionPermission(ctx context.Context, connectionID, userID int) (*entity.ConnectionPermission, error) {
+	var perm entity.ConnectionPermission
+
+	err := r.DB.QueryRowxContext(ctx,
+		"SELECT * FROM connection_permissions WHERE connection_id = $1 AND user_id = $2",
+		connectionID, userID).StructScan(&perm)
+	if err != nil {
+		return nil, mapPermissionErr(err)
+	}
+
+	return &perm, nil
+}
+
+// SetConnectionPermission grants access or changes access already granted
+func (r *Repo) SetConnectionPermission(ctx context.Context, perm entity.ConnectionPermission) (*entity.ConnectionPermission, error) {
+	var set entity.ConnectionPermission
+
+	err := r.DB.QueryRowxContext(ctx,
+		`INSERT INTO connection_permissions (connection_id, user_id, access, created_at, updated_at) 
+VALUES ($1, $2, $3, $4, $5) 
+ON CONFLICT (connection_id, user_id) DO UPDATE SET access = EXCLUDED.access, updated_at = EXCLUDED.updated_at 
+RETURNING *`,
+		perm.ConnectionID, perm.UserID, perm.Access, perm.CreatedAt, perm.UpdatedAt).StructScan(&set)
+	if err != nil {
+		return nil, mapPermissionErr(err)
+	}
+
+	return &set, nil
+}
+
+func (r *Repo) DeleteConnectionPermission(ctx context.Context, connectionID, userID int) (*entity.ConnectionPermission, error) {
+	var deleted entity.ConnectionPermission
+
+	err := r.DB.QueryRowxContext(ctx,
+		"DELETE FROM connection_permissions WHERE connection_id = $1 AND user_id = $2 RETURNING *",
+		connectionID, userID).StructScan(&deleted)
+	if err != nil {
+		return nil, mapPermissionErr(err)
+	}
+
+	return &deleted, nil
+}
+
+func mapPermissionErr(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrPermissionNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+		return ErrGranteeNotFound
+	}
+
+	return err
+}
+
+func mapErr(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrConnectionNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return ErrConnectionNameExists
+	}
+
+	return err
+}
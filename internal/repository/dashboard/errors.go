@@ -0,0 +1,11 @@
+package dashboard
+
+import "errors"
+
+var (
+	ErrDashboardNotFound = errors.New("dashboard not found")
+	ErrWidgetNotFound    = errors.New("widget not found")
+
+	ErrPermissionNotFound = errors.New("dashboard permission not found")
+	ErrGranteeNotFound    = errors.New("user to grant access to not found")
+)
@@ -0,0 +1,304 @@
+package dashboard
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+const foreignKeyViolationCode = "23503"
+
+type Repo struct {
+	DB *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Repo {
+	return &Repo{
+		DB: db,
+	}
+}
+
+// GetAllDashboards returns dashboards of workspace or, if workspaceID is nil, personal dashboards owned by user
+// and shared with user. Access is set from permission of user
+func (r *Repo) GetAllDashboards(ctx context.Context, userID int, workspaceID *int, offset, limit int) ([]*entity.Dashboard, error) {
+	rows, err := r.DB.QueryxContext(ctx,
+		`SELECT d.*, COALESCE(p.access, '') AS access 
+FROM dashboards d 
+LEFT JOIN dashboard_permissions p ON p.dashboard_id = d.id AND p.user_id = $1 
+WHERE d.workspace_id IS NOT DISTINCT FROM $2 AND ($2 IS NOT NULL OR d.user_id = $1 OR p.user_id IS NOT NULL) 
+ORDER BY d.created_at LIMIT $3 OFFSET $4`,
+		userID, workspaceID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dashboards []*entity.Dashboard
+
+	for rows.Next() {
+		var dashboard entity.Dashboard
+
+		if err = rows.StructScan(&dashboard); err != nil {
+			return nil, err
+		}
+
+		dashboards = append(dashboards, &dashboard)
+	}
+
+	return dashboards, rows.Err()
+}
+
+func (r *Repo) GetDashboardByID(ctx context.Context, id int) (*entity.Dashboard, error) {
+	var dashboard entity.Dashboard
+
+	err := r.DB.QueryRowxContext(ctx, "SELECT * FROM dashboards WHERE id = $1", id).StructScan(&dashboard)
+	if err != nil {
+		return nil, mapErr(err, ErrDashboardNotFound)
+	}
+
+	return &dashboard, nil
+}
+
+func (r *Repo) CreateDashboard(ctx context.Context, dashboard entity.Dashboard) (*entity.Dashboard, error) {
+	var created entity.Dashboard
+
+	err := r.DB.QueryRowxContext(ctx,
+		`INSERT INTO dashboards (user_id, workspace_id, title, description, created_at, updated_at) 
+VALUES ($1, $2, $3, $4, $5, $6) 
+RETURNING *`,
+		dashboard.UserID, dashboard.WorkspaceID, dashboard.Title, dashboard.Description, dashboard.CreatedAt, dashboard.UpdatedAt).StructScan(&created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (r *Repo) UpdateDashboard(ctx context.Context, dashboard entity.Dashboard) (*entity.Dashboard, error) {
+	var updated entity.Dashboard
+
+	err := r.DB.QueryRowxContext(ctx,
+		"UPDATE dashboards SET title = $1, description = $2, updated_at = $3 WHERE id = $4 RETURNING *",
+		dashboard.Title, dashboard.Description, dashboard.UpdatedAt, dashboard.ID).StructScan(&updated)
+	if err != nil {
+		return nil, mapErr(err, ErrDashboardNotFound)
+	}
+
+	return &updated, nil
+}
+
+func (r *Repo) DeleteDashboard(ctx context.Context, id int) (*entity.Dashboard, error) {
+	var deleted entity.Dashboard
+
+	err := r.DB.QueryRowxContext(ctx, "DELETE FROM dashboards WHERE id = $1 RETURNING *", id).StructScan(&deleted)
+	if err != nil {
+		return nil, mapErr(err, ErrDashboardNotFound)
+	}
+
+	return &deleted, nil
+}
+
+func (r *Repo) GetWidgets(ctx context.Context, dashboardID int) ([]*entity.Widget, error) {
+	rows, err := r.DB.QueryxContext(ctx,
+		"SELECT * FROM widgets WHERE dashboard_id = $1 ORDER BY position, id",
+		dashboardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var widgets []*entity.Widget
+
+	for rows.Next() {
+		var widget entity.Widget
+
+		if err = rows.StructScan(&widget); err != nil {
+			return nil, err
+		}
+
+		widgets = append(widgets, &widget)
+	}
+
+	return widgets, rows.Err()
+}
+
+func (r *Repo) GetWidgetByID(ctx context.Context, dashboardID, id int) (*entity.Widget, error) {
+	var widget entity.Widget
+
+	err := r.DB.QueryRowxContext(ctx,
+		"SELECT * FROM widgets WHERE id = $1 AND dashboard_id = $2",
+		id, dashboardID).StructScan(&widget)
+	if err != nil {
+		return nil, mapErr(err, ErrWidgetNotFound)
+	}
+
+	return &widget, nil
+}
+
+// CreateWidget appends widget to the end of dashboard
+func (r *Repo) CreateWidget(ctx context.Context, widget entity.Widget) (*entity.Widget, error) {
+	var created entity.Widget
+
+	err := r.DB.QueryRowxContext(ctx,
+		`INSERT INTO widgets (dashboard_id, title, type, saved_query_id, connection_id, sql, 
+                     grid_x, grid_y, width, height, position, refresh_interval, options, created_at, updated_at) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, 
+        (SELECT COALESCE(MAX(position) + 1, 0) FROM widgets WHERE dashboard_id = $1), 
+        $11, $12, $13, $14) 
+RETURNING *`,
+		widget.DashboardID, widget.Title, widget.Type, widget.SavedQueryID, widget.ConnectionID, widget.SQL,
+		widget.GridX, widget.GridY, widget.Width, widget.Height,
+		widget.RefreshInterval, widget.Options, widget.CreatedAt, widget.UpdatedAt).StructScan(&created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (r *Repo) UpdateWidget(ctx context.Context, widget entity.Widget) (*entity.Widget, error) {
+	var updated entity.Widget
+
+	err := r.DB.QueryRowxContext(ctx,
+		`UPDATE widgets 
+SET title = $1, type = $2, saved_query_id = $3, connection_id = $4, sql = $5, 
+    grid_x = $6, grid_y = $7, width = $8, height = $9, refresh_interval = $10, options = $11, updated_at = $12 
+WHERE id = $13 AND dashboard_id = $14 
+RETURNING *`,
+		widget.Title, widget.Type, widget.SavedQueryID, widget.ConnectionID, widget.SQL,
+		widget.GridX, widget.GridY, widget.Width, widget.Height, widget.RefreshInterval, widget.Options, widget.UpdatedAt,
+		widget.ID, widget.DashboardID).StructScan(&updated)
+	if err != nil {
+		return nil, mapErr(err, ErrWidgetNotFound)
+	}
+
+	return &updated, nil
+}
+
+func (r *Repo) DeleteWidget(ctx context.Context, dashboardID, id int) (*entity.Widget, error) {
+	var deleted entity.Widget
+
+	err := r.DB.QueryRowxContext(ctx,
+		"DELETE FROM widgets WHERE id = $1 AND dashboard_id = $2 RETURNING *",
+		id, dashboardID).StructScan(&deleted)
+	if err != nil {
+		return nil, mapErr(err, ErrWidgetNotFound)
+	}
+
+	return &deleted, nil
+}
+
+// ReorderWidgets sets widget positions to their indexes in ids within single transaction
+func (r *Repo) ReorderWidgets(ctx context.Context, dashboardID int, ids []int, updatedAt time.Time) error {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for position, id := range ids {
+		res, err := tx.ExecContext(ctx,
+			"UPDATE widgets SET position = $1, updated_at = $2 WHERE id = $3 AND dashboard_id = $4",
+			position, updatedAt, id, dashboardID)
+		if err != nil {
+			return err
+		}
+
+		affected, err := res.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		if affected == 0 {
+			return ErrWidgetNotFound
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (r *Repo) GetDashboardPermissions(ctx context.Context, dashboardID int) ([]*entity.DashboardPermission, error) {
+	rows, err := r.DB.QueryxContext(ctx,
+		"SELECT * FROM dashboard_permissions WHERE dashboard_id = $1 ORDER BY created_at",
+		dashboardID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var perms []*entity.DashboardPermission
+
+	for rows.Next() {
+		var perm entity.DashboardPermission
+
+		if err = rows.StructScan(&perm); err != nil {
+			return nil, err
+		}
+
+		perms = append(perms, &perm)
+	}
+
+	return perms, rows.Err()
+}
+
+func (r *Repo) GetDashboardPermission(ctx context.Context, dashboardID, userID int) (*entity.DashboardPermission, error) {
+	var perm entity.DashboardPermission
+
+	err := r.DB.QueryRowxContext(ctx,
+		"SELECT * FROM dashboard_permissions WHERE dashboard_id = $1 AND user_id = $2",
+		dashboardID, userID).StructScan(&perm)
+	if err != nil {
+		return nil, mapErr(err, ErrPermissionNotFound)
+	}
+
+	return &perm, nil
+}
+
+// SetDashboardPermission grants access or changes access already granted
+func (r *Repo) SetDashboardPermission(ctx context.Context, perm entity.DashboardPermission) (*entity.DashboardPermission, error) {
+	var set entity.DashboardPermission
+
+	err := r.DB.QueryRowxContext(ctx,
+		`INSERT INTO dashboard_permissions (dashboard_id, user_id, access, created_at, updated_at) 
+VALUES ($1, $2, $3, $4, $5) 
+ON CONFLICT (dashboard_id, user_id) DO UPDATE SET access = EXCLUDED.access, updated_at = EXCLUDED.updated_at 
+RETURNING *`,
+		perm.DashboardID, perm.UserID, perm.Access, perm.CreatedAt, perm.UpdatedAt).StructScan(&set)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == foreignKeyViolationCode {
+			return nil, ErrGranteeNotFound
+		}
+
+		return nil, err
+	}
+
+	return &set, nil
+}
+
+func (r *Repo) DeleteDashboardPermission(ctx context.Context, dashboardID, userID int) (*entity.DashboardPermission, error) {
+	var deleted entity.DashboardPermission
+
+	err := r.DB.QueryRowxContext(ctx,
+		"DELETE FROM dashboard_permissions WHERE dashboard_id = $1 AND user_id = $2 RETURNING *",
+		dashboardID, userID).StructScan(&deleted)
+	if err != nil {
+		return nil, mapErr(err, ErrPermissionNotFound)
+	}
+
+	return &deleted, nil
+}
+
+func mapErr(err error, notFound error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFound
+	}
+
+	return err
+}
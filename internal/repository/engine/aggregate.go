@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+// MeasureName returns output column name of measure, e.g. sum_amount or count
+func MeasureName(m entity.Measure) string {
+	if m.Alias != "" {
+		return m.Alias
+	}
+
+	if m.Column == "" {
+		return string(m.Func)
+	}
+
+	return fmt.Sprintf("%v_%v", m.Func, m.Column)
+}
+
+// BuildAggregate compiles validated aggregate query into dialect specific sql and its bind arguments
+func BuildAggregate(d Dialect, q entity.AggregateQuery) (string, []any) {
+	b := &builder{dialect: d}
+
+	var (
+		selects []string
+		groups  []string
+	)
+
+	if q.TimeGrain != nil {
+		expr := d.TruncateTime(d.QuoteIdentifier(q.TimeGrain.Column), q.TimeGrain.Grain)
+
+		selects = append(selects, expr+" AS "+d.QuoteIdentifier(q.TimeGrain.Column))
+		groups = append(groups, expr)
+	}
+
+	for _, dim := range q.Dimensions {
+		selects = append(selects, d.QuoteIdentifier(dim))
+		groups = append(groups, d.QuoteIdentifier(dim))
+	}
+
+	for _, m := range q.Measures {
+		selects = append(selects, measureExpr(d, m)+" AS "+d.QuoteIdentifier(MeasureName(m)))
+	}
+
+	query := "SELECT " + strings.Join(selects, ", ") + " FROM " + QualifiedName(d, q.Schema, q.Table)
+	query += b.where(SelectQuery{Filters: q.Filters})
+
+	if len(groups) > 0 {
+		query += " GROUP BY " + strings.Join(groups, ", ")
+	}
+
+	query += b.orderBy(q.Order)
+	query += " " + d.LimitOffset(b.bind(q.Limit), b.bind(0))
+
+	return query, b.args
+}
+
+func measureExpr(d Dialect, m entity.Measure) string {
+	if m.Column == "" {
+		return "count(*)"
+	}
+
+	column := d.QuoteIdentifier(m.Column)
+
+	if m.Func == entity.MeasureCountDistinct {
+		return "count(DISTINCT " + column + ")"
+	}
+
+	return fmt.Sprintf("%v(%v)", m.Func, column)
+}
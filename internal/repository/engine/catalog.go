@@ -0,0 +1,40 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+
+	"db-dashboards/internal/domain/entity"
+
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+// LookupTable ensures table requested by client exists in introspected catalog and returns its columns.
+// Identifiers must pass through it before being quoted into any query
+func LookupTable(ctx context.Context, repo Repo, schema, tableName string) ([]*entity.Column, error) {
+	tables, err := repo.GetAllTables(ctx, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	names := sliceutils.Map(tables, func(t *entity.Table) string { return t.Name })
+
+	if !sliceutils.Contains(names, tableName) {
+		return nil, fmt.Errorf("%w: %v", ErrTableNotFound, tableName)
+	}
+
+	return repo.GetColumnsFromTable(ctx, schema, tableName)
+}
+
+// ValidateColumns ensures every name is a column of table
+func ValidateColumns(columns []*entity.Column, names ...string) error {
+	known := sliceutils.Map(columns, func(c *entity.Column) string { return c.Name })
+
+	for _, name := range names {
+		if !sliceutils.Contains(known, name) {
+			return fmt.Errorf("%w: %v", ErrUnknownColumn, name)
+		}
+	}
+
+	return nil
+}
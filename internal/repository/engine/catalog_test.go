@@ -0,0 +1,99 @@
+package engine_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/repository/engine"
+	"db-dashboards/internal/repository/sqlite"
+)
+
+func newSqliteRepo(t *testing.T) engine.Repo {
+	t.Helper()
+
+	db, err := sqlx.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	// every pooled connection would get its own in-memory database
+	db.SetMaxOpenConns(1)
+
+	db.MustExec(`CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT)`)
+	db.MustExec(`INSERT INTO items (name) VALUES ('a'), ('b')`)
+
+	return sqlite.New(db)
+}
+
+func TestLookupTable(t *testing.T) {
+	repo := newSqliteRepo(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		schema  string
+		table   string
+		wantErr error
+	}{
+		{"existing", "main", "items", nil},
+		{"statement separator", "main", "items; DROP TABLE items", engine.ErrTableNotFound},
+		{"double quote", "main", `items"; DROP TABLE items; --`, engine.ErrTableNotFound},
+		{"single quote", "main", "items' OR '1'='1", engine.ErrTableNotFound},
+		{"comment", "main", "items --", engine.ErrTableNotFound},
+		{"case differs", "main", "ITEMS", engine.ErrTableNotFound},
+		{"empty", "main", "", engine.ErrTableNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := engine.LookupTable(ctx, repo, tt.schema, tt.table)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("LookupTable(%q) error = %v, want %v", tt.table, err, tt.wantErr)
+			}
+
+			if tt.wantErr == nil && len(columns) != 2 {
+				t.Errorf("LookupTable(%q) = %v columns, want 2", tt.table, len(columns))
+			}
+		})
+	}
+
+	for _, schema := range []string{`main"; DROP TABLE items; --`, "main; DROP TABLE items", "main' --"} {
+		if _, err := engine.LookupTable(ctx, repo, schema, "items"); err == nil {
+			t.Errorf("LookupTable in schema %q found table", schema)
+		}
+	}
+
+	if count, err := repo.CountRows(ctx, engine.SelectQuery{Schema: "main", Table: "items"}); err != nil || count != 2 {
+		t.Errorf("items table changed, count %v, error %v", count, err)
+	}
+}
+
+func TestValidateColumns(t *testing.T) {
+	columns := []*entity.Column{{Name: "id"}, {Name: "name"}}
+
+	tests := []struct {
+		name    string
+		names   []string
+		wantErr error
+	}{
+		{"known", []string{"id", "name"}, nil},
+		{"none", nil, nil},
+		{"quote", []string{`id"; DROP TABLE items; --`}, engine.ErrUnknownColumn},
+		{"backtick", []string{"id`"}, engine.ErrUnknownColumn},
+		{"expression", []string{"id = id OR 1"}, engine.ErrUnknownColumn},
+		{"unknown after known", []string{"id", "password"}, engine.ErrUnknownColumn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := engine.ValidateColumns(columns, tt.names...); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateColumns(%q) error = %v, want %v", tt.names, err, tt.wantErr)
+			}
+		})
+	}
+}
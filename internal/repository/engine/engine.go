@@ -0,0 +1,47 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+// Dialect describes SQL syntax differences between engines
+type Dialect interface {
+	QuoteIdentifier(name string) string
+	Placeholder(n int) string // n is 1-based position of bind argument
+	LimitOffset(limitPlaceholder, offsetPlaceholder string) string
+	LexOptions() LexOptions
+	TruncateTime(expr string, grain entity.TimeBucket) string
+	// GuardStatements returns session statements run on query console connection before and after the query,
+	// they enforce timeout and read only mode where transaction options are not enough
+	GuardStatements(timeout time.Duration, readOnly bool) (setup []string, reset []string)
+}
+
+// Repo is implemented by every engine repository to introspect and read target databases
+type Repo interface {
+	Dialect() Dialect
+	GetDefaultSchema(ctx context.Context) (string, error)
+	GetAllSchemas(ctx context.Context) ([]string, error)
+	GetAllTables(ctx context.Context, schema string) ([]*entity.Table, error)
+	GetColumnsFromTable(ctx context.Context, schema, tableName string) ([]*entity.Column, error)
+	GetRows(ctx context.Context, q SelectQuery) (*entity.QueryResult, error)
+	StreamRows(ctx context.Context, q SelectQuery, maxRows int, w RowWriter) (*StreamResult, error)
+	CountRows(ctx context.Context, q SelectQuery) (int64, error)
+	EstimateRowCount(ctx context.Context, schema, tableName string) (int64, error)
+	ExecuteQuery(ctx context.Context, q RawQuery) (*entity.QueryResult, error)
+	StreamQuery(ctx context.Context, q RawQuery, w RowWriter) (*StreamResult, error)
+	ImportRows(ctx context.Context, q ImportQuery) (int64, error)
+}
+
+// Driver binds engine name used in urls and saved connections to its sql driver, dialect and repository
+type Driver interface {
+	Name() string
+	DriverName() string
+	Dialect() Dialect
+	ResolveDSN(dsn string) (string, error) // validates saved dsn and returns the one passed to sql driver
+	NewRepo(db *sqlx.DB) Repo
+}
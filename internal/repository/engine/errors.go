@@ -0,0 +1,42 @@
+package engine
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"modernc.org/sqlite"
+)
+
+var (
+	ErrUnknownEngine = errors.New("unknown database engine")
+	ErrTableNotFound = errors.New("table not found")
+	ErrUnknownColumn = errors.New("unknown column")
+)
+
+// IsStatementError reports errors raised by target database server for executed statement,
+// e.g. syntax errors or constraint violations
+func IsStatementError(err error) bool {
+	var (
+		pgErr     *pgconn.PgError
+		mysqlErr  *mysql.MySQLError
+		sqliteErr *sqlite.Error
+	)
+
+	return errors.As(err, &pgErr) || errors.As(err, &mysqlErr) || errors.As(err, &sqliteErr)
+}
+
+// IsConnectionError reports errors of broken or lost connection to target database
+func IsConnectionError(err error) bool {
+	var netErr net.Error
+
+	return errors.As(err, &netErr) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+// RawQuery is client provided sql executed by query console
+type RawQuery struct {
+	Preceding []string // run in the same transaction before SQL, their results are discarded
+	SQL       string
+	Args      []any
+	ReadOnly  bool
+	Timeout   time.Duration
+	MaxRows   int
+}
+
+// ExecuteQuery runs q in its own transaction on a dedicated connection guarded by dialect session statements,
+// rows above q.MaxRows are discarded
+func ExecuteQuery(ctx context.Context, db *sqlx.DB, d Dialect, convert func(any) any, q RawQuery) (*entity.QueryResult, error) {
+	var result *entity.QueryResult
+
+	err := runGuarded(ctx, db, d, q, func(ctx context.Context, tx *sqlx.Tx) (err error) {
+		result, err = SelectRows(ctx, tx, convert, q.MaxRows, q.SQL, q.Args...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// StreamQuery runs q like ExecuteQuery but passes rows to w as they are scanned, rows above q.MaxRows are not passed.
+// Write transaction is committed only if every row was written
+func StreamQuery(ctx context.Context, db *sqlx.DB, d Dialect, convert func(any) any, q RawQuery, w RowWriter) (*StreamResult, error) {
+	var result *StreamResult
+
+	err := runGuarded(ctx, db, d, q, func(ctx context.Context, tx *sqlx.Tx) (err error) {
+		result, err = StreamRows(ctx, tx, convert, w, q.MaxRows, q.SQL, q.Args...)
+		return err
+	})
+
+	return result, err
+}
+
+// runGuarded runs preceding statements of q and then run in transaction of q
+func runGuarded(ctx context.Context, db *sqlx.DB, d Dialect, q RawQuery, run func(ctx context.Context, tx *sqlx.Tx) error) error {
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	setup, reset := d.GuardStatements(q.Timeout, q.ReadOnly)
+
+	// reset runs even if request is cancelled, connection is discarded when guards can not be lifted
+	defer func() {
+		for _, stmt := range reset {
+			if _, resetErr := conn.ExecContext(context.Background(), stmt); resetErr != nil {
+				_ = conn.Raw(func(any) error { return driver.ErrBadConn })
+				return
+			}
+		}
+	}()
+
+	for _, stmt := range setup {
+		if _, err = conn.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	if q.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, q.Timeout)
+		defer cancel()
+	}
+
+	tx, err := conn.BeginTxx(ctx, &sql.TxOptions{ReadOnly: q.ReadOnly})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, stmt := range q.Preceding {
+		if _, err = tx.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+
+	if err = run(ctx, tx); err != nil {
+		return err
+	}
+
+	if q.ReadOnly {
+		return tx.Rollback()
+	}
+
+	return tx.Commit()
+}
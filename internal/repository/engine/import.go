@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// bind arguments of single INSERT statement stay below limits of supported engines, e.g. 32766 of sqlite
+const (
+	insertBatchRows = 500
+	insertBatchArgs = 30000
+)
+
+// ImportQuery inserts rows into columns of table, values of row are ordered as columns.
+// Rows are inserted in one transaction which is rolled back on DryRun
+type ImportQuery struct {
+	Schema  string
+	Table   string
+	Columns []string
+	Rows    [][]any
+	DryRun  bool
+}
+
+// LoadFunc inserts rows of q on connection of open transaction, e.g. with bulk load protocol of engine
+type LoadFunc func(ctx context.Context, conn *sqlx.Conn, q ImportQuery) (int64, error)
+
+// ImportRows inserts rows of q with load or with batched INSERT statements if load is nil,
+// it returns number of inserted rows
+func ImportRows(ctx context.Context, db *sqlx.DB, d Dialect, q ImportQuery, load LoadFunc) (int64, error) {
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	tx, err := conn.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var inserted int64
+
+	if load != nil {
+		inserted, err = load(ctx, conn, q)
+	} else {
+		inserted, err = insertBatches(ctx, tx, d, q)
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	if q.DryRun {
+		return inserted, tx.Rollback()
+	}
+
+	return inserted, tx.Commit()
+}
+
+// BuildInsert returns INSERT statement head listing table and columns of q, e.g. INSERT INTO "t" ("a", "b")
+func BuildInsert(d Dialect, q ImportQuery) string {
+	columns := make([]string, len(q.Columns))
+
+	for i, column := range q.Columns {
+		columns[i] = d.QuoteIdentifier(column)
+	}
+
+	return fmt.Sprintf("INSERT INTO %v (%v)", QualifiedName(d, q.Schema, q.Table), strings.Join(columns, ", "))
+}
+
+func insertBatches(ctx context.Context, tx *sqlx.Tx, d Dialect, q ImportQuery) (int64, error) {
+	if len(q.Columns) == 0 {
+		return 0, nil
+	}
+
+	batchRows := min(insertBatchRows, max(insertBatchArgs/len(q.Columns), 1))
+	head := BuildInsert(d, q) + " VALUES "
+
+	var inserted int64
+
+	for start := 0; start < len(q.Rows); start += batchRows {
+		b := &builder{dialect: d}
+
+		batch := q.Rows[start:min(start+batchRows, len(q.Rows))]
+		tuples := make([]string, len(batch))
+
+		for i, row := range batch {
+			placeholders := make([]string, len(row))
+
+			for j, val := range row {
+				placeholders[j] = b.bind(val)
+			}
+
+			tuples[i] = "(" + strings.Join(placeholders, ", ") + ")"
+		}
+
+		res, err := tx.ExecContext(ctx, head+strings.Join(tuples, ", "), b.args...)
+		if err != nil {
+			return inserted, err
+		}
+
+		n, err := res.RowsAffected()
+		if err != nil {
+			return inserted, err
+		}
+
+		inserted += n
+	}
+
+	return inserted, nil
+}
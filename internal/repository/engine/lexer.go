@@ -0,0 +1,237 @@
+package engine
+
+import (
+	"regexp"
+	"strings"
+)
+
+type LexOptions struct {
+	DollarQuotes       bool // postgres $tag$ ... $tag$ strings
+	EscapeStrings      bool // postgres E'...' strings, backslash escapes quote in them
+	ExecutableComments bool // mysql /*! ... */ comments are executed and must not hide statement separators
+	HashComments       bool // mysql # comments until end of line
+	DashCommentSpace   bool // mysql -- starts comment only when followed by whitespace
+	BackslashEscapes   bool // mysql backslash escapes quote in '...' and "..." strings
+}
+
+type segmentKind int
+
+const (
+	segmentCode segmentKind = iota
+	segmentLiteral
+	segmentComment
+)
+
+type segment struct {
+	kind segmentKind
+	text string
+}
+
+var (
+	dollarTag  = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z_0-9]*)?\$`)
+	namedParam = regexp.MustCompile(`^:[A-Za-z_][A-Za-z_0-9]*`)
+)
+
+// lex cuts query into code, literal (strings, quoted identifiers) and comment segments.
+// Unterminated literals and comments run until the end of query
+func lex(query string, opts LexOptions) []segment {
+	var (
+		segments []segment
+		codeFrom int
+	)
+
+	emit := func(kind segmentKind, from, to int) {
+		if codeFrom < from {
+			segments = append(segments, segment{kind: segmentCode, text: query[codeFrom:from]})
+		}
+
+		segments = append(segments, segment{kind: kind, text: query[from:to]})
+		codeFrom = to
+	}
+
+	for i := 0; i < len(query); {
+		c := query[i]
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := skipQuoted(query, i, c, opts.BackslashEscapes && c != '`')
+			emit(segmentLiteral, i, end)
+			i = end
+
+		case (c == 'E' || c == 'e') && opts.EscapeStrings && strings.HasPrefix(query[i+1:], "'") &&
+			(i == 0 || !isIdentifierByte(query[i-1])):
+			end := skipQuoted(query, i+1, '\'', true)
+			emit(segmentLiteral, i, end)
+			i = end
+
+		case c == '-' && strings.HasPrefix(query[i:], "--") &&
+			(!opts.DashCommentSpace || i+2 == len(query) || isSpaceByte(query[i+2])),
+			c == '#' && opts.HashComments:
+			end := len(query)
+			if idx := strings.IndexByte(query[i:], '\n'); idx >= 0 {
+				end = i + idx + 1
+			}
+
+			emit(segmentComment, i, end)
+			i = end
+
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			if opts.ExecutableComments && strings.HasPrefix(query[i:], "/*!") {
+				i += 3
+				continue
+			}
+
+			end := len(query)
+			if idx := strings.Index(query[i+2:], "*/"); idx >= 0 {
+				end = i + 2 + idx + 2
+			}
+
+			emit(segmentComment, i, end)
+			i = end
+
+		case c == '$' && opts.DollarQuotes && dollarTag.MatchString(query[i:]):
+			tag := dollarTag.FindString(query[i:])
+
+			end := len(query)
+			if idx := strings.Index(query[i+len(tag):], tag); idx >= 0 {
+				end = i + len(tag) + idx + len(tag)
+			}
+
+			emit(segmentLiteral, i, end)
+			i = end
+
+		default:
+			i++
+		}
+	}
+
+	if codeFrom < len(query) {
+		segments = append(segments, segment{kind: segmentCode, text: query[codeFrom:]})
+	}
+
+	return segments
+}
+
+// skipQuoted returns position after closing quote, doubled quote is an escaped one,
+// with backslash any character following backslash is escaped
+func skipQuoted(query string, start int, quote byte, backslash bool) int {
+	for i := start + 1; i < len(query); i++ {
+		if backslash && query[i] == '\\' {
+			i++
+			continue
+		}
+
+		if query[i] != quote {
+			continue
+		}
+
+		if i+1 < len(query) && query[i+1] == quote {
+			i++
+			continue
+		}
+
+		return i + 1
+	}
+
+	return len(query)
+}
+
+func isIdentifierByte(c byte) bool {
+	return c == '_' || c == '$' || c >= 0x80 ||
+		'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// isSpaceByte reports control characters and space, mysql accepts any of them after -- of comment
+func isSpaceByte(c byte) bool {
+	return c <= ' '
+}
+
+// SplitStatements splits query on semicolons outside of literals and comments.
+// Statements consisting only of whitespace and comments are dropped. Lexing errs on the side of splitting,
+// so the result is only used to refuse multi-statement payloads
+func SplitStatements(query string, opts LexOptions) []string {
+	var (
+		statements []string
+		current    strings.Builder
+		hasContent bool
+	)
+
+	for _, seg := range lex(query, opts) {
+		if seg.kind != segmentCode {
+			current.WriteString(seg.text)
+			hasContent = hasContent || seg.kind == segmentLiteral
+
+			continue
+		}
+
+		parts := strings.Split(seg.text, ";")
+
+		for i, part := range parts {
+			if i > 0 {
+				if hasContent {
+					statements = append(statements, strings.TrimSpace(current.String()))
+				}
+
+				current.Reset()
+				hasContent = false
+			}
+
+			current.WriteString(part)
+			hasContent = hasContent || strings.TrimSpace(part) != ""
+		}
+	}
+
+	if hasContent {
+		statements = append(statements, strings.TrimSpace(current.String()))
+	}
+
+	return statements
+}
+
+// CompileNamedParams replaces :name params outside of literals and comments with dialect placeholders
+// and returns param names in order of placeholders. Postgres :: casts are left intact
+func CompileNamedParams(query string, d Dialect) (string, []string) {
+	var (
+		compiled strings.Builder
+		names    []string
+	)
+
+	for _, seg := range lex(query, d.LexOptions()) {
+		if seg.kind != segmentCode {
+			compiled.WriteString(seg.text)
+			continue
+		}
+
+		text := seg.text
+
+		for i := 0; i < len(text); {
+			if text[i] != ':' {
+				compiled.WriteByte(text[i])
+				i++
+
+				continue
+			}
+
+			if strings.HasPrefix(text[i:], "::") {
+				compiled.WriteString("::")
+				i += 2
+
+				continue
+			}
+
+			name := namedParam.FindString(text[i:])
+			if name == "" {
+				compiled.WriteByte(':')
+				i++
+
+				continue
+			}
+
+			names = append(names, name[1:])
+			compiled.WriteString(d.Placeholder(len(names)))
+			i += len(name)
+		}
+	}
+
+	return compiled.String(), names
+}
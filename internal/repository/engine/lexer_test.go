@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"testing"
+)
+
+var (
+	mysqlLex    = LexOptions{ExecutableComments: true, HashComments: true, DashCommentSpace: true, BackslashEscapes: true}
+	postgresLex = LexOptions{DollarQuotes: true, EscapeStrings: true}
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		opts  LexOptions
+		want  int
+	}{
+		{"single", "SELECT 1", LexOptions{}, 1},
+		{"trailing semicolon", "SELECT 1;", LexOptions{}, 1},
+		{"two statements", "SELECT 1; DELETE FROM t", LexOptions{}, 2},
+		{"semicolon in string", "SELECT ';'", LexOptions{}, 1},
+		{"doubled quote", "SELECT 'a'';'", LexOptions{}, 1},
+		{"semicolon in quoted identifier", `SELECT 1 AS "a;b"`, LexOptions{}, 1},
+		{"semicolon in line comment", "SELECT 1 -- ;\n", LexOptions{}, 1},
+		{"semicolon in block comment", "SELECT 1 /* ; */", LexOptions{}, 1},
+		{"comment only statement", "SELECT 1; -- done", LexOptions{}, 1},
+
+		{"mysql hash comment hides quote", "SELECT 1 # '\n; COMMIT; DELETE FROM t", mysqlLex, 3},
+		{"mysql hash comment", "SELECT 1 # ;\n", mysqlLex, 1},
+		{"mysql backslash escaped quote", `SELECT 'a\'; DELETE FROM t; SELECT '`, mysqlLex, 1},
+		{"mysql backslash before separator", `SELECT 'a\\'; DELETE FROM t`, mysqlLex, 2},
+		{"mysql backtick ignores backslash", "SELECT 1 AS `a\\`; DELETE FROM t", mysqlLex, 2},
+		{"mysql dash without space", "SELECT 1 --1; DELETE FROM t", mysqlLex, 2},
+		{"mysql executable comment", "SELECT 1 /*! ; DELETE FROM t */", mysqlLex, 2},
+		{"hash without mysql", "SELECT 1 # ; SELECT 2", LexOptions{}, 2},
+
+		{"postgres escape string", `SELECT E'a\'; DELETE FROM t; SELECT '`, postgresLex, 1},
+		{"postgres lowercase escape string", `SELECT e'\''; DELETE FROM t`, postgresLex, 2},
+		{"postgres identifier ending with e", `SELECT typE'a\'; DELETE FROM t`, postgresLex, 2},
+		{"postgres standard string", `SELECT 'a\'; DELETE FROM t`, postgresLex, 2},
+		{"postgres dollar quote", "SELECT $$;$$, $tag$ ; $tag$", postgresLex, 1},
+		{"postgres dollar quote hides escape", `SELECT $$E'$$; DELETE FROM t`, postgresLex, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitStatements(tt.query, tt.opts); len(got) != tt.want {
+				t.Errorf("SplitStatements(%q) = %q, want %v statements", tt.query, got, tt.want)
+			}
+		})
+	}
+}
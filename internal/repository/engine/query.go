@@ -0,0 +1,211 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+// SelectQuery describes page of table rows, After holds keyset cursor values of Sort columns
+type SelectQuery struct {
+	Schema  string
+	Table   string
+	Filters []entity.Filter
+	Sort    []entity.Sort
+	After   []any
+	Limit   int
+	Offset  int
+}
+
+type builder struct {
+	dialect Dialect
+	args    []any
+}
+
+func (b *builder) bind(val any) string {
+	b.args = append(b.args, val)
+	return b.dialect.Placeholder(len(b.args))
+}
+
+func (b *builder) where(q SelectQuery) string {
+	var conds []string
+
+	for _, filter := range q.Filters {
+		conds = append(conds, b.filter(filter))
+	}
+
+	if len(q.After) > 0 {
+		conds = append(conds, b.keyset(q.Sort, q.After))
+	}
+
+	if len(conds) == 0 {
+		return ""
+	}
+
+	return " WHERE " + strings.Join(conds, " AND ")
+}
+
+func (b *builder) filter(filter entity.Filter) string {
+	column := b.dialect.QuoteIdentifier(filter.Column)
+
+	switch filter.Op {
+	case entity.FilterOpIsNull:
+		return column + " IS NULL"
+
+	case entity.FilterOpNotNull:
+		return column + " IS NOT NULL"
+
+	case entity.FilterOpIn:
+		placeholders := make([]string, len(filter.Values))
+
+		for i, val := range filter.Values {
+			placeholders[i] = b.bind(val)
+		}
+
+		return fmt.Sprintf("%v IN (%v)", column, strings.Join(placeholders, ", "))
+	}
+
+	var op string
+
+	switch filter.Op {
+	case entity.FilterOpEq:
+		op = "="
+	case entity.FilterOpNe:
+		op = "<>"
+	case entity.FilterOpLt:
+		op = "<"
+	case entity.FilterOpGt:
+		op = ">"
+	case entity.FilterOpLike:
+		op = "LIKE"
+	}
+
+	var val any
+	if len(filter.Values) > 0 {
+		val = filter.Values[0]
+	}
+
+	return fmt.Sprintf("%v %v %v", column, op, b.bind(val))
+}
+
+// keyset expands (a, b) > (x, y) to a > x OR (a = x AND b > y) so that mixed sort directions are supported
+func (b *builder) keyset(sort []entity.Sort, after []any) string {
+	var alternatives []string
+
+	for i := range sort {
+		var parts []string
+
+		for j := 0; j < i; j++ {
+			parts = append(parts, fmt.Sprintf("%v = %v", b.dialect.QuoteIdentifier(sort[j].Column), b.bind(after[j])))
+		}
+
+		op := ">"
+		if sort[i].Desc {
+			op = "<"
+		}
+
+		parts = append(parts, fmt.Sprintf("%v %v %v", b.dialect.QuoteIdentifier(sort[i].Column), op, b.bind(after[i])))
+
+		alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")"
+}
+
+func (b *builder) orderBy(sort []entity.Sort) string {
+	if len(sort) == 0 {
+		return ""
+	}
+
+	columns := make([]string, len(sort))
+
+	for i, s := range sort {
+		dir := "ASC"
+		if s.Desc {
+			dir = "DESC"
+		}
+
+		columns[i] = b.dialect.QuoteIdentifier(s.Column) + " " + dir
+	}
+
+	return " ORDER BY " + strings.Join(columns, ", ")
+}
+
+func QualifiedName(d Dialect, schema, name string) string {
+	if schema == "" {
+		return d.QuoteIdentifier(name)
+	}
+
+	return fmt.Sprintf("%v.%v", d.QuoteIdentifier(schema), d.QuoteIdentifier(name))
+}
+
+// BuildSelect returns query selecting page of rows and its bind arguments
+func BuildSelect(d Dialect, q SelectQuery) (string, []any) {
+	b := &builder{dialect: d}
+
+	query := "SELECT * FROM " + QualifiedName(d, q.Schema, q.Table) + b.where(q) + b.orderBy(q.Sort)
+	query += " " + d.LimitOffset(b.bind(q.Limit), b.bind(q.Offset))
+
+	return query, b.args
+}
+
+// BuildCount returns query counting rows matching filters of q, paging and cursor are ignored
+func BuildCount(d Dialect, q SelectQuery) (string, []any) {
+	b := &builder{dialect: d}
+
+	q.After = nil
+
+	return "SELECT count(*) FROM " + QualifiedName(d, q.Schema, q.Table) + b.where(q), b.args
+}
+
+// SelectRows runs query and scans rows in column order, convert is applied to each scanned value if not nil.
+// Rows above maxRows are discarded and reported as truncated, maxRows is not applied if not positive
+func SelectRows(ctx context.Context,
+	db sqlx.QueryerContext,
+	convert func(any) any,
+	maxRows int,
+	query string,
+	args ...any,
+) (*entity.QueryResult, error) {
+	w := resultWriter{
+		result: entity.QueryResult{Rows: [][]any{}},
+	}
+
+	stream, err := StreamRows(ctx, db, convert, &w, maxRows, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	w.result.Truncated = stream.Truncated
+
+	return &w.result, nil
+}
+
+// resultWriter collects streamed rows, scanned rows are not reused so they are kept as is
+type resultWriter struct {
+	result entity.QueryResult
+}
+
+func (w *resultWriter) WriteColumns(columns []entity.QueryColumn) error {
+	w.result.Columns = columns
+	return nil
+}
+
+func (w *resultWriter) WriteRow(row []any) error {
+	w.result.Rows = append(w.result.Rows, row)
+	return nil
+}
+
+func CountRows(ctx context.Context, db *sqlx.DB, query string, args ...any) (int64, error) {
+	var count int64
+
+	if err := db.QueryRowxContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
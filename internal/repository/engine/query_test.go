@@ -0,0 +1,152 @@
+package engine_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/repository/engine"
+	"db-dashboards/internal/repository/mysql"
+	"db-dashboards/internal/repository/postgres"
+	"db-dashboards/internal/repository/sqlite"
+)
+
+var hostileValues = []string{
+	"' OR '1'='1",
+	"1; DROP TABLE users; --",
+	`"; DELETE FROM users; --`,
+	"`; DELETE FROM users; #",
+	`\'; DELETE FROM users; --`,
+	"$$; DELETE FROM users; $$",
+}
+
+func TestBuildSelect(t *testing.T) {
+	q := engine.SelectQuery{
+		Schema: `s"; DROP SCHEMA public; --`,
+		Table:  "t`; DROP TABLE users; --",
+		Filters: []entity.Filter{
+			{Column: `a"b`, Op: entity.FilterOpEq, Values: []string{"' OR '1'='1"}},
+			{Column: "c`d", Op: entity.FilterOpIn, Values: []string{"1; DROP TABLE users", "2"}},
+			{Column: "e;f", Op: entity.FilterOpIsNull},
+		},
+		Sort:   []entity.Sort{{Column: `g"; --`, Desc: true}},
+		Limit:  10,
+		Offset: 20,
+	}
+
+	tests := []struct {
+		name    string
+		dialect engine.Dialect
+		want    string
+	}{
+		{
+			name:    "postgres",
+			dialect: postgres.Dialect{},
+			want: `SELECT * FROM "s""; DROP SCHEMA public; --"."t` + "`" + `; DROP TABLE users; --"` +
+				` WHERE "a""b" = $1 AND "c` + "`" + `d" IN ($2, $3) AND "e;f" IS NULL` +
+				` ORDER BY "g""; --" DESC LIMIT $4 OFFSET $5`,
+		},
+		{
+			name:    "mysql",
+			dialect: mysql.Dialect{},
+			want: "SELECT * FROM `s\"; DROP SCHEMA public; --`.`t``; DROP TABLE users; --`" +
+				" WHERE `a\"b` = ? AND `c``d` IN (?, ?) AND `e;f` IS NULL" +
+				" ORDER BY `g\"; --` DESC LIMIT ? OFFSET ?",
+		},
+		{
+			name:    "sqlite",
+			dialect: sqlite.Dialect{},
+			want: `SELECT * FROM "s""; DROP SCHEMA public; --"."t` + "`" + `; DROP TABLE users; --"` +
+				` WHERE "a""b" = ? AND "c` + "`" + `d" IN (?, ?) AND "e;f" IS NULL` +
+				` ORDER BY "g""; --" DESC LIMIT ? OFFSET ?`,
+		},
+	}
+
+	wantArgs := []any{"' OR '1'='1", "1; DROP TABLE users", "2", 10, 20}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := engine.BuildSelect(tt.dialect, q)
+
+			if query != tt.want {
+				t.Errorf("query\n got: %v\nwant: %v", query, tt.want)
+			}
+
+			if !reflect.DeepEqual(args, wantArgs) {
+				t.Errorf("args = %#v, want %#v", args, wantArgs)
+			}
+		})
+	}
+}
+
+func TestBuildSelectBindsValues(t *testing.T) {
+	dialects := map[string]engine.Dialect{
+		"postgres": postgres.Dialect{},
+		"mysql":    mysql.Dialect{},
+		"sqlite":   sqlite.Dialect{},
+	}
+
+	ops := []entity.FilterOp{
+		entity.FilterOpEq,
+		entity.FilterOpNe,
+		entity.FilterOpLt,
+		entity.FilterOpGt,
+		entity.FilterOpLike,
+		entity.FilterOpIn,
+	}
+
+	for name, d := range dialects {
+		for _, op := range ops {
+			for _, val := range hostileValues {
+				q := engine.SelectQuery{
+					Table:   "t",
+					Filters: []entity.Filter{{Column: "c", Op: op, Values: []string{val}}},
+					Sort:    []entity.Sort{{Column: "c"}},
+					After:   []any{val},
+					Limit:   1,
+				}
+
+				query, args := engine.BuildSelect(d, q)
+				if strings.Contains(query, val) {
+					t.Errorf("%v %v: value %q is in query %v", name, op, val, query)
+				}
+
+				if len(args) != 4 || args[0] != val || args[1] != val {
+					t.Errorf("%v %v: value %q is not bound, args %#v", name, op, val, args)
+				}
+
+				count, countArgs := engine.BuildCount(d, q)
+				if strings.Contains(count, val) || len(countArgs) != 1 || countArgs[0] != val {
+					t.Errorf("%v %v: value %q is not bound in count %v, args %#v", name, op, val, count, countArgs)
+				}
+			}
+		}
+	}
+}
+
+func TestBuildSelectBindsLimitOffset(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect engine.Dialect
+		suffix  string
+	}{
+		{"postgres", postgres.Dialect{}, " LIMIT $1 OFFSET $2"},
+		{"mysql", mysql.Dialect{}, " LIMIT ? OFFSET ?"},
+		{"sqlite", sqlite.Dialect{}, " LIMIT ? OFFSET ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, args := engine.BuildSelect(tt.dialect, engine.SelectQuery{Table: "t", Limit: 50, Offset: 100})
+
+			if !strings.HasSuffix(query, tt.suffix) {
+				t.Errorf("query %v does not end with %v", query, tt.suffix)
+			}
+
+			if !reflect.DeepEqual(args, []any{50, 100}) {
+				t.Errorf("args = %#v, want limit and offset", args)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package engine
+
+import "sort"
+
+type Registry struct {
+	drivers map[string]Driver
+}
+
+func NewRegistry(drivers ...Driver) *Registry {
+	r := &Registry{
+		drivers: make(map[string]Driver, len(drivers)),
+	}
+
+	for _, driver := range drivers {
+		r.drivers[driver.Name()] = driver
+	}
+
+	return r
+}
+
+func (r *Registry) Get(name string) (Driver, error) {
+	driver, ok := r.drivers[name]
+	if !ok {
+		return nil, ErrUnknownEngine
+	}
+
+	return driver, nil
+}
+
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.drivers))
+
+	for name := range r.drivers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
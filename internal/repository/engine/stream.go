@@ -0,0 +1,115 @@
+package engine
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+// postgres numeric precision is at most 1000, mysql decimal precision at most 65
+const maxDecimalPrecision = 1000
+
+// RowWriter receives rows as they are scanned, WriteColumns is called once before the first row.
+// Values of row are ordered as columns
+type RowWriter interface {
+	WriteColumns(columns []entity.QueryColumn) error
+	WriteRow(row []any) error
+}
+
+// StreamResult describes rows passed to RowWriter
+type StreamResult struct {
+	Rows      int
+	Truncated bool // rows above max rows were not passed
+}
+
+// StreamSelect streams rows of q to w without buffering them. At most maxRows rows are passed,
+// maxRows must be positive and q.Limit above it is lowered to it
+func StreamSelect(ctx context.Context,
+	db *sqlx.DB,
+	d Dialect,
+	convert func(any) any,
+	q SelectQuery,
+	maxRows int,
+	w RowWriter,
+) (*StreamResult, error) {
+	// one extra row tells whether table has more rows than allowed
+	if q.Limit <= 0 || q.Limit > maxRows {
+		q.Limit = maxRows + 1
+	}
+
+	query, args := BuildSelect(d, q)
+
+	return StreamRows(ctx, db, convert, w, maxRows, query, args...)
+}
+
+// StreamRows runs query and passes every row to w, convert is applied to each scanned value if not nil.
+// Scanning stops as soon as ctx is cancelled or w fails, e.g. when client went away
+func StreamRows(ctx context.Context,
+	db sqlx.QueryerContext,
+	convert func(any) any,
+	w RowWriter,
+	maxRows int,
+	query string,
+	args ...any,
+) (*StreamResult, error) {
+	dbRows, err := db.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer dbRows.Close()
+
+	columnTypes, err := dbRows.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make([]entity.QueryColumn, len(columnTypes))
+
+	for i, ct := range columnTypes {
+		columns[i] = entity.QueryColumn{
+			Name: ct.Name(),
+			Type: strings.ToLower(ct.DatabaseTypeName()),
+		}
+
+		// unconstrained postgres numerics report out of range precision and mysql floats report max int
+		if precision, scale, ok := ct.DecimalSize(); ok && precision > 0 && precision <= maxDecimalPrecision && scale >= 0 && scale <= precision {
+			columns[i].Precision = int(precision)
+			columns[i].Scale = int(scale)
+		}
+	}
+
+	if err = w.WriteColumns(columns); err != nil {
+		return nil, err
+	}
+
+	var result StreamResult
+
+	for dbRows.Next() {
+		if maxRows > 0 && result.Rows == maxRows {
+			result.Truncated = true
+			break
+		}
+
+		row, err := dbRows.SliceScan()
+		if err != nil {
+			return &result, err
+		}
+
+		if convert != nil {
+			for i, val := range row {
+				row[i] = convert(val)
+			}
+		}
+
+		if err = w.WriteRow(row); err != nil {
+			return &result, err
+		}
+
+		result.Rows++
+	}
+
+	return &result, dbRows.Err()
+}
@@ -0,0 +1,65 @@
+package engine
+
+import "strings"
+
+type TypeKind int
+
+const (
+	KindOther TypeKind = iota
+	KindNumeric
+	KindTemporal
+	KindBoolean
+	KindText
+)
+
+// type names as reported by catalogs and ColumnTypes of supported engines, lowercased and without modifiers
+var typeKinds = map[string]TypeKind{
+	"smallint": KindNumeric, "int": KindNumeric, "integer": KindNumeric, "bigint": KindNumeric,
+	"tinyint": KindNumeric, "mediumint": KindNumeric, "int2": KindNumeric, "int4": KindNumeric, "int8": KindNumeric,
+	"serial": KindNumeric, "bigserial": KindNumeric, "smallserial": KindNumeric,
+	"real": KindNumeric, "float": KindNumeric, "float4": KindNumeric, "float8": KindNumeric, "double": KindNumeric,
+	"decimal": KindNumeric, "numeric": KindNumeric, "year": KindNumeric,
+
+	"date": KindTemporal, "datetime": KindTemporal, "timestamp": KindTemporal, "timestamptz": KindTemporal,
+
+	"bool": KindBoolean, "boolean": KindBoolean,
+
+	"text": KindText, "varchar": KindText, "char": KindText, "bpchar": KindText, "character": KindText,
+	"name": KindText, "citext": KindText, "tinytext": KindText, "mediumtext": KindText, "longtext": KindText,
+	"enum": KindText, "clob": KindText,
+}
+
+// integer subset of numeric type names
+var integerTypes = map[string]bool{
+	"smallint": true, "int": true, "integer": true, "bigint": true, "tinyint": true, "mediumint": true,
+	"int2": true, "int4": true, "int8": true, "serial": true, "bigserial": true, "smallserial": true, "year": true,
+}
+
+// KindOf classifies database type name, e.g. "int4", "UNSIGNED BIGINT", "varchar(255)" or "timestamp with time zone"
+func KindOf(typeName string) TypeKind {
+	return typeKinds[BaseType(typeName)]
+}
+
+// IsInteger reports whether type name is integer type of numeric kind
+func IsInteger(typeName string) bool {
+	return integerTypes[BaseType(typeName)]
+}
+
+// BaseType returns lowercased leading type name without modifiers and signedness, e.g. "bigint" for "UNSIGNED BIGINT"
+func BaseType(typeName string) string {
+	name := strings.ToLower(typeName)
+
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = name[:i]
+	}
+
+	for _, token := range strings.Fields(name) {
+		if token == "unsigned" || token == "signed" {
+			continue
+		}
+
+		return token
+	}
+
+	return ""
+}
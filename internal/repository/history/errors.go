@@ -0,0 +1,5 @@
+package history
+
+import "errors"
+
+var ErrHistoryEntryNotFound = errors.New("history entry not found")
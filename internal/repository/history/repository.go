@@ -0,0 +1,106 @@
+package history
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+// likeEscaper makes search text match literally inside LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+type Repo struct {
+	DB *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Repo {
+	return &Repo{
+		DB: db,
+	}
+}
+
+// GetEntries returns page of history entries of user matching filter, newest first
+func (r *Repo) GetEntries(ctx context.Context, filter entity.HistoryFilter) ([]*entity.HistoryEntry, error) {
+	var search string
+	if filter.Search != "" {
+		search = "%" + likeEscaper.Replace(filter.Search) + "%"
+	}
+
+	rows, err := r.DB.QueryxContext(ctx,
+		`SELECT * FROM query_history 
+WHERE user_id = $1 AND workspace_id IS NOT DISTINCT FROM $2 AND ($3::bigint IS NULL OR connection_id = $3) 
+AND (NOT $4 OR starred) AND ($5 = '' OR sql ILIKE $5) 
+ORDER BY created_at DESC, id DESC LIMIT $6 OFFSET $7`,
+		filter.UserID, filter.WorkspaceID, filter.ConnectionID, filter.Starred, search, filter.Limit, filter.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*entity.HistoryEntry
+
+	for rows.Next() {
+		var entry entity.HistoryEntry
+
+		if err = rows.StructScan(&entry); err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, &entry)
+	}
+
+	return entries, rows.Err()
+}
+
+func (r *Repo) GetEntryByID(ctx context.Context, id int) (*entity.HistoryEntry, error) {
+	var entry entity.HistoryEntry
+
+	err := r.DB.QueryRowxContext(ctx, "SELECT * FROM query_history WHERE id = $1", id).StructScan(&entry)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &entry, nil
+}
+
+func (r *Repo) CreateEntry(ctx context.Context, entry entity.HistoryEntry) (*entity.HistoryEntry, error) {
+	var created entity.HistoryEntry
+
+	err := r.DB.QueryRowxContext(ctx,
+		`INSERT INTO query_history (user_id, workspace_id, connection_id, sql, params, duration_ms, row_count, error, created_at) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) 
+RETURNING *`,
+		entry.UserID, entry.WorkspaceID, entry.ConnectionID, entry.SQL, entry.Params,
+		entry.DurationMs, entry.RowCount, entry.Error, entry.CreatedAt).StructScan(&created)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &created, nil
+}
+
+func (r *Repo) SetEntryStarred(ctx context.Context, id int, starred bool) (*entity.HistoryEntry, error) {
+	var updated entity.HistoryEntry
+
+	err := r.DB.QueryRowxContext(ctx,
+		"UPDATE query_history SET starred = $1 WHERE id = $2 RETURNING *",
+		starred, id).StructScan(&updated)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &updated, nil
+}
+
+func mapErr(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrHistoryEntryNotFound
+	}
+
+	return err
+}
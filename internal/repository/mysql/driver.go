@@ -0,0 +1,106 @@
+package mysql
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/repository/engine"
+)
+
+type Dialect struct{}
+
+func (Dialect) QuoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (Dialect) Placeholder(int) string {
+	return "?"
+}
+
+func (Dialect) LimitOffset(limitPlaceholder, offsetPlaceholder string) string {
+	return "LIMIT " + limitPlaceholder + " OFFSET " + offsetPlaceholder
+}
+
+func (Dialect) LexOptions() engine.LexOptions {
+	return engine.LexOptions{
+		ExecutableComments: true,
+		HashComments:       true,
+		DashCommentSpace:   true,
+		BackslashEscapes:   true,
+	}
+}
+
+// TruncateTime keeps DATETIME type of expression, weeks start on monday
+func (Dialect) TruncateTime(expr string, grain entity.TimeBucket) string {
+	switch grain {
+	case entity.TimeBucketMinute:
+		return fmt.Sprintf("CAST(DATE_FORMAT(%v, '%%Y-%%m-%%d %%H:%%i:00') AS DATETIME)", expr)
+
+	case entity.TimeBucketHour:
+		return fmt.Sprintf("CAST(DATE_FORMAT(%v, '%%Y-%%m-%%d %%H:00:00') AS DATETIME)", expr)
+
+	case entity.TimeBucketWeek:
+		return fmt.Sprintf("CAST(DATE_SUB(DATE(%v), INTERVAL WEEKDAY(%v) DAY) AS DATETIME)", expr, expr)
+
+	case entity.TimeBucketMonth:
+		return fmt.Sprintf("CAST(DATE_FORMAT(%v, '%%Y-%%m-01') AS DATETIME)", expr)
+
+	case entity.TimeBucketYear:
+		return fmt.Sprintf("CAST(DATE_FORMAT(%v, '%%Y-01-01') AS DATETIME)", expr)
+
+	default:
+		return fmt.Sprintf("CAST(DATE(%v) AS DATETIME)", expr)
+	}
+}
+
+// GuardStatements relies on READ ONLY transaction for read only mode, timeout applies to SELECT statements only
+func (Dialect) GuardStatements(timeout time.Duration, _ bool) ([]string, []string) {
+	if timeout <= 0 {
+		return nil, nil
+	}
+
+	return []string{fmt.Sprintf("SET SESSION MAX_EXECUTION_TIME = %d", timeout.Milliseconds())},
+		[]string{"SET SESSION MAX_EXECUTION_TIME = DEFAULT"}
+}
+
+type Driver struct{}
+
+func NewDriver() *Driver {
+	return &Driver{}
+}
+
+func (d *Driver) Name() string {
+	return "mysql"
+}
+
+func (d *Driver) DriverName() string {
+	return "mysql"
+}
+
+func (d *Driver) Dialect() engine.Dialect {
+	return Dialect{}
+}
+
+// ResolveDSN rejects dsn enabling multiple statements per query, the query console relies on server
+// refusing them in addition to splitting statements itself
+func (d *Driver) ResolveDSN(dsn string) (string, error) {
+	cfg, err := mysql.ParseDSN(dsn)
+	if err != nil {
+		return "", err
+	}
+
+	if cfg.MultiStatements {
+		return "", ErrMultiStatements
+	}
+
+	return dsn, nil
+}
+
+func (d *Driver) NewRepo(db *sqlx.DB) engine.Repo {
+	return New(db)
+}
@@ -0,0 +1,48 @@
+package mysql
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"users", "`users`"},
+		{"a`b", "`a``b`"},
+		{"`; DROP TABLE users; #", "```; DROP TABLE users; #`"},
+		{`a"b`, "`a\"b`"},
+		{`a\`, "`a\\`"},
+		{"", "``"},
+	}
+
+	for _, tt := range tests {
+		if got := (Dialect{}).QuoteIdentifier(tt.name); got != tt.want {
+			t.Errorf("QuoteIdentifier(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestResolveDSN(t *testing.T) {
+	tests := []struct {
+		name    string
+		dsn     string
+		wantErr error
+	}{
+		{"plain", "user:pass@tcp(localhost:3306)/db?parseTime=true", nil},
+		{"multi statements off", "user:pass@tcp(localhost:3306)/db?multiStatements=false", nil},
+		{"multi statements on", "user:pass@tcp(localhost:3306)/db?multiStatements=true", ErrMultiStatements},
+		{"multi statements among options", "user:pass@tcp(localhost:3306)/db?parseTime=true&multiStatements=1", ErrMultiStatements},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDriver().ResolveDSN(tt.dsn)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ResolveDSN(%q) error = %v, want %v", tt.dsn, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,5 @@
+package mysql
+
+import "errors"
+
+var ErrMultiStatements = errors.New("multiStatements option is not allowed, queries must be single statements")
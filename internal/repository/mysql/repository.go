@@ -3,106 +3,136 @@ package mysql
 import (
 	"context"
 	"database/sql"
-	"db-dashboards/internal/domain/entity/mysql"
-	"fmt"
 	"strings"
+
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/repository/engine"
 )
 
 type Repo struct {
-	DB *sql.DB
+	DB *sqlx.DB
 }
 
-func New(db *sql.DB) *Repo {
+func New(db *sqlx.DB) *Repo {
 	return &Repo{
 		DB: db,
 	}
 }
 
-func (r *Repo) GetAllTables(ctx context.Context, dbName string) ([]*mysql.Table, error) {
-	query := fmt.Sprintf("SELECT * FROM information_schema.tables WHERE table_schema = '%s'", dbName)
-	rows, err := r.DB.QueryContext(ctx, query)
-	if err != nil {
-		return nil, err
-	}
+func (r *Repo) Dialect() engine.Dialect {
+	return Dialect{}
+}
 
-	columnNames, _ := rows.Columns()
+// GetDefaultSchema returns database selected by dsn, mysql schemas are databases
+func (r *Repo) GetDefaultSchema(ctx context.Context) (string, error) {
+	var dbName sql.NullString
 
-	var tables []*mysql.Table
+	if err := r.DB.QueryRowxContext(ctx, "SELECT DATABASE()").Scan(&dbName); err != nil {
+		return "", err
+	}
 
-	for rows.Next() {
-		columns := make([]string, len(columnNames))
-		columnPointers := make([]any, len(columnNames))
+	return dbName.String, nil
+}
 
-		for i, _ := range columns {
-			columnPointers[i] = &columns[i]
-		}
+func (r *Repo) GetAllSchemas(ctx context.Context) ([]string, error) {
+	var schemas []string
 
-		rows.Scan(columnPointers...)
+	err := r.DB.SelectContext(ctx, &schemas,
+		`SELECT SCHEMA_NAME FROM information_schema.schemata 
+WHERE SCHEMA_NAME NOT IN ('information_schema', 'mysql', 'performance_schema', 'sys') 
+ORDER BY SCHEMA_NAME`)
+	if err != nil {
+		return nil, err
+	}
 
-		var table mysql.Table
+	return schemas, nil
+}
 
-		for i, columnName := range columnNames {
-			if columnName == "TABLE_NAME" {
-				table.Name = columns[i]
-				break
-			}
-		}
+func (r *Repo) GetAllTables(ctx context.Context, schema string) ([]*entity.Table, error) {
+	var tables []*entity.Table
 
-		tables = append(tables, &table)
+	err := r.DB.SelectContext(ctx, &tables,
+		"SELECT TABLE_NAME AS table_name FROM information_schema.tables WHERE table_schema = ? ORDER BY TABLE_NAME", schema)
+	if err != nil {
+		return nil, err
 	}
 
 	return tables, nil
 }
 
-func (r *Repo) GetColumnsFromTable(ctx context.Context, tableName string) ([]*mysql.Column, error) {
-
-	rows, err := r.DB.QueryContext(ctx,
-		fmt.Sprintf("SELECT * FROM information_schema.columns WHERE table_name   = '%v' order by ordinal_position", tableName))
+func (r *Repo) GetColumnsFromTable(ctx context.Context, schema, tableName string) ([]*entity.Column, error) {
+	var columns []*entity.Column
 
+	err := r.DB.SelectContext(ctx, &columns,
+		`SELECT COLUMN_NAME AS column_name, DATA_TYPE AS data_type, 
+       IS_NULLABLE = 'YES' AS is_nullable, COLUMN_KEY = 'PRI' AS is_primary_key 
+FROM information_schema.columns 
+WHERE table_schema = ? AND table_name = ? ORDER BY ordinal_position`, schema, tableName)
 	if err != nil {
 		return nil, err
 	}
 
-	columnNames, err := rows.Columns()
-	if err != nil {
-		return nil, err
+	for _, column := range columns {
+		column.Type = strings.ToLower(column.Type)
 	}
 
-	columnTypes, err := rows.ColumnTypes()
-	if err != nil {
-		return nil, err
-	}
+	return columns, nil
+}
 
-	var columns []*mysql.Column
+func (r *Repo) GetRows(ctx context.Context, q engine.SelectQuery) (*entity.QueryResult, error) {
+	query, args := engine.BuildSelect(Dialect{}, q)
 
-	for rows.Next() {
-		cols := make([]string, len(columnNames))
-		columnPointers := make([]any, len(columnNames))
+	return engine.SelectRows(ctx, r.DB, convertValue, 0, query, args...)
+}
 
-		for i, _ := range cols {
-			columnPointers[i] = &cols[i]
-		}
+func (r *Repo) StreamRows(ctx context.Context, q engine.SelectQuery, maxRows int, w engine.RowWriter) (*engine.StreamResult, error) {
+	return engine.StreamSelect(ctx, r.DB, Dialect{}, convertValue, q, maxRows, w)
+}
 
-		rows.Scan(columnPointers...)
+func (r *Repo) CountRows(ctx context.Context, q engine.SelectQuery) (int64, error) {
+	query, args := engine.BuildCount(Dialect{}, q)
 
-		var column mysql.Column
+	return engine.CountRows(ctx, r.DB, query, args...)
+}
+
+// EstimateRowCount uses table statistics which are approximate for InnoDB
+func (r *Repo) EstimateRowCount(ctx context.Context, schema, tableName string) (int64, error) {
+	var estimate sql.NullInt64
 
-		for i, columnName := range columnNames {
-			if columnName == "COLUMN_NAME" {
-				column.Name = cols[i]
-			}
+	err := r.DB.QueryRowxContext(ctx,
+		"SELECT TABLE_ROWS FROM information_schema.tables WHERE table_schema = ? AND table_name = ?",
+		schema, tableName).Scan(&estimate)
+	if err != nil {
+		return 0, err
+	}
 
-			if columnName == "DATA_TYPE" {
-				column.Type = strings.ToLower(columnTypes[i].DatabaseTypeName())
-			}
+	// views have no statistics
+	if !estimate.Valid {
+		return r.CountRows(ctx, engine.SelectQuery{Schema: schema, Table: tableName})
+	}
 
-			if column.Name != "" && column.Type != "" {
-				break
-			}
-		}
+	return estimate.Int64, nil
+}
 
-		columns = append(columns, &column)
+// convertValue turns bytes returned by text protocol into strings
+func convertValue(val any) any {
+	if b, ok := val.([]byte); ok {
+		return string(b)
 	}
 
-	return columns, nil
+	return val
+}
+
+func (r *Repo) ExecuteQuery(ctx context.Context, q engine.RawQuery) (*entity.QueryResult, error) {
+	return engine.ExecuteQuery(ctx, r.DB, Dialect{}, convertValue, q)
+}
+
+func (r *Repo) StreamQuery(ctx context.Context, q engine.RawQuery, w engine.RowWriter) (*engine.StreamResult, error) {
+	return engine.StreamQuery(ctx, r.DB, Dialect{}, convertValue, q, w)
+}
+
+func (r *Repo) ImportRows(ctx context.Context, q engine.ImportQuery) (int64, error) {
+	return engine.ImportRows(ctx, r.DB, Dialect{}, q, nil)
 }
@@ -0,0 +1,180 @@
+package mysql
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/repository/engine"
+)
+
+// newTestRepo returns repo over sqlite stand-in of mysql server, information_schema holds catalog of shop and
+// mysql databases and shop holds items table. Catalog queries and generated sql are portable enough to run on it
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+
+	db, err := sqlx.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	// attached databases belong to connection
+	db.SetMaxOpenConns(1)
+
+	db.MustExec(`ATTACH DATABASE ':memory:' AS information_schema`)
+	db.MustExec(`ATTACH DATABASE ':memory:' AS shop`)
+
+	db.MustExec(`CREATE TABLE information_schema.schemata (SCHEMA_NAME TEXT)`)
+	db.MustExec(`INSERT INTO information_schema.schemata VALUES ('sys'), ('shop'), ('mysql'), ('archive'), ('information_schema')`)
+
+	db.MustExec(`CREATE TABLE information_schema.tables (TABLE_SCHEMA TEXT, TABLE_NAME TEXT, TABLE_ROWS INTEGER)`)
+	db.MustExec(`INSERT INTO information_schema.tables VALUES 
+('shop', 'orders', 1200), ('shop', 'items', NULL), ('mysql', 'user', 3)`)
+
+	db.MustExec(`CREATE TABLE information_schema.columns 
+(TABLE_SCHEMA TEXT, TABLE_NAME TEXT, COLUMN_NAME TEXT, DATA_TYPE TEXT, IS_NULLABLE TEXT, COLUMN_KEY TEXT, ORDINAL_POSITION INTEGER)`)
+	db.MustExec(`INSERT INTO information_schema.columns VALUES 
+('shop', 'items', 'note', 'BLOB', 'YES', '', 3),
+('shop', 'items', 'id', 'INT', 'NO', 'PRI', 1),
+('shop', 'items', 'name', 'VARCHAR', 'NO', 'UNI', 2),
+('shop', 'orders', 'id', 'BIGINT', 'NO', 'PRI', 1)`)
+
+	db.MustExec("CREATE TABLE shop.`it``ems` (id INTEGER PRIMARY KEY, name TEXT NOT NULL, note BLOB)")
+	db.MustExec("INSERT INTO shop.`it``ems` VALUES (1, 'a', X'6869'), (2, 'b', NULL), (3, 'c', X'00ff')")
+
+	db.MustExec(`CREATE TABLE shop.items (id INTEGER PRIMARY KEY)`)
+	db.MustExec(`INSERT INTO shop.items VALUES (1), (2)`)
+
+	return New(db)
+}
+
+func TestConvertValue(t *testing.T) {
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		val  any
+		want any
+	}{
+		{"text protocol bytes", []byte("12.50"), "12.50"},
+		{"empty bytes", []byte{}, ""},
+		{"nil", nil, nil},
+		{"int64", int64(7), int64(7)},
+		{"float64", 1.5, 1.5},
+		{"time", now, now},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := convertValue(tt.val); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("convertValue(%#v) = %#v, want %#v", tt.val, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetAllSchemas(t *testing.T) {
+	schemas, err := newTestRepo(t).GetAllSchemas(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"archive", "shop"}; !reflect.DeepEqual(schemas, want) {
+		t.Errorf("schemas = %v, want %v", schemas, want)
+	}
+}
+
+func TestGetAllTables(t *testing.T) {
+	tables, err := newTestRepo(t).GetAllTables(context.Background(), "shop")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*entity.Table{{Name: "items"}, {Name: "orders"}}
+
+	if !reflect.DeepEqual(tables, want) {
+		t.Errorf("tables = %v, want %v", tables, want)
+	}
+}
+
+func TestGetColumnsFromTable(t *testing.T) {
+	repo := newTestRepo(t)
+
+	columns, err := repo.GetColumnsFromTable(context.Background(), "shop", "items")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*entity.Column{
+		{Name: "id", Type: "int", PrimaryKey: true},
+		{Name: "name", Type: "varchar"},
+		{Name: "note", Type: "blob", Nullable: true},
+	}
+
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("columns = %+v, want %+v", columns, want)
+	}
+
+	columns, err = repo.GetColumnsFromTable(context.Background(), "mysql", "items")
+	if err != nil || len(columns) != 0 {
+		t.Errorf("columns of table in other schema = %v, %v", columns, err)
+	}
+}
+
+func TestEstimateRowCount(t *testing.T) {
+	repo := newTestRepo(t)
+
+	tests := []struct {
+		name  string
+		table string
+		want  int64
+	}{
+		{"statistics", "orders", 1200},
+		{"no statistics counts rows", "items", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := repo.EstimateRowCount(context.Background(), "shop", tt.table)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got != tt.want {
+				t.Errorf("EstimateRowCount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRows(t *testing.T) {
+	repo := newTestRepo(t)
+
+	result, err := repo.GetRows(context.Background(), engine.SelectQuery{
+		Schema:  "shop",
+		Table:   "it`ems",
+		Filters: []entity.Filter{{Column: "id", Op: entity.FilterOpNe, Values: []string{"2"}}},
+		Sort:    []entity.Sort{{Column: "id", Desc: true}},
+		Limit:   10,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]any{{int64(3), "c", "\x00\xff"}, {int64(1), "a", "hi"}}
+
+	if !reflect.DeepEqual(result.Rows, want) {
+		t.Errorf("rows = %#v, want %#v", result.Rows, want)
+	}
+
+	count, err := repo.CountRows(context.Background(), engine.SelectQuery{Schema: "shop", Table: "it`ems"})
+	if err != nil || count != 3 {
+		t.Errorf("CountRows() = %v, %v, want 3", count, err)
+	}
+}
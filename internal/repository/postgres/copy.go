@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/repository/engine"
+)
+
+var errNotPgxConn = errors.New("connection is not pgx connection")
+
+// copyRows loads rows with COPY FROM STDIN in csv format, non NULL values are quoted
+// so that empty strings are told apart from NULL
+func copyRows(ctx context.Context, conn *sqlx.Conn, q engine.ImportQuery) (int64, error) {
+	var data bytes.Buffer
+
+	for _, row := range q.Rows {
+		for i, val := range row {
+			if i > 0 {
+				data.WriteByte(',')
+			}
+
+			if val == nil {
+				continue
+			}
+
+			data.WriteByte('"')
+			data.WriteString(strings.ReplaceAll(copyText(val), `"`, `""`))
+			data.WriteByte('"')
+		}
+
+		data.WriteByte('\n')
+	}
+
+	columns := make([]string, len(q.Columns))
+
+	for i, column := range q.Columns {
+		columns[i] = Dialect{}.QuoteIdentifier(column)
+	}
+
+	statement := fmt.Sprintf("COPY %v (%v) FROM STDIN WITH (FORMAT csv)",
+		engine.QualifiedName(Dialect{}, q.Schema, q.Table), strings.Join(columns, ", "))
+
+	var copied int64
+
+	err := conn.Raw(func(driverConn any) error {
+		pgxConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return errNotPgxConn
+		}
+
+		tag, err := pgxConn.Conn().PgConn().CopyFrom(ctx, &data, statement)
+		copied = tag.RowsAffected()
+
+		return err
+	})
+
+	return copied, err
+}
+
+// copyText renders value in text input format of postgres
+func copyText(val any) string {
+	switch v := val.(type) {
+	case string:
+		return v
+
+	case []byte:
+		return `\x` + hex.EncodeToString(v)
+
+	case int64:
+		return strconv.FormatInt(v, 10)
+
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+
+	case bool:
+		return strconv.FormatBool(v)
+
+	case time.Time:
+		return v.Format("2006-01-02 15:04:05.999999999Z07:00")
+
+	default:
+		return fmt.Sprint(v)
+	}
+}
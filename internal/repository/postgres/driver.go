@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/repository/engine"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+type Dialect struct{}
+
+func (Dialect) QuoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (Dialect) Placeholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+func (Dialect) LimitOffset(limitPlaceholder, offsetPlaceholder string) string {
+	return "LIMIT " + limitPlaceholder + " OFFSET " + offsetPlaceholder
+}
+
+func (Dialect) LexOptions() engine.LexOptions {
+	return engine.LexOptions{DollarQuotes: true, EscapeStrings: true}
+}
+
+func (Dialect) TruncateTime(expr string, grain entity.TimeBucket) string {
+	return fmt.Sprintf("date_trunc('%v', %v)", grain, expr)
+}
+
+// GuardStatements relies on READ ONLY transaction for read only mode
+func (Dialect) GuardStatements(timeout time.Duration, _ bool) ([]string, []string) {
+	if timeout <= 0 {
+		return nil, nil
+	}
+
+	return []string{fmt.Sprintf("SET statement_timeout = %d", timeout.Milliseconds())},
+		[]string{"RESET statement_timeout"}
+}
+
+type Driver struct{}
+
+func NewDriver() *Driver {
+	return &Driver{}
+}
+
+func (d *Driver) Name() string {
+	return "postgres"
+}
+
+func (d *Driver) DriverName() string {
+	return "pgx"
+}
+
+func (d *Driver) Dialect() engine.Dialect {
+	return Dialect{}
+}
+
+// ResolveDSN rejects dsn switching to simple protocol, with extended protocol server refuses multiple statements
+// per query which the query console relies on in addition to splitting statements itself
+func (d *Driver) ResolveDSN(dsn string) (string, error) {
+	cfg, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return "", err
+	}
+
+	if cfg.DefaultQueryExecMode == pgx.QueryExecModeSimpleProtocol {
+		return "", ErrSimpleProtocol
+	}
+
+	return dsn, nil
+}
+
+func (d *Driver) NewRepo(db *sqlx.DB) engine.Repo {
+	return New(db)
+}
@@ -0,0 +1,5 @@
+package postgres
+
+import "errors"
+
+var ErrSimpleProtocol = errors.New("simple_protocol query exec mode is not allowed, queries must be single statements")
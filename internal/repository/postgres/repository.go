@@ -2,12 +2,11 @@ package postgres
 
 import (
 	"context"
-	"fmt"
-	"strings"
 
 	"github.com/jmoiron/sqlx"
 
-	"db-dashboards/internal/domain/entity/postgres"
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/repository/engine"
 )
 
 type Repo struct {
@@ -20,117 +19,114 @@ func New(db *sqlx.DB) *Repo {
 	}
 }
 
-func (r *Repo) GetAllTables(ctx context.Context, schema string) ([]*postgres.Table, error) {
-	rows, err := r.DB.QueryxContext(ctx, "SELECT * FROM information_schema.tables WHERE table_schema = $1", schema)
-	if err != nil {
-		return nil, err
-	}
-
-	columnNames, _ := rows.Columns()
-
-	var tables []*postgres.Table
-
-	for rows.Next() {
-		columns := make([]string, len(columnNames))
-		columnPointers := make([]any, len(columnNames))
-
-		for i, _ := range columns {
-			columnPointers[i] = &columns[i]
-		}
-
-		rows.Scan(columnPointers...)
-
-		var table postgres.Table
+func (r *Repo) Dialect() engine.Dialect {
+	return Dialect{}
+}
 
-		for i, columnName := range columnNames {
-			if columnName == "table_name" {
-				table.Name = columns[i]
-				break
-			}
-		}
+func (r *Repo) GetDefaultSchema(ctx context.Context) (string, error) {
+	var schema string
 
-		tables = append(tables, &table)
+	if err := r.DB.QueryRowxContext(ctx, "SELECT current_schema()").Scan(&schema); err != nil {
+		return "", err
 	}
 
-	return tables, nil
+	return schema, nil
 }
 
-func (r *Repo) GetColumnsFromTable(ctx context.Context, tableName string) ([]*postgres.Column, error) {
-	rows, err := r.DB.QueryxContext(ctx,
-		fmt.Sprintf("SELECT * FROM information_schema.columns WHERE table_name = '%v' order by ordinal_position", tableName))
+func (r *Repo) GetAllSchemas(ctx context.Context) ([]string, error) {
+	var schemas []string
 
+	err := r.DB.SelectContext(ctx, &schemas,
+		`SELECT schema_name FROM information_schema.schemata 
+WHERE schema_name NOT IN ('pg_catalog', 'information_schema') AND schema_name NOT LIKE 'pg\_%' 
+ORDER BY schema_name`)
 	if err != nil {
 		return nil, err
 	}
 
-	columnNames, err := rows.Columns()
+	return schemas, nil
+}
+
+func (r *Repo) GetAllTables(ctx context.Context, schema string) ([]*entity.Table, error) {
+	var tables []*entity.Table
+
+	err := r.DB.SelectContext(ctx, &tables,
+		"SELECT table_name FROM information_schema.tables WHERE table_schema = $1 ORDER BY table_name", schema)
 	if err != nil {
 		return nil, err
 	}
 
-	columnTypes, err := rows.ColumnTypes()
+	return tables, nil
+}
+
+func (r *Repo) GetColumnsFromTable(ctx context.Context, schema, tableName string) ([]*entity.Column, error) {
+	var columns []*entity.Column
+
+	// udt_name matches type names reported by driver for query results
+	err := r.DB.SelectContext(ctx, &columns,
+		`SELECT c.column_name, c.udt_name AS data_type, c.is_nullable = 'YES' AS is_nullable,
+       EXISTS (SELECT 1
+               FROM information_schema.table_constraints tc
+               JOIN information_schema.key_column_usage k
+                    ON k.constraint_schema = tc.constraint_schema
+                        AND k.constraint_name = tc.constraint_name
+                        AND k.table_name = tc.table_name
+               WHERE tc.constraint_type = 'PRIMARY KEY'
+                 AND tc.table_schema = c.table_schema
+                 AND tc.table_name = c.table_name
+                 AND k.column_name = c.column_name) AS is_primary_key
+FROM information_schema.columns c
+WHERE c.table_schema = $1 AND c.table_name = $2 ORDER BY c.ordinal_position`, schema, tableName)
 	if err != nil {
 		return nil, err
 	}
 
-	var columns []*postgres.Column
-
-	for rows.Next() {
-		row := make(postgres.Row)
-
-		if err = rows.MapScan(row); err != nil {
-			return nil, err
-		}
-
-		cols := make([]string, len(columnNames))
-		columnPointers := make([]any, len(columnNames))
-
-		for i, _ := range cols {
-			columnPointers[i] = &cols[i]
-		}
-
-		rows.Scan(columnPointers...)
+	return columns, nil
+}
 
-		var column postgres.Column
+func (r *Repo) GetRows(ctx context.Context, q engine.SelectQuery) (*entity.QueryResult, error) {
+	query, args := engine.BuildSelect(Dialect{}, q)
 
-		for i, columnName := range columnNames {
-			if columnName == "column_name" {
-				column.Name = cols[i]
-			}
+	return engine.SelectRows(ctx, r.DB, nil, 0, query, args...)
+}
 
-			if columnName == "data_type" {
-				column.Type = strings.ToLower(columnTypes[i].DatabaseTypeName())
-			}
+func (r *Repo) StreamRows(ctx context.Context, q engine.SelectQuery, maxRows int, w engine.RowWriter) (*engine.StreamResult, error) {
+	return engine.StreamSelect(ctx, r.DB, Dialect{}, nil, q, maxRows, w)
+}
 
-			if column.Name != "" && column.Type != "" {
-				break
-			}
-		}
+func (r *Repo) CountRows(ctx context.Context, q engine.SelectQuery) (int64, error) {
+	query, args := engine.BuildCount(Dialect{}, q)
 
-		columns = append(columns, &column)
-	}
-
-	return columns, nil
+	return engine.CountRows(ctx, r.DB, query, args...)
 }
 
-func (r *Repo) GetAllRowsFromTable(ctx context.Context, tableName string) ([]*postgres.Row, error) {
+// EstimateRowCount uses planner statistics, exact count is used for tables that were never analyzed
+func (r *Repo) EstimateRowCount(ctx context.Context, schema, tableName string) (int64, error) {
+	var estimate float64
 
-	dbRows, err := r.DB.QueryxContext(ctx, fmt.Sprintf("SELECT * FROM %v", tableName))
+	err := r.DB.QueryRowxContext(ctx,
+		`SELECT c.reltuples FROM pg_class c JOIN pg_namespace n ON n.oid = c.relnamespace 
+WHERE n.nspname = $1 AND c.relname = $2`, schema, tableName).Scan(&estimate)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	var rows []*postgres.Row
+	if estimate < 0 {
+		return r.CountRows(ctx, engine.SelectQuery{Schema: schema, Table: tableName})
+	}
 
-	for dbRows.Next() {
-		row := make(postgres.Row)
+	return int64(estimate), nil
+}
 
-		if err = dbRows.MapScan(row); err != nil {
-			return nil, err
-		}
+func (r *Repo) ExecuteQuery(ctx context.Context, q engine.RawQuery) (*entity.QueryResult, error) {
+	return engine.ExecuteQuery(ctx, r.DB, Dialect{}, nil, q)
+}
 
-		rows = append(rows, &row)
-	}
+func (r *Repo) StreamQuery(ctx context.Context, q engine.RawQuery, w engine.RowWriter) (*engine.StreamResult, error) {
+	return engine.StreamQuery(ctx, r.DB, Dialect{}, nil, q, w)
+}
 
-	return rows, nil
+// ImportRows loads rows with COPY, see copyRows
+func (r *Repo) ImportRows(ctx context.Context, q engine.ImportQuery) (int64, error) {
+	return engine.ImportRows(ctx, r.DB, Dialect{}, q, copyRows)
 }
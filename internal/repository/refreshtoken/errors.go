@@ -0,0 +1,8 @@
+package refreshtoken
+
+import "errors"
+
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenRevoked  = errors.New("refresh token revoked")
+)
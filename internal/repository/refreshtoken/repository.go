@@ -0,0 +1,127 @@
+package refreshtoken
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+type Repo struct {
+	DB *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Repo {
+	return &Repo{
+		DB: db,
+	}
+}
+
+func (r *Repo) CreateRefreshToken(ctx context.Context, token entity.RefreshToken) (*entity.RefreshToken, error) {
+	var created entity.RefreshToken
+
+	err := r.DB.QueryRowxContext(ctx,
+		`INSERT INTO refresh_tokens (user_id, session_id, token_hash, expires_at, created_at) 
+VALUES ($1, $2, $3, $4, $5) 
+RETURNING *`,
+		token.UserID, token.SessionID, token.TokenHash, token.ExpiresAt, token.CreatedAt).StructScan(&created)
+	if err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (r *Repo) GetRefreshTokenByHash(ctx context.Context, hash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+
+	err := r.DB.QueryRowxContext(ctx, "SELECT * FROM refresh_tokens WHERE token_hash = $1", hash).StructScan(&token)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrRefreshTokenNotFound
+		}
+
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// RotateRefreshToken revokes old token and stores new one in single transaction,
+// old token is revoked only once so that concurrent rotations of the same token can't both succeed
+func (r *Repo) RotateRefreshToken(ctx context.Context, oldID int, token entity.RefreshToken) (*entity.RefreshToken, error) {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		"UPDATE refresh_tokens SET revoked_at = $1 WHERE id = $2 AND revoked_at IS NULL",
+		token.CreatedAt, oldID)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	if affected == 0 {
+		return nil, ErrRefreshTokenRevoked
+	}
+
+	var created entity.RefreshToken
+
+	err = tx.QueryRowxContext(ctx,
+		`INSERT INTO refresh_tokens (user_id, session_id, token_hash, expires_at, created_at) 
+VALUES ($1, $2, $3, $4, $5) 
+RETURNING *`,
+		token.UserID, token.SessionID, token.TokenHash, token.ExpiresAt, token.CreatedAt).StructScan(&created)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (r *Repo) RevokeSession(ctx context.Context, sessionID string, revokedAt time.Time) error {
+	_, err := r.DB.ExecContext(ctx,
+		"UPDATE refresh_tokens SET revoked_at = $1 WHERE session_id = $2 AND revoked_at IS NULL",
+		revokedAt, sessionID)
+
+	return err
+}
+
+// RevokeUserSessions revokes every session of user except the one with exceptSessionID,
+// so that changes of user rights and credentials take effect immediately
+func (r *Repo) RevokeUserSessions(ctx context.Context, userID int, exceptSessionID string, revokedAt time.Time) error {
+	_, err := r.DB.ExecContext(ctx,
+		"UPDATE refresh_tokens SET revoked_at = $1 WHERE user_id = $2 AND session_id <> $3 AND revoked_at IS NULL",
+		revokedAt, userID, exceptSessionID)
+
+	return err
+}
+
+// IsSessionActive reports whether session has not revoked and not expired refresh token
+func (r *Repo) IsSessionActive(ctx context.Context, sessionID string, now time.Time) (bool, error) {
+	var active bool
+
+	err := r.DB.QueryRowxContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM refresh_tokens WHERE session_id = $1 AND revoked_at IS NULL AND expires_at > $2)",
+		sessionID, now).Scan(&active)
+	if err != nil {
+		return false, err
+	}
+
+	return active, nil
+}
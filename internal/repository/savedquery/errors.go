@@ -0,0 +1,8 @@
+package savedquery
+
+import "errors"
+
+var (
+	ErrSavedQueryNotFound   = errors.New("saved query not found")
+	ErrSavedQueryNameExists = errors.New("saved query with this name already exists")
+)
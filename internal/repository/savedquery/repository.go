@@ -0,0 +1,120 @@
+package savedquery
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+const uniqueViolationCode = "23505"
+
+type Repo struct {
+	DB *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Repo {
+	return &Repo{
+		DB: db,
+	}
+}
+
+// GetAllSavedQueries returns saved queries of workspace or, if workspaceID is nil, personal saved queries of user.
+// Empty tag disables filtering by tag
+func (r *Repo) GetAllSavedQueries(ctx context.Context, userID int, workspaceID *int, tag string, offset, limit int) ([]*entity.SavedQuery, error) {
+	rows, err := r.DB.QueryxContext(ctx,
+		`SELECT * FROM saved_queries 
+WHERE workspace_id IS NOT DISTINCT FROM $2 AND ($2 IS NOT NULL OR user_id = $1) AND ($3 = '' OR tags ? $3) 
+ORDER BY name LIMIT $4 OFFSET $5`,
+		userID, workspaceID, tag, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var queries []*entity.SavedQuery
+
+	for rows.Next() {
+		var query entity.SavedQuery
+
+		if err = rows.StructScan(&query); err != nil {
+			return nil, err
+		}
+
+		queries = append(queries, &query)
+	}
+
+	return queries, rows.Err()
+}
+
+func (r *Repo) GetSavedQueryByID(ctx context.Context, id int) (*entity.SavedQuery, error) {
+	var query entity.SavedQuery
+
+	err := r.DB.QueryRowxContext(ctx, "SELECT * FROM saved_queries WHERE id = $1", id).StructScan(&query)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &query, nil
+}
+
+func (r *Repo) CreateSavedQuery(ctx context.Context, query entity.SavedQuery) (*entity.SavedQuery, error) {
+	var created entity.SavedQuery
+
+	err := r.DB.QueryRowxContext(ctx,
+		`INSERT INTO saved_queries (user_id, workspace_id, connection_id, name, description, sql, parameters, tags, created_at, updated_at) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) 
+RETURNING *`,
+		query.UserID, query.WorkspaceID, query.ConnectionID, query.Name, query.Description, query.SQL,
+		query.Parameters, query.Tags, query.CreatedAt, query.UpdatedAt).StructScan(&created)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &created, nil
+}
+
+func (r *Repo) UpdateSavedQuery(ctx context.Context, query entity.SavedQuery) (*entity.SavedQuery, error) {
+	var updated entity.SavedQuery
+
+	err := r.DB.QueryRowxContext(ctx,
+		`UPDATE saved_queries 
+SET connection_id = $1, name = $2, description = $3, sql = $4, parameters = $5, tags = $6, updated_at = $7 
+WHERE id = $8 
+RETURNING *`,
+		query.ConnectionID, query.Name, query.Description, query.SQL,
+		query.Parameters, query.Tags, query.UpdatedAt, query.ID).StructScan(&updated)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &updated, nil
+}
+
+func (r *Repo) DeleteSavedQuery(ctx context.Context, id int) (*entity.SavedQuery, error) {
+	var deleted entity.SavedQuery
+
+	err := r.DB.QueryRowxContext(ctx, "DELETE FROM saved_queries WHERE id = $1 RETURNING *", id).StructScan(&deleted)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &deleted, nil
+}
+
+func mapErr(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrSavedQueryNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return ErrSavedQueryNameExists
+	}
+
+	return err
+}
@@ -0,0 +1,127 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/repository/engine"
+
+	_ "modernc.org/sqlite"
+)
+
+type Dialect struct{}
+
+func (Dialect) QuoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func (Dialect) Placeholder(int) string {
+	return "?"
+}
+
+func (Dialect) LimitOffset(limitPlaceholder, offsetPlaceholder string) string {
+	return "LIMIT " + limitPlaceholder + " OFFSET " + offsetPlaceholder
+}
+
+func (Dialect) LexOptions() engine.LexOptions {
+	return engine.LexOptions{}
+}
+
+// TruncateTime returns text in sqlite datetime format, weeks start on monday
+func (Dialect) TruncateTime(expr string, grain entity.TimeBucket) string {
+	switch grain {
+	case entity.TimeBucketMinute:
+		return fmt.Sprintf("strftime('%%Y-%%m-%%d %%H:%%M:00', %v)", expr)
+
+	case entity.TimeBucketHour:
+		return fmt.Sprintf("strftime('%%Y-%%m-%%d %%H:00:00', %v)", expr)
+
+	case entity.TimeBucketWeek:
+		return fmt.Sprintf("strftime('%%Y-%%m-%%d 00:00:00', %v, '-6 days', 'weekday 1')", expr)
+
+	case entity.TimeBucketMonth:
+		return fmt.Sprintf("strftime('%%Y-%%m-01 00:00:00', %v)", expr)
+
+	case entity.TimeBucketYear:
+		return fmt.Sprintf("strftime('%%Y-01-01 00:00:00', %v)", expr)
+
+	default:
+		return fmt.Sprintf("strftime('%%Y-%%m-%%d 00:00:00', %v)", expr)
+	}
+}
+
+// GuardStatements enforces read only mode with query_only pragma as driver ignores READ ONLY transactions,
+// timeout is enforced by context only
+func (Dialect) GuardStatements(_ time.Duration, readOnly bool) ([]string, []string) {
+	if !readOnly {
+		return nil, nil
+	}
+
+	return []string{"PRAGMA query_only = ON"}, []string{"PRAGMA query_only = OFF"}
+}
+
+type Driver struct {
+	BaseDir string
+}
+
+func NewDriver(baseDir string) *Driver {
+	return &Driver{
+		BaseDir: baseDir,
+	}
+}
+
+func (d *Driver) Name() string {
+	return "sqlite"
+}
+
+func (d *Driver) DriverName() string {
+	return "sqlite"
+}
+
+func (d *Driver) Dialect() engine.Dialect {
+	return Dialect{}
+}
+
+// ResolveDSN treats dsn as path relative to base dir, optionally prefixed with file: and followed by query options.
+// Paths escaping base dir and missing files are rejected, otherwise sqlite would silently create a new database
+func (d *Driver) ResolveDSN(dsn string) (string, error) {
+	path, options, _ := strings.Cut(strings.TrimPrefix(dsn, "file:"), "?")
+
+	baseDir, err := filepath.Abs(d.BaseDir)
+	if err != nil {
+		return "", err
+	}
+
+	full := filepath.Join(baseDir, filepath.Clean("/"+path))
+
+	if rel, err := filepath.Rel(baseDir, full); err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", ErrPathOutsideBaseDir
+	}
+
+	if _, err = os.Stat(full); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", ErrFileNotFound
+		}
+
+		return "", err
+	}
+
+	resolved := "file:" + full
+	if options != "" {
+		resolved += "?" + options
+	}
+
+	return resolved, nil
+}
+
+func (d *Driver) NewRepo(db *sqlx.DB) engine.Repo {
+	return New(db)
+}
@@ -0,0 +1,23 @@
+package sqlite
+
+import "testing"
+
+func TestQuoteIdentifier(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"users", `"users"`},
+		{`a"b`, `"a""b"`},
+		{`"; DROP TABLE users; --`, `"""; DROP TABLE users; --"`},
+		{"a`b", "\"a`b\""},
+		{"a'b", `"a'b"`},
+		{"", `""`},
+	}
+
+	for _, tt := range tests {
+		if got := (Dialect{}).QuoteIdentifier(tt.name); got != tt.want {
+			t.Errorf("QuoteIdentifier(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
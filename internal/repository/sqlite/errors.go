@@ -0,0 +1,8 @@
+package sqlite
+
+import "errors"
+
+var (
+	ErrPathOutsideBaseDir = errors.New("database file is outside of allowed directory")
+	ErrFileNotFound       = errors.New("database file not found")
+)
@@ -0,0 +1,106 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/repository/engine"
+)
+
+const mainSchema = "main"
+
+type Repo struct {
+	DB *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Repo {
+	return &Repo{
+		DB: db,
+	}
+}
+
+func (r *Repo) Dialect() engine.Dialect {
+	return Dialect{}
+}
+
+func (r *Repo) GetDefaultSchema(context.Context) (string, error) {
+	return mainSchema, nil
+}
+
+// GetAllSchemas returns attached databases, main is always present
+func (r *Repo) GetAllSchemas(ctx context.Context) ([]string, error) {
+	var schemas []string
+
+	if err := r.DB.SelectContext(ctx, &schemas, "SELECT name FROM pragma_database_list ORDER BY seq"); err != nil {
+		return nil, err
+	}
+
+	return schemas, nil
+}
+
+func (r *Repo) GetAllTables(ctx context.Context, schema string) ([]*entity.Table, error) {
+	var tables []*entity.Table
+
+	// schema of sqlite_master can not be bound as parameter
+	query := fmt.Sprintf(
+		`SELECT name AS table_name FROM %v 
+WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite\_%%' ESCAPE '\' ORDER BY name`,
+		engine.QualifiedName(Dialect{}, schema, "sqlite_master"))
+
+	if err := r.DB.SelectContext(ctx, &tables, query); err != nil {
+		return nil, err
+	}
+
+	return tables, nil
+}
+
+func (r *Repo) GetColumnsFromTable(ctx context.Context, schema, tableName string) ([]*entity.Column, error) {
+	var columns []*entity.Column
+
+	// primary key columns are reported not null, sqlite tolerates nulls only in legacy rowid tables
+	err := r.DB.SelectContext(ctx, &columns,
+		`SELECT name AS column_name, lower(type) AS data_type, "notnull" = 0 AND pk = 0 AS is_nullable, pk > 0 AS is_primary_key 
+FROM pragma_table_info(?, ?) ORDER BY cid`,
+		tableName, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return columns, nil
+}
+
+func (r *Repo) GetRows(ctx context.Context, q engine.SelectQuery) (*entity.QueryResult, error) {
+	query, args := engine.BuildSelect(Dialect{}, q)
+
+	return engine.SelectRows(ctx, r.DB, nil, 0, query, args...)
+}
+
+func (r *Repo) StreamRows(ctx context.Context, q engine.SelectQuery, maxRows int, w engine.RowWriter) (*engine.StreamResult, error) {
+	return engine.StreamSelect(ctx, r.DB, Dialect{}, nil, q, maxRows, w)
+}
+
+func (r *Repo) CountRows(ctx context.Context, q engine.SelectQuery) (int64, error) {
+	query, args := engine.BuildCount(Dialect{}, q)
+
+	return engine.CountRows(ctx, r.DB, query, args...)
+}
+
+// EstimateRowCount falls back to exact count as sqlite keeps no row statistics
+func (r *Repo) EstimateRowCount(ctx context.Context, schema, tableName string) (int64, error) {
+	return r.CountRows(ctx, engine.SelectQuery{Schema: schema, Table: tableName})
+}
+
+func (r *Repo) ExecuteQuery(ctx context.Context, q engine.RawQuery) (*entity.QueryResult, error) {
+	return engine.ExecuteQuery(ctx, r.DB, Dialect{}, nil, q)
+}
+
+func (r *Repo) StreamQuery(ctx context.Context, q engine.RawQuery, w engine.RowWriter) (*engine.StreamResult, error) {
+	return engine.StreamQuery(ctx, r.DB, Dialect{}, nil, q, w)
+}
+
+func (r *Repo) ImportRows(ctx context.Context, q engine.ImportQuery) (int64, error) {
+	return engine.ImportRows(ctx, r.DB, Dialect{}, q, nil)
+}
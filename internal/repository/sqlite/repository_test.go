@@ -0,0 +1,127 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/repository/engine"
+)
+
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+
+	db, err := sqlx.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	// every pooled connection would get its own in-memory database
+	db.SetMaxOpenConns(1)
+
+	db.MustExec(`CREATE TABLE "we""ird; table" (id INTEGER PRIMARY KEY, "na""me" TEXT)`)
+	db.MustExec(`INSERT INTO "we""ird; table" ("na""me") VALUES ('a'), ('b'), ('c'), ('it''s')`)
+
+	return New(db)
+}
+
+func TestGetRowsHostileInput(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	const table = `we"ird; table`
+
+	tests := []struct {
+		name     string
+		filters  []entity.Filter
+		limit    int
+		offset   int
+		wantRows int
+	}{
+		{"no filter", nil, 10, 0, 4},
+		{"quoted column", []entity.Filter{{Column: `na"me`, Op: entity.FilterOpEq, Values: []string{"a"}}}, 10, 0, 1},
+		{"quote in value", []entity.Filter{{Column: `na"me`, Op: entity.FilterOpEq, Values: []string{"it's"}}}, 10, 0, 1},
+		{"tautology", []entity.Filter{{Column: `na"me`, Op: entity.FilterOpEq, Values: []string{"' OR '1'='1"}}}, 10, 0, 0},
+		{"statement", []entity.Filter{{Column: `na"me`, Op: entity.FilterOpEq, Values: []string{`a'; DROP TABLE "we""ird; table"; --`}}}, 10, 0, 0},
+		{"in list", []entity.Filter{{Column: "id", Op: entity.FilterOpIn, Values: []string{"1", "2) OR (1=1"}}}, 10, 0, 1},
+		{"like wildcard stays pattern", []entity.Filter{{Column: `na"me`, Op: entity.FilterOpLike, Values: []string{"%' OR '1'='1"}}}, 10, 0, 0},
+		{"limit", nil, 2, 0, 2},
+		{"offset", nil, 10, 3, 1},
+		{"offset past end", nil, 10, 10, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := engine.SelectQuery{Schema: mainSchema, Table: table, Filters: tt.filters, Limit: tt.limit, Offset: tt.offset}
+
+			result, err := repo.GetRows(ctx, q)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(result.Rows) != tt.wantRows {
+				t.Errorf("got %v rows, want %v", len(result.Rows), tt.wantRows)
+			}
+
+			count, err := repo.CountRows(ctx, q)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.limit >= 4 && tt.offset == 0 && count != int64(tt.wantRows) {
+				t.Errorf("counted %v rows, want %v", count, tt.wantRows)
+			}
+		})
+	}
+
+	if count, err := repo.CountRows(ctx, engine.SelectQuery{Schema: mainSchema, Table: table}); err != nil || count != 4 {
+		t.Errorf("table changed, count %v, error %v", count, err)
+	}
+}
+
+func TestGetColumnsFromTableHostileInput(t *testing.T) {
+	repo := newTestRepo(t)
+	ctx := context.Background()
+
+	columns, err := repo.GetColumnsFromTable(ctx, mainSchema, `we"ird; table`)
+	if err != nil || len(columns) != 2 || columns[1].Name != `na"me` {
+		t.Fatalf("GetColumnsFromTable = %v, %v", columns, err)
+	}
+
+	for _, table := range []string{`we"ird; table' OR '1'='1`, "x'); DROP TABLE items; --"} {
+		columns, err = repo.GetColumnsFromTable(ctx, mainSchema, table)
+		if err != nil || len(columns) != 0 {
+			t.Errorf("GetColumnsFromTable(%q) = %v, %v, want no columns", table, columns, err)
+		}
+	}
+}
+
+func TestGetColumnsFromTableKeys(t *testing.T) {
+	repo := newTestRepo(t)
+
+	repo.DB.MustExec(`CREATE TABLE pairs (a TEXT NOT NULL, b INTEGER, c TEXT, PRIMARY KEY (a, b))`)
+
+	columns, err := repo.GetColumnsFromTable(context.Background(), mainSchema, "pairs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []entity.Column{
+		{Name: "a", Type: "text", PrimaryKey: true},
+		{Name: "b", Type: "integer", PrimaryKey: true},
+		{Name: "c", Type: "text", Nullable: true},
+	}
+
+	if len(columns) != len(want) {
+		t.Fatalf("columns = %v, want %v", columns, want)
+	}
+
+	for i, column := range columns {
+		if *column != want[i] {
+			t.Errorf("column %v = %+v, want %+v", i, *column, want[i])
+		}
+	}
+}
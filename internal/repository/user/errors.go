@@ -3,5 +3,6 @@ package user
 import "errors"
 
 var (
-	ErrEmailExists = errors.New("user with this email already exists")
+	ErrEmailExists  = errors.New("user with this email already exists")
+	ErrUserNotFound = errors.New("user not found")
 )
@@ -2,12 +2,27 @@ package user
 
 import (
 	"context"
+	"database/sql"
 	"db-dashboards/internal/domain/entity"
+	"errors"
 	"fmt"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jmoiron/sqlx"
-	"math"
+	"strings"
+	"time"
 )
 
+const uniqueViolationCode = "23505"
+
+// likeEscaper makes search text match literally inside LIKE pattern
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// columns users may be looked up by, argName is never taken from client input but is still checked
+var lookupColumns = map[string]bool{
+	"id":    true,
+	"email": true,
+}
+
 type Repo struct {
 	DB *sqlx.DB
 }
@@ -18,19 +33,15 @@ func New(db *sqlx.DB) *Repo {
 	}
 }
 
-func (r *Repo) GetAllUsers(ctx context.Context, limit, offset int) ([]*entity.User, error) {
-	var query string
-
-	if limit == math.MaxInt64 {
-		query = fmt.Sprintf("SELECT * FROM users ORDER BY created_at OFFSET %v", offset)
-	} else {
-		query = fmt.Sprintf("SELECT * FROM users ORDER BY created_at LIMIT %v OFFSET %v", limit, offset)
-	}
-
-	rows, err := r.DB.QueryxContext(ctx, query)
+// GetAllUsers returns page of users which email contains search, empty search matches every user
+func (r *Repo) GetAllUsers(ctx context.Context, search string, offset, limit int) ([]*entity.User, error) {
+	rows, err := r.DB.QueryxContext(ctx,
+		"SELECT * FROM users WHERE email ILIKE $1 ORDER BY created_at, id LIMIT $2 OFFSET $3",
+		"%"+likeEscaper.Replace(search)+"%", limit, offset)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	var users []*entity.User
 
@@ -45,21 +56,15 @@ func (r *Repo) GetAllUsers(ctx context.Context, limit, offset int) ([]*entity.Us
 		users = append(users, &user)
 	}
 
-	return users, nil
+	return users, rows.Err()
 }
 
 func (r *Repo) getUserByArg(ctx context.Context, argName string, arg any) (*entity.User, error) {
-	var query string
-
-	switch arg.(type) {
-	case string:
-		query = fmt.Sprintf("SELECT * FROM users WHERE %v = '%v'", argName, arg)
-
-	case int, float64:
-		query = fmt.Sprintf("SELECT * FROM users WHERE %v = %v", argName, arg)
+	if !lookupColumns[argName] {
+		return nil, fmt.Errorf("users can not be looked up by %v", argName)
 	}
 
-	row := r.DB.QueryRowxContext(ctx, query)
+	row := r.DB.QueryRowxContext(ctx, fmt.Sprintf("SELECT * FROM users WHERE %v = $1", argName), arg)
 	if err := row.Err(); err != nil {
 		return nil, err
 	}
@@ -68,7 +73,7 @@ func (r *Repo) getUserByArg(ctx context.Context, argName string, arg any) (*enti
 
 	err := row.StructScan(&user)
 	if err != nil {
-		return nil, err
+		return nil, mapErr(err)
 	}
 
 	return &user, nil
@@ -82,48 +87,156 @@ func (r *Repo) GetUserByEmail(ctx context.Context, email string) (*entity.User,
 	return r.getUserByArg(ctx, "email", email)
 }
 
-func (r *Repo) CreateUser(ctx context.Context, user entity.User) (*entity.User, error) {
-	result, err := r.DB.NamedQueryContext(ctx,
-		`INSERT INTO users (email, hashed_password, created_at, updated_at) 
-VALUES (:email, :hashed_password, :created_at, :updated_at) 
-RETURNING id, email, hashed_password, created_at, updated_at`,
-		&user)
+// RegisterUser creates user with role of user, or with firstRole if there are no users yet.
+// Table is locked against concurrent inserts until commit, so that only one user can be the first
+func (r *Repo) RegisterUser(ctx context.Context, user entity.User, firstRole entity.Role) (*entity.User, error) {
+	tx, err := r.DB.BeginTxx(ctx, nil)
 	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
 
-	var usr entity.User
+	// the mode conflicts with itself and with inserts, reads are not blocked
+	if _, err = tx.ExecContext(ctx, "LOCK TABLE users IN SHARE ROW EXCLUSIVE MODE"); err != nil {
+		return nil, err
+	}
 
-	if result.Next() {
-		if err = result.StructScan(&usr); err != nil {
-			return nil, err
-		}
+	var created entity.User
+
+	err = tx.QueryRowxContext(ctx,
+		`INSERT INTO users (email, hashed_password, role, created_at, updated_at)
+SELECT $1, $2, CASE WHEN EXISTS (SELECT 1 FROM users) THEN $3::varchar ELSE $4::varchar END, $5, $6
+RETURNING *`,
+		user.Email, user.HashedPassword, user.Role, firstRole, user.CreatedAt, user.UpdatedAt).StructScan(&created)
+	if err != nil {
+		return nil, mapErr(err)
 	}
 
-	return &usr, nil
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (r *Repo) UpdateUser(ctx context.Context, user entity.User) (*entity.User, error) {
+	var updated entity.User
+
+	err := r.DB.QueryRowxContext(ctx,
+		"UPDATE users SET email = $1, hashed_password = $2, updated_at = $3 WHERE id = $4 RETURNING *",
+		user.Email, user.HashedPassword, user.UpdatedAt, user.ID).StructScan(&updated)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &updated, nil
 }
 
+// DeleteUser deletes user with personal resources of user. Resources user created in workspaces pass
+// to owner of workspace who joined first, or to member who joined first if user was the only owner
 func (r *Repo) DeleteUser(ctx context.Context, id int) (*entity.User, error) {
-	row := r.DB.QueryRowxContext(ctx, "DELETE FROM users WHERE id = $1", id)
-	if err := row.Err(); err != nil {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
 		return nil, err
 	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"connections", "saved_queries", "dashboards"} {
+		_, err = tx.ExecContext(ctx,
+			fmt.Sprintf(`UPDATE %s r SET user_id = h.user_id
+FROM (
+    SELECT DISTINCT ON (workspace_id) workspace_id, user_id
+    FROM workspace_members
+    WHERE user_id <> $1
+    ORDER BY workspace_id, role <> $2, created_at, user_id
+) h
+WHERE r.workspace_id = h.workspace_id AND r.user_id = $1`, table),
+			id, entity.WorkspaceRoleOwner)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	var user entity.User
 
-	err := row.StructScan(&user)
+	err = tx.QueryRowxContext(ctx, "DELETE FROM users WHERE id = $1 RETURNING *", id).StructScan(&user)
 	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	if err = tx.Commit(); err != nil {
 		return nil, err
 	}
 
 	return &user, nil
 }
 
+func (r *Repo) UpdateUserRole(ctx context.Context, id int, role entity.Role, updatedAt time.Time) (*entity.User, error) {
+	var user entity.User
+
+	err := r.DB.QueryRowxContext(ctx,
+		"UPDATE users SET role = $1, updated_at = $2 WHERE id = $3 RETURNING *",
+		role, updatedAt, id).StructScan(&user)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &user, nil
+}
+
+// SetUserDisabled disables user when disabledAt is set and enables otherwise
+func (r *Repo) SetUserDisabled(ctx context.Context, id int, disabledAt *time.Time, updatedAt time.Time) (*entity.User, error) {
+	var user entity.User
+
+	err := r.DB.QueryRowxContext(ctx,
+		"UPDATE users SET disabled_at = $1, updated_at = $2 WHERE id = $3 RETURNING *",
+		disabledAt, updatedAt, id).StructScan(&user)
+	if err != nil {
+		return nil, mapErr(err)
+	}
+
+	return &user, nil
+}
+
+// CountUsersWithRole counts enabled users having role
+func (r *Repo) CountUsersWithRole(ctx context.Context, role entity.Role) (int, error) {
+	var count int
+
+	err := r.DB.QueryRowxContext(ctx,
+		"SELECT count(*) FROM users WHERE role = $1 AND disabled_at IS NULL",
+		role).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CheckUniqueConstraints returns ErrEmailExists if user with email exists, lookup errors are returned as is
 func (r *Repo) CheckUniqueConstraints(ctx context.Context, email string) error {
-	got, err := r.GetUserByEmail(ctx, email)
-	if got != nil || err == nil {
+	_, err := r.GetUserByEmail(ctx, email)
+
+	switch {
+	case err == nil:
+		return ErrEmailExists
+
+	case errors.Is(err, ErrUserNotFound):
+		return nil
+
+	default:
+		return err
+	}
+}
+
+func mapErr(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
 		return ErrEmailExists
 	}
 
-	return nil
+	return err
 }
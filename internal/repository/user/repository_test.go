@@ -0,0 +1,84 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestGetUserByArgUnknownColumn(t *testing.T) {
+	// repo has no database, query must be refused before reaching it
+	repo := &Repo{}
+
+	for _, argName := range []string{
+		"",
+		"hashed_password",
+		"id = id OR 1 = 1 --",
+		"email; DROP TABLE users",
+		`"id"`,
+		"ID",
+	} {
+		if _, err := repo.getUserByArg(context.Background(), argName, 1); err == nil {
+			t.Errorf("getUserByArg(%q) succeeded", argName)
+		}
+	}
+}
+
+// newTestRepo returns repo over sqlite stand-in of users table, lookups by email are portable enough to run on it
+func newTestRepo(t *testing.T) *Repo {
+	t.Helper()
+
+	db, err := sqlx.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	db.SetMaxOpenConns(1)
+
+	db.MustExec(`CREATE TABLE users (
+id INTEGER PRIMARY KEY,
+email TEXT NOT NULL UNIQUE,
+hashed_password TEXT NOT NULL,
+role TEXT NOT NULL,
+disabled_at TIMESTAMP,
+created_at TIMESTAMP NOT NULL,
+updated_at TIMESTAMP NOT NULL)`)
+	db.MustExec(`INSERT INTO users (email, hashed_password, role, created_at, updated_at)
+VALUES ('taken@example.com', 'hash', 'admin', '2024-05-30 10:24:15', '2024-05-30 10:24:15')`)
+
+	return New(db)
+}
+
+func TestCheckUniqueConstraints(t *testing.T) {
+	repo := newTestRepo(t)
+
+	tests := []struct {
+		email   string
+		wantErr error
+	}{
+		{"taken@example.com", ErrEmailExists},
+		{"free@example.com", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			if err := repo.CheckUniqueConstraints(context.Background(), tt.email); !errors.Is(err, tt.wantErr) {
+				t.Errorf("CheckUniqueConstraints() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("lookup error", func(t *testing.T) {
+		repo.DB.Close()
+
+		err := repo.CheckUniqueConstraints(context.Background(), "free@example.com")
+		if err == nil || errors.Is(err, ErrEmailExists) {
+			t.Errorf("CheckUniqueConstraints() error = %v, want lookup error", err)
+		}
+	})
+}
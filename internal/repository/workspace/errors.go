@@ -0,0 +1,13 @@
+package workspace
+
+import "errors"
+
+var (
+	ErrWorkspaceNotFound = errors.New("workspace not found")
+
+	ErrMemberNotFound = errors.New("workspace member not found")
+	ErrMemberExists   = errors.New("user is already member of workspace")
+
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvitationExists   = errors.New("invitation with this email already exists")
+)
@@ -0,0 +1,367 @@
+package workspace
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+const uniqueViolationCode = "23505"
+
+type Repo struct {
+	DB *sqlx.DB
+}
+
+func New(db *sqlx.DB) *Repo {
+	return &Repo{
+		DB: db,
+	}
+}
+
+// GetUserWorkspaces returns workspaces user is member of, role is set from membership of user
+func (r *Repo) GetUserWorkspaces(ctx context.Context, userID, offset, limit int) ([]*entity.Workspace, error) {
+	rows, err := r.DB.QueryxContext(ctx,
+		`SELECT w.*, m.role 
+FROM workspaces w 
+JOIN workspace_members m ON m.workspace_id = w.id 
+WHERE m.user_id = $1 
+ORDER BY w.name, w.id LIMIT $2 OFFSET $3`,
+		userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var workspaces []*entity.Workspace
+
+	for rows.Next() {
+		var ws entity.Workspace
+
+		if err = rows.StructScan(&ws); err != nil {
+			return nil, err
+		}
+
+		workspaces = append(workspaces, &ws)
+	}
+
+	return workspaces, rows.Err()
+}
+
+// GetWorkspaceByID returns workspace with role of user, role is empty if user is not member of workspace
+func (r *Repo) GetWorkspaceByID(ctx context.Context, id, userID int) (*entity.Workspace, error) {
+	var ws entity.Workspace
+
+	err := r.DB.QueryRowxContext(ctx,
+		`SELECT w.*, COALESCE(m.role, '') AS role 
+FROM workspaces w 
+LEFT JOIN workspace_members m ON m.workspace_id = w.id AND m.user_id = $2 
+WHERE w.id = $1`,
+		id, userID).StructScan(&ws)
+	if err != nil {
+		return nil, mapErr(err, ErrWorkspaceNotFound, nil)
+	}
+
+	return &ws, nil
+}
+
+// CreateWorkspace creates workspace and makes user its owner
+func (r *Repo) CreateWorkspace(ctx context.Context, ws entity.Workspace, ownerID int) (*entity.Workspace, error) {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var created entity.Workspace
+
+	err = tx.QueryRowxContext(ctx,
+		`INSERT INTO workspaces (name, created_at, updated_at) VALUES ($1, $2, $3) RETURNING *, $4::varchar AS role`,
+		ws.Name, ws.CreatedAt, ws.UpdatedAt, entity.WorkspaceRoleOwner).StructScan(&created)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO workspace_members (workspace_id, user_id, role, created_at, updated_at) VALUES ($1, $2, $3, $4, $4)`,
+		created.ID, ownerID, entity.WorkspaceRoleOwner, ws.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &created, nil
+}
+
+func (r *Repo) UpdateWorkspace(ctx context.Context, ws entity.Workspace) (*entity.Workspace, error) {
+	var updated entity.Workspace
+
+	err := r.DB.QueryRowxContext(ctx,
+		"UPDATE workspaces SET name = $1, updated_at = $2 WHERE id = $3 RETURNING *, $4::varchar AS role",
+		ws.Name, ws.UpdatedAt, ws.ID, ws.Role).StructScan(&updated)
+	if err != nil {
+		return nil, mapErr(err, ErrWorkspaceNotFound, nil)
+	}
+
+	return &updated, nil
+}
+
+// DeleteWorkspace deletes workspace with every resource of it
+func (r *Repo) DeleteWorkspace(ctx context.Context, id int) (*entity.Workspace, error) {
+	var deleted entity.Workspace
+
+	err := r.DB.QueryRowxContext(ctx, "DELETE FROM workspaces WHERE id = $1 RETURNING *, '' AS role", id).StructScan(&deleted)
+	if err != nil {
+		return nil, mapErr(err, ErrWorkspaceNotFound, nil)
+	}
+
+	return &deleted, nil
+}
+
+func (r *Repo) GetMembers(ctx context.Context, workspaceID int) ([]*entity.Membership, error) {
+	rows, err := r.DB.QueryxContext(ctx,
+		`SELECT m.*, u.email 
+FROM workspace_members m 
+JOIN users u ON u.id = m.user_id 
+WHERE m.workspace_id = $1 
+ORDER BY m.created_at, m.user_id`,
+		workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []*entity.Membership
+
+	for rows.Next() {
+		var member entity.Membership
+
+		if err = rows.StructScan(&member); err != nil {
+			return nil, err
+		}
+
+		members = append(members, &member)
+	}
+
+	return members, rows.Err()
+}
+
+func (r *Repo) GetMember(ctx context.Context, workspaceID, userID int) (*entity.Membership, error) {
+	var member entity.Membership
+
+	err := r.DB.QueryRowxContext(ctx,
+		`SELECT m.*, u.email 
+FROM workspace_members m 
+JOIN users u ON u.id = m.user_id 
+WHERE m.workspace_id = $1 AND m.user_id = $2`,
+		workspaceID, userID).StructScan(&member)
+	if err != nil {
+		return nil, mapErr(err, ErrMemberNotFound, nil)
+	}
+
+	return &member, nil
+}
+
+func (r *Repo) UpdateMemberRole(ctx context.Context, workspaceID, userID int, role entity.WorkspaceRole, updatedAt time.Time) (*entity.Membership, error) {
+	var updated entity.Membership
+
+	err := r.DB.QueryRowxContext(ctx,
+		`WITH m AS (
+    UPDATE workspace_members SET role = $1, updated_at = $2 WHERE workspace_id = $3 AND user_id = $4 RETURNING *
+) 
+SELECT m.*, u.email FROM m JOIN users u ON u.id = m.user_id`,
+		role, updatedAt, workspaceID, userID).StructScan(&updated)
+	if err != nil {
+		return nil, mapErr(err, ErrMemberNotFound, nil)
+	}
+
+	return &updated, nil
+}
+
+// DeleteMember removes user from workspace and passes workspace resources created by user to heir,
+// so that they outlive membership of their creator
+func (r *Repo) DeleteMember(ctx context.Context, workspaceID, userID, heirID int) (*entity.Membership, error) {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"connections", "saved_queries", "dashboards"} {
+		_, err = tx.ExecContext(ctx,
+			fmt.Sprintf("UPDATE %s SET user_id = $1 WHERE workspace_id = $2 AND user_id = $3", table),
+			heirID, workspaceID, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var deleted entity.Membership
+
+	err = tx.QueryRowxContext(ctx,
+		`WITH m AS (
+    DELETE FROM workspace_members WHERE workspace_id = $1 AND user_id = $2 RETURNING *
+) 
+SELECT m.*, u.email FROM m JOIN users u ON u.id = m.user_id`,
+		workspaceID, userID).StructScan(&deleted)
+	if err != nil {
+		return nil, mapErr(err, ErrMemberNotFound, nil)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &deleted, nil
+}
+
+func (r *Repo) CountMembersWithRole(ctx context.Context, workspaceID int, role entity.WorkspaceRole) (int, error) {
+	var count int
+
+	err := r.DB.QueryRowxContext(ctx,
+		"SELECT count(*) FROM workspace_members WHERE workspace_id = $1 AND role = $2",
+		workspaceID, role).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *Repo) GetInvitations(ctx context.Context, workspaceID int) ([]*entity.Invitation, error) {
+	return r.selectInvitations(ctx,
+		"SELECT * FROM workspace_invitations WHERE workspace_id = $1 ORDER BY created_at, id",
+		workspaceID)
+}
+
+// GetInvitationsByEmail returns invitations addressed to email, emails are compared case-insensitively
+func (r *Repo) GetInvitationsByEmail(ctx context.Context, email string) ([]*entity.Invitation, error) {
+	return r.selectInvitations(ctx,
+		"SELECT * FROM workspace_invitations WHERE lower(email) = lower($1) ORDER BY created_at, id",
+		email)
+}
+
+func (r *Repo) GetInvitationByID(ctx context.Context, id int) (*entity.Invitation, error) {
+	var inv entity.Invitation
+
+	err := r.DB.QueryRowxContext(ctx, "SELECT * FROM workspace_invitations WHERE id = $1", id).StructScan(&inv)
+	if err != nil {
+		return nil, mapErr(err, ErrInvitationNotFound, nil)
+	}
+
+	return &inv, nil
+}
+
+func (r *Repo) CreateInvitation(ctx context.Context, inv entity.Invitation) (*entity.Invitation, error) {
+	var created entity.Invitation
+
+	err := r.DB.QueryRowxContext(ctx,
+		`INSERT INTO workspace_invitations (workspace_id, email, role, invited_by, created_at) 
+VALUES ($1, $2, $3, $4, $5) 
+RETURNING *`,
+		inv.WorkspaceID, inv.Email, inv.Role, inv.InvitedBy, inv.CreatedAt).StructScan(&created)
+	if err != nil {
+		return nil, mapErr(err, ErrWorkspaceNotFound, ErrInvitationExists)
+	}
+
+	return &created, nil
+}
+
+func (r *Repo) DeleteInvitation(ctx context.Context, id int) (*entity.Invitation, error) {
+	var deleted entity.Invitation
+
+	err := r.DB.QueryRowxContext(ctx, "DELETE FROM workspace_invitations WHERE id = $1 RETURNING *", id).StructScan(&deleted)
+	if err != nil {
+		return nil, mapErr(err, ErrInvitationNotFound, nil)
+	}
+
+	return &deleted, nil
+}
+
+// AcceptInvitation makes user member of workspace with invited role and deletes invitation
+func (r *Repo) AcceptInvitation(ctx context.Context, inv entity.Invitation, userID int, acceptedAt time.Time) (*entity.Membership, error) {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, "DELETE FROM workspace_invitations WHERE id = $1", inv.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+
+	// invitation was accepted or revoked concurrently
+	if affected == 0 {
+		return nil, ErrInvitationNotFound
+	}
+
+	var member entity.Membership
+
+	err = tx.QueryRowxContext(ctx,
+		`WITH m AS (
+    INSERT INTO workspace_members (workspace_id, user_id, role, created_at, updated_at) 
+    VALUES ($1, $2, $3, $4, $4) 
+    RETURNING *
+) 
+SELECT m.*, u.email FROM m JOIN users u ON u.id = m.user_id`,
+		inv.WorkspaceID, userID, inv.Role, acceptedAt).StructScan(&member)
+	if err != nil {
+		return nil, mapErr(err, ErrMemberNotFound, ErrMemberExists)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+func (r *Repo) selectInvitations(ctx context.Context, query string, args ...any) ([]*entity.Invitation, error) {
+	rows, err := r.DB.QueryxContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var invitations []*entity.Invitation
+
+	for rows.Next() {
+		var inv entity.Invitation
+
+		if err = rows.StructScan(&inv); err != nil {
+			return nil, err
+		}
+
+		invitations = append(invitations, &inv)
+	}
+
+	return invitations, rows.Err()
+}
+
+func mapErr(err error, notFound, exists error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return notFound
+	}
+
+	var pgErr *pgconn.PgError
+	if exists != nil && errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode {
+		return exists
+	}
+
+	return err
+}
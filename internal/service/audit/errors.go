@@ -0,0 +1,8 @@
+package audit
+
+import "errors"
+
+var (
+	ErrAdminRequired = errors.New("admin role required")
+	ErrAuditFailed   = errors.New("cannot record audit entry")
+)
@@ -0,0 +1,213 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"db-dashboards/internal/domain/entity"
+
+	enginerepo "db-dashboards/internal/repository/engine"
+)
+
+// auditedRepo records every call reaching target database, calls failing to be recorded fail too
+// so that no data is returned without trace
+type auditedRepo struct {
+	enginerepo.Repo
+
+	service *Service
+	conn    *entity.Connection
+}
+
+func (r *auditedRepo) GetDefaultSchema(ctx context.Context) (string, error) {
+	start := time.Now()
+
+	schema, err := r.Repo.GetDefaultSchema(ctx)
+
+	return schema, r.audit(ctx, entity.AuditOperationDefaultSchema, "", nil, 1, start, err)
+}
+
+func (r *auditedRepo) GetAllSchemas(ctx context.Context) ([]string, error) {
+	start := time.Now()
+
+	schemas, err := r.Repo.GetAllSchemas(ctx)
+
+	return schemas, r.audit(ctx, entity.AuditOperationSchemas, "", nil, len(schemas), start, err)
+}
+
+func (r *auditedRepo) GetAllTables(ctx context.Context, schema string) ([]*entity.Table, error) {
+	start := time.Now()
+
+	tables, err := r.Repo.GetAllTables(ctx, schema)
+
+	return tables, r.audit(ctx, entity.AuditOperationTables, schema, nil, len(tables), start, err)
+}
+
+func (r *auditedRepo) GetColumnsFromTable(ctx context.Context, schema, tableName string) ([]*entity.Column, error) {
+	start := time.Now()
+
+	columns, err := r.Repo.GetColumnsFromTable(ctx, schema, tableName)
+
+	return columns, r.audit(ctx, entity.AuditOperationColumns, qualify(schema, tableName), nil, len(columns), start, err)
+}
+
+func (r *auditedRepo) GetRows(ctx context.Context, q enginerepo.SelectQuery) (*entity.QueryResult, error) {
+	start := time.Now()
+
+	result, err := r.Repo.GetRows(ctx, q)
+
+	var rows int
+	if result != nil {
+		rows = len(result.Rows)
+	}
+
+	query, args := enginerepo.BuildSelect(r.Repo.Dialect(), q)
+
+	return result, r.audit(ctx, entity.AuditOperationRows, query, args, rows, start, err)
+}
+
+func (r *auditedRepo) StreamRows(ctx context.Context,
+	q enginerepo.SelectQuery,
+	maxRows int,
+	w enginerepo.RowWriter,
+) (*enginerepo.StreamResult, error) {
+	start := time.Now()
+
+	result, err := r.Repo.StreamRows(ctx, q, maxRows, w)
+
+	var rows int
+	if result != nil {
+		rows = result.Rows
+	}
+
+	query, args := enginerepo.BuildSelect(r.Repo.Dialect(), q)
+
+	return result, r.audit(ctx, entity.AuditOperationStream, query, args, rows, start, err)
+}
+
+func (r *auditedRepo) CountRows(ctx context.Context, q enginerepo.SelectQuery) (int64, error) {
+	start := time.Now()
+
+	count, err := r.Repo.CountRows(ctx, q)
+
+	query, args := enginerepo.BuildCount(r.Repo.Dialect(), q)
+
+	return count, r.audit(ctx, entity.AuditOperationCount, query, args, 1, start, err)
+}
+
+func (r *auditedRepo) EstimateRowCount(ctx context.Context, schema, tableName string) (int64, error) {
+	start := time.Now()
+
+	count, err := r.Repo.EstimateRowCount(ctx, schema, tableName)
+
+	return count, r.audit(ctx, entity.AuditOperationEstimate, qualify(schema, tableName), nil, 1, start, err)
+}
+
+func (r *auditedRepo) ExecuteQuery(ctx context.Context, q enginerepo.RawQuery) (*entity.QueryResult, error) {
+	start := time.Now()
+
+	result, err := r.Repo.ExecuteQuery(ctx, q)
+
+	var rows int
+	if result != nil {
+		rows = len(result.Rows)
+	}
+
+	return result, r.audit(ctx, entity.AuditOperationQuery, rawStatement(q), q.Args, rows, start, err)
+}
+
+func (r *auditedRepo) StreamQuery(ctx context.Context, q enginerepo.RawQuery, w enginerepo.RowWriter) (*enginerepo.StreamResult, error) {
+	start := time.Now()
+
+	result, err := r.Repo.StreamQuery(ctx, q, w)
+
+	var rows int
+	if result != nil {
+		rows = result.Rows
+	}
+
+	return result, r.audit(ctx, entity.AuditOperationQuery, rawStatement(q), q.Args, rows, start, err)
+}
+
+// ImportRows returns count of inserted rows along with ErrAuditFailed if import succeeded but could not be recorded,
+// rows are committed already so failure is left to caller to log
+func (r *auditedRepo) ImportRows(ctx context.Context, q enginerepo.ImportQuery) (int64, error) {
+	start := time.Now()
+
+	inserted, err := r.Repo.ImportRows(ctx, q)
+
+	op := entity.AuditOperationImport
+	if q.DryRun {
+		op = entity.AuditOperationImportDryRun
+	}
+
+	auditErr := r.audit(ctx, op, enginerepo.BuildInsert(r.Repo.Dialect(), q), nil, int(inserted), start, err)
+	if err != nil {
+		return 0, auditErr
+	}
+
+	return inserted, auditErr
+}
+
+// audit records call and returns callErr, or ErrAuditFailed if call succeeded but could not be recorded
+func (r *auditedRepo) audit(ctx context.Context,
+	op entity.AuditOperation,
+	statement string,
+	args []any,
+	rows int,
+	start time.Time,
+	callErr error,
+) error {
+	entry := entity.AuditEntry{
+		ConnectionID: r.conn.ID,
+		WorkspaceID:  r.conn.WorkspaceID,
+		Engine:       r.conn.Engine,
+		Operation:    op,
+		Statement:    statement,
+		Params:       redact(args),
+	}
+
+	if callErr != nil {
+		entry.Error = callErr.Error()
+		rows = 0
+	}
+
+	entry.RowsReturned = rows
+
+	if err := r.service.record(ctx, entry, start); err != nil {
+		return errors.Join(callErr, fmt.Errorf("%w: %v", ErrAuditFailed, err))
+	}
+
+	return callErr
+}
+
+// redact keeps only types of bound values
+func redact(args []any) entity.AuditParams {
+	params := make(entity.AuditParams, len(args))
+
+	for i, arg := range args {
+		if arg == nil {
+			params[i] = "null"
+			continue
+		}
+
+		params[i] = fmt.Sprintf("%T", arg)
+	}
+
+	return params
+}
+
+// rawStatement joins every statement of q in order they run
+func rawStatement(q enginerepo.RawQuery) string {
+	return strings.Join(append(append([]string{}, q.Preceding...), q.SQL), ";\n")
+}
+
+func qualify(schema, name string) string {
+	if schema == "" {
+		return name
+	}
+
+	return schema + "." + name
+}
@@ -0,0 +1,69 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/domain/identity"
+
+	enginerepo "db-dashboards/internal/repository/engine"
+)
+
+type Repo interface {
+	CreateEntry(ctx context.Context, entry entity.AuditEntry) error
+	GetEntries(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error)
+	DeleteEntriesBefore(ctx context.Context, t time.Time) (int64, error)
+}
+
+type Service struct {
+	Repo      Repo
+	Retention time.Duration // 0 keeps entries forever
+}
+
+func New(repo Repo, retention time.Duration) *Service {
+	return &Service{
+		Repo:      repo,
+		Retention: retention,
+	}
+}
+
+func (s *Service) GetEntries(ctx context.Context, filter entity.AuditFilter) ([]*entity.AuditEntry, error) {
+	if !identity.HasRole(ctx, entity.RoleAdmin) {
+		return nil, ErrAdminRequired
+	}
+
+	return s.Repo.GetEntries(ctx, filter)
+}
+
+// Cleanup deletes entries older than retention and returns their count
+func (s *Service) Cleanup(ctx context.Context) (int64, error) {
+	if s.Retention <= 0 {
+		return 0, nil
+	}
+
+	return s.Repo.DeleteEntriesBefore(ctx, time.Now().Add(-s.Retention))
+}
+
+// Wrap returns repository recording audit entry for every call to target database behind conn
+func (s *Service) Wrap(conn *entity.Connection, repo enginerepo.Repo) enginerepo.Repo {
+	return &auditedRepo{
+		Repo:    repo,
+		service: s,
+		conn:    conn,
+	}
+}
+
+// record stores entry of call started at start, user is taken from identity of call context.
+// Entry is recorded even if call context is cancelled
+func (s *Service) record(ctx context.Context, entry entity.AuditEntry, start time.Time) error {
+	if id, err := identity.FromContext(ctx); err == nil {
+		entry.UserID = &id.UserID
+		entry.UserEmail = id.Email
+	}
+
+	entry.DurationMs = time.Since(start).Milliseconds()
+	entry.CreatedAt = start
+
+	return s.Repo.CreateEntry(context.WithoutCancel(ctx), entry)
+}
@@ -0,0 +1,10 @@
+package auth
+
+import "errors"
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired = errors.New("refresh token expired")
+	ErrRefreshTokenReused  = errors.New("refresh token reused, session revoked")
+	ErrUserDisabled        = errors.New("user is disabled")
+)
@@ -2,26 +2,54 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"db-dashboards/internal/config"
 	"db-dashboards/internal/domain/entity"
+
+	refreshtokenrepo "db-dashboards/internal/repository/refreshtoken"
+	cryptoutils "db-dashboards/pkg/utils/crypto"
+	jwtutils "db-dashboards/pkg/utils/jwt"
+)
+
+const (
+	refreshTokenBytes = 32
+	idBytes           = 16
 )
 
 type UserRepo interface {
+	GetUserByID(ctx context.Context, id int) (*entity.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
 }
 
+type TokenRepo interface {
+	CreateRefreshToken(ctx context.Context, token entity.RefreshToken) (*entity.RefreshToken, error)
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*entity.RefreshToken, error)
+	RotateRefreshToken(ctx context.Context, oldID int, token entity.RefreshToken) (*entity.RefreshToken, error)
+	RevokeSession(ctx context.Context, sessionID string, revokedAt time.Time) error
+	IsSessionActive(ctx context.Context, sessionID string, now time.Time) (bool, error)
+}
+
 type Hasher interface {
 	CompareHashAndPassword(hashedPassword []byte, password []byte) error
 }
 
 type Service struct {
-	UserRepo UserRepo
-	Hasher   Hasher
+	UserRepo  UserRepo
+	TokenRepo TokenRepo
+	Hasher    Hasher
+	JwtConfig config.Jwt
 }
 
-func New(userRepo UserRepo, hasher Hasher) *Service {
+func New(userRepo UserRepo, tokenRepo TokenRepo, hasher Hasher, jwtConfig config.Jwt) *Service {
 	return &Service{
-		UserRepo: userRepo,
-		Hasher:   hasher,
+		UserRepo:  userRepo,
+		TokenRepo: tokenRepo,
+		Hasher:    hasher,
+		JwtConfig: jwtConfig,
 	}
 }
 
@@ -36,5 +64,157 @@ func (s *Service) Login(ctx context.Context, email, password string) (*entity.Us
 		return nil, err
 	}
 
+	if user.DisabledAt != nil {
+		return nil, ErrUserDisabled
+	}
+
 	return user, nil
 }
+
+// IssueTokens starts new session of user and returns its first token pair
+func (s *Service) IssueTokens(ctx context.Context, user *entity.User) (*entity.TokenPair, error) {
+	sessionID, err := cryptoutils.RandomToken(idBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := cryptoutils.RandomToken(refreshTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	stored, err := s.TokenRepo.CreateRefreshToken(ctx, entity.RefreshToken{
+		UserID:    user.ID,
+		SessionID: sessionID,
+		TokenHash: cryptoutils.HashToken(refreshToken),
+		ExpiresAt: now.Add(s.refreshTTL()),
+		CreatedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.tokenPair(user, stored, refreshToken, now)
+}
+
+// Refresh rotates refresh token, presenting already rotated token revokes the whole session as it may be stolen
+func (s *Service) Refresh(ctx context.Context, refreshToken string) (*entity.TokenPair, error) {
+	stored, err := s.getRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	if stored.RevokedAt != nil {
+		return nil, s.revokeReused(ctx, stored.SessionID, now)
+	}
+
+	if !now.Before(stored.ExpiresAt) {
+		return nil, ErrRefreshTokenExpired
+	}
+
+	user, err := s.UserRepo.GetUserByID(ctx, stored.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.DisabledAt != nil {
+		return nil, ErrUserDisabled
+	}
+
+	newToken, err := cryptoutils.RandomToken(refreshTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	rotated, err := s.TokenRepo.RotateRefreshToken(ctx, stored.ID, entity.RefreshToken{
+		UserID:    stored.UserID,
+		SessionID: stored.SessionID,
+		TokenHash: cryptoutils.HashToken(newToken),
+		ExpiresAt: now.Add(s.refreshTTL()),
+		CreatedAt: now,
+	})
+	if err != nil {
+		// token was rotated concurrently
+		if errors.Is(err, refreshtokenrepo.ErrRefreshTokenRevoked) {
+			return nil, s.revokeReused(ctx, stored.SessionID, now)
+		}
+
+		return nil, err
+	}
+
+	return s.tokenPair(user, rotated, newToken, now)
+}
+
+// Logout revokes session of refresh token, access tokens of the session are rejected from now on
+func (s *Service) Logout(ctx context.Context, refreshToken string) error {
+	stored, err := s.getRefreshToken(ctx, refreshToken)
+	if err != nil {
+		return err
+	}
+
+	return s.TokenRepo.RevokeSession(ctx, stored.SessionID, time.Now())
+}
+
+func (s *Service) IsSessionActive(ctx context.Context, sessionID string) (bool, error) {
+	return s.TokenRepo.IsSessionActive(ctx, sessionID, time.Now())
+}
+
+func (s *Service) getRefreshToken(ctx context.Context, refreshToken string) (*entity.RefreshToken, error) {
+	stored, err := s.TokenRepo.GetRefreshTokenByHash(ctx, cryptoutils.HashToken(refreshToken))
+	if err != nil {
+		if errors.Is(err, refreshtokenrepo.ErrRefreshTokenNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+
+		return nil, err
+	}
+
+	return stored, nil
+}
+
+func (s *Service) revokeReused(ctx context.Context, sessionID string, now time.Time) error {
+	if err := s.TokenRepo.RevokeSession(ctx, sessionID, now); err != nil {
+		return err
+	}
+
+	return ErrRefreshTokenReused
+}
+
+func (s *Service) tokenPair(user *entity.User, stored *entity.RefreshToken, refreshToken string, now time.Time) (*entity.TokenPair, error) {
+	jti, err := cryptoutils.RandomToken(idBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := now.Add(time.Duration(s.JwtConfig.AccessTTL) * time.Second)
+
+	payload := jwt.MapClaims{
+		"id":    user.ID,
+		"email": user.Email,
+		"role":  user.Role,
+		"sid":   stored.SessionID,
+		"jti":   jti,
+		"iat":   now.Unix(),
+		"exp":   expiresAt.Unix(),
+	}
+
+	accessToken, err := jwtutils.CreateJWT(payload, jwt.SigningMethodHS256, s.JwtConfig.Secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  expiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: stored.ExpiresAt,
+	}, nil
+}
+
+func (s *Service) refreshTTL() time.Duration {
+	return time.Duration(s.JwtConfig.RefreshTTL) * time.Second
+}
@@ -0,0 +1,12 @@
+package connection
+
+import "errors"
+
+var (
+	ErrInvalidDSN    = errors.New("invalid dsn")
+	ErrCannotConnect = errors.New("cannot connect to database")
+
+	ErrAccessDenied  = errors.New("insufficient access to connection")
+	ErrInvalidAccess = errors.New("invalid connection access level")
+	ErrGrantToOwner  = errors.New("connection owner already has full access")
+)
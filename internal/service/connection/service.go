@@ -0,0 +1,344 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/domain/identity"
+
+	connectionrepo "db-dashboards/internal/repository/connection"
+	enginerepo "db-dashboards/internal/repository/engine"
+)
+
+type Repo interface {
+	GetAllConnections(ctx context.Context, userID int, workspaceID *int, offset, limit int) ([]*entity.Connection, error)
+	GetConnectionByID(ctx context.Context, id int) (*entity.Connection, error)
+	CreateConnection(ctx context.Context, conn entity.Connection) (*entity.Connection, error)
+	UpdateConnection(ctx context.Context, conn entity.Connection) (*entity.Connection, error)
+	DeleteConnection(ctx context.Context, id int) (*entity.Connection, error)
+
+	GetConnectionPermissions(ctx context.Context, connectionID int) ([]*entity.ConnectionPermission, error)
+	GetConnectionPermission(ctx context.Context, connectionID, userID int) (*entity.ConnectionPermission, error)
+	SetConnectionPermission(ctx context.Context, perm entity.ConnectionPermission) (*entity.ConnectionPermission, error)
+	DeleteConnectionPermission(ctx context.Context, connectionID, userID int) (*entity.ConnectionPermission, error)
+}
+
+type Cipher interface {
+	Encrypt(plaintext []byte) (string, error)
+	Decrypt(ciphertext string) ([]byte, error)
+}
+
+type Pool interface {
+	Get(ctx context.Context, id int, driverName, dsn string) (*sqlx.DB, error)
+	Evict(id int) error
+}
+
+// Auditor records calls made through repository of target database
+type Auditor interface {
+	Wrap(conn *entity.Connection, repo enginerepo.Repo) enginerepo.Repo
+}
+
+type Service struct {
+	Repo     Repo
+	Cipher   Cipher
+	Registry *enginerepo.Registry
+	Pool     Pool
+	Auditor  Auditor
+}
+
+func New(repo Repo, cipher Cipher, registry *enginerepo.Registry, pool Pool, auditor Auditor) *Service {
+	return &Service{
+		Repo:     repo,
+		Cipher:   cipher,
+		Registry: registry,
+		Pool:     pool,
+		Auditor:  auditor,
+	}
+}
+
+// GetAllConnections returns connections of active workspace of request or personal connections of user
+func (s *Service) GetAllConnections(ctx context.Context, userID, offset, limit int) ([]*entity.Connection, error) {
+	conns, err := s.Repo.GetAllConnections(ctx, userID, identity.WorkspaceID(ctx), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	role := identity.Role(ctx)
+	ws, inWorkspace := identity.ActiveWorkspace(ctx)
+
+	for _, conn := range conns {
+		if role == entity.RoleAdmin || conn.UserID == userID {
+			conn.Access = entity.ConnectionAccessWrite
+		}
+
+		if inWorkspace && !conn.Access.Allows(ws.Role.ConnectionAccess()) {
+			conn.Access = ws.Role.ConnectionAccess()
+		}
+
+		conn.Access = conn.Access.Cap(role.MaxConnectionAccess())
+	}
+
+	return conns, nil
+}
+
+// GetConnectionByID returns connection owned by or shared with user, access of user is set to returned connection.
+// Connections out of workspace scope of request are not found
+func (s *Service) GetConnectionByID(ctx context.Context, userID, id int) (*entity.Connection, error) {
+	conn, err := s.Repo.GetConnectionByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := s.access(ctx, userID, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	// do not reveal existence of connections not shared with user
+	if !access.Valid() {
+		return nil, connectionrepo.ErrConnectionNotFound
+	}
+
+	conn.Access = access
+
+	return conn, nil
+}
+
+// Authorize returns connection if user has at least need access to it
+func (s *Service) Authorize(ctx context.Context, userID, id int, need entity.ConnectionAccess) (*entity.Connection, error) {
+	conn, err := s.GetConnectionByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !conn.Access.Allows(need) {
+		return nil, fmt.Errorf("%w: %v access required", ErrAccessDenied, need)
+	}
+
+	return conn, nil
+}
+
+func (s *Service) GetConnectionDSN(ctx context.Context, userID, id int, need entity.ConnectionAccess) (*entity.Connection, string, error) {
+	conn, err := s.Authorize(ctx, userID, id, need)
+	if err != nil {
+		return nil, "", err
+	}
+
+	dsn, err := s.Cipher.Decrypt(conn.EncryptedDSN)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return conn, string(dsn), nil
+}
+
+// OpenConnection returns repository of target database behind saved connection if user has at least need access to it,
+// pool of database is reused and every call through returned repository is audited
+func (s *Service) OpenConnection(ctx context.Context, userID, id int, need entity.ConnectionAccess) (*entity.Connection, enginerepo.Repo, error) {
+	conn, dsn, err := s.GetConnectionDSN(ctx, userID, id, need)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	driver, err := s.Registry.Get(conn.Engine)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dsn, err = driver.ResolveDSN(dsn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidDSN, err)
+	}
+
+	db, err := s.Pool.Get(ctx, conn.ID, driver.DriverName(), dsn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrCannotConnect, err)
+	}
+
+	return conn, s.Auditor.Wrap(conn, driver.NewRepo(db)), nil
+}
+
+func (s *Service) CreateConnection(ctx context.Context, conn entity.Connection) (*entity.Connection, error) {
+	if !identity.HasRole(ctx, entity.RoleAdmin, entity.RoleEditor) {
+		return nil, fmt.Errorf("%w: viewers can not create connections", ErrAccessDenied)
+	}
+
+	if ws, ok := identity.ActiveWorkspace(ctx); ok && !ws.Role.Allows(entity.WorkspaceRoleEditor) {
+		return nil, fmt.Errorf("%w: workspace viewers can not create connections", ErrAccessDenied)
+	}
+
+	// connection model sent with plain dsn
+	encrypted, err := s.Cipher.Encrypt([]byte(conn.EncryptedDSN))
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	conn.EncryptedDSN = encrypted
+	conn.WorkspaceID = identity.WorkspaceID(ctx)
+	conn.CreatedAt = now
+	conn.UpdatedAt = now
+
+	created, err := s.Repo.CreateConnection(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	created.Access = entity.ConnectionAccessWrite
+
+	return created, nil
+}
+
+func (s *Service) UpdateConnection(ctx context.Context, userID int, conn entity.Connection) (*entity.Connection, error) {
+	existing, err := s.getManagedConnection(ctx, userID, conn.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if conn.Name != "" {
+		existing.Name = conn.Name
+	}
+
+	previous := *existing
+
+	// connection model sent with plain dsn, empty dsn keeps the stored one
+	if conn.EncryptedDSN != "" {
+		encrypted, err := s.Cipher.Encrypt([]byte(conn.EncryptedDSN))
+		if err != nil {
+			return nil, err
+		}
+
+		existing.EncryptedDSN = encrypted
+	}
+
+	existing.UpdatedAt = time.Now()
+
+	updated, err := s.Repo.UpdateConnection(ctx, *existing)
+	if err != nil {
+		return nil, err
+	}
+
+	if conn.EncryptedDSN != "" {
+		s.evictPool(&previous)
+	}
+
+	updated.Access = existing.Access
+
+	return updated, nil
+}
+
+func (s *Service) DeleteConnection(ctx context.Context, userID, id int) (*entity.Connection, error) {
+	existing, err := s.getManagedConnection(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := s.Repo.DeleteConnection(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	s.evictPool(existing)
+
+	return deleted, nil
+}
+
+func (s *Service) GetConnectionPermissions(ctx context.Context, userID, id int) ([]*entity.ConnectionPermission, error) {
+	if _, err := s.getManagedConnection(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.GetConnectionPermissions(ctx, id)
+}
+
+// SetConnectionPermission shares connection with another user or changes access already granted
+func (s *Service) SetConnectionPermission(ctx context.Context, userID int, perm entity.ConnectionPermission) (*entity.ConnectionPermission, error) {
+	conn, err := s.getManagedConnection(ctx, userID, perm.ConnectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !perm.Access.Valid() {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAccess, perm.Access)
+	}
+
+	if perm.UserID == conn.UserID {
+		return nil, ErrGrantToOwner
+	}
+
+	now := time.Now()
+
+	perm.CreatedAt = now
+	perm.UpdatedAt = now
+
+	return s.Repo.SetConnectionPermission(ctx, perm)
+}
+
+func (s *Service) RevokeConnectionPermission(ctx context.Context, userID, id, granteeID int) (*entity.ConnectionPermission, error) {
+	if _, err := s.getManagedConnection(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.DeleteConnectionPermission(ctx, id, granteeID)
+}
+
+// access returns level of user on connection capped by role of authenticated user, admins act as owners.
+// In workspace the higher of workspace role and permission of user applies
+func (s *Service) access(ctx context.Context, userID int, conn *entity.Connection) (entity.ConnectionAccess, error) {
+	if !identity.InScope(ctx, conn.WorkspaceID) {
+		return entity.ConnectionAccessNone, nil
+	}
+
+	role := identity.Role(ctx)
+
+	if role == entity.RoleAdmin || conn.UserID == userID {
+		return entity.ConnectionAccessWrite.Cap(role.MaxConnectionAccess()), nil
+	}
+
+	access := entity.ConnectionAccessNone
+
+	if ws, ok := identity.ActiveWorkspace(ctx); ok {
+		access = ws.Role.ConnectionAccess()
+	}
+
+	perm, err := s.Repo.GetConnectionPermission(ctx, conn.ID, userID)
+	if err != nil && !errors.Is(err, connectionrepo.ErrPermissionNotFound) {
+		return entity.ConnectionAccessNone, err
+	}
+
+	if err == nil && !access.Allows(perm.Access) {
+		access = perm.Access
+	}
+
+	return access.Cap(role.MaxConnectionAccess()), nil
+}
+
+// getManagedConnection returns connection if user may change and share it,
+// which only admins, owners of connection or its workspace not demoted to viewers do
+func (s *Service) getManagedConnection(ctx context.Context, userID, id int) (*entity.Connection, error) {
+	conn, err := s.GetConnectionByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	isOwner := (conn.UserID == userID || identity.HasWorkspaceRole(ctx, entity.WorkspaceRoleOwner)) &&
+		identity.HasRole(ctx, entity.RoleEditor)
+
+	if !isOwner && !identity.HasRole(ctx, entity.RoleAdmin) {
+		return nil, fmt.Errorf("%w: only owner manages connection", ErrAccessDenied)
+	}
+
+	return conn, nil
+}
+
+// evictPool closes cached pool of conn, so that replaced or deleted credentials are not used any longer
+func (s *Service) evictPool(conn *entity.Connection) {
+	// pool is forgotten even if closing it fails
+	_ = s.Pool.Evict(conn.ID)
+}
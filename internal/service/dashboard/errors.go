@@ -0,0 +1,17 @@
+package dashboard
+
+import "errors"
+
+var (
+	ErrAccessDenied  = errors.New("insufficient access to dashboard")
+	ErrInvalidAccess = errors.New("invalid dashboard access level")
+	ErrGrantToOwner  = errors.New("dashboard owner already has full access")
+
+	ErrInvalidWidgetBinding = errors.New("widget must be bound either to saved query or to connection with sql")
+	ErrInvalidWidgetOrder   = errors.New("widget order must list every widget of dashboard exactly once")
+
+	ErrWidgetQueryDetached         = errors.New("widget is not bound to any query")
+	ErrUnknownWidgetColumn         = errors.New("unknown column in widget options")
+	ErrNoValueColumns              = errors.New("query result has no numeric columns for widget values")
+	ErrTimeBucketRequiresTimestamp = errors.New("time bucket requires timestamp x column")
+)
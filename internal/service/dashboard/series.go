@@ -0,0 +1,387 @@
+package dashboard
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"db-dashboards/internal/domain/entity"
+
+	enginerepo "db-dashboards/internal/repository/engine"
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+}
+
+type accumulator struct {
+	sum, min, max float64
+	count         int
+}
+
+func (a *accumulator) add(v float64) {
+	if a.count == 0 || v < a.min {
+		a.min = v
+	}
+
+	if a.count == 0 || v > a.max {
+		a.max = v
+	}
+
+	a.sum += v
+	a.count++
+}
+
+func (a *accumulator) result(aggregate entity.Aggregate) *float64 {
+	if aggregate == entity.AggregateCount {
+		v := float64(a.count)
+		return &v
+	}
+
+	if a.count == 0 {
+		return nil
+	}
+
+	var v float64
+
+	switch aggregate {
+	case entity.AggregateAvg:
+		v = a.sum / float64(a.count)
+
+	case entity.AggregateMin:
+		v = a.min
+
+	case entity.AggregateMax:
+		v = a.max
+
+	default:
+		v = a.sum
+	}
+
+	return &v
+}
+
+type seriesBuilder struct {
+	name   string
+	xs     []any
+	accums []*accumulator
+	index  map[string]int
+}
+
+func (b *seriesBuilder) add(x any, y *float64) {
+	key := fmt.Sprint(x)
+
+	i, ok := b.index[key]
+	if !ok {
+		i = len(b.xs)
+
+		b.index[key] = i
+		b.xs = append(b.xs, x)
+		b.accums = append(b.accums, &accumulator{})
+	}
+
+	if y != nil {
+		b.accums[i].add(*y)
+	}
+}
+
+// shapeWidgetData turns query result into series according to widget options,
+// unset options are picked by column kinds
+func shapeWidgetData(widget *entity.Widget, result *entity.QueryResult) (*entity.WidgetData, error) {
+	data := entity.WidgetData{
+		Type:      widget.Type,
+		Columns:   result.Columns,
+		Truncated: result.Truncated,
+	}
+
+	if widget.Type == entity.WidgetTypeTable {
+		data.Rows = result.Rows
+		return &data, nil
+	}
+
+	names := sliceutils.Map(result.Columns, func(c entity.QueryColumn) string { return c.Name })
+	kinds := columnKinds(result)
+
+	opts := widget.Options
+
+	for _, column := range append([]string{opts.XColumn, opts.GroupBy}, opts.YColumns...) {
+		if column != "" && !sliceutils.Contains(names, column) {
+			return nil, fmt.Errorf("%w: %v", ErrUnknownWidgetColumn, column)
+		}
+	}
+
+	xColumn := opts.XColumn
+	if xColumn == "" && widget.Type != entity.WidgetTypeSingleStat {
+		xColumn = defaultXColumn(names, kinds, opts.GroupBy)
+	}
+
+	yColumns := opts.YColumns
+	if len(yColumns) == 0 {
+		for i, name := range names {
+			if kinds[i] == enginerepo.KindNumeric && name != xColumn && name != opts.GroupBy {
+				yColumns = append(yColumns, name)
+			}
+		}
+	}
+
+	if len(yColumns) == 0 {
+		return nil, ErrNoValueColumns
+	}
+
+	xIdx := indexOf(names, xColumn)
+	groupIdx := indexOf(names, opts.GroupBy)
+	yIdx := sliceutils.Map(yColumns, func(name string) int { return indexOf(names, name) })
+
+	if opts.TimeBucket != entity.TimeBucketNone && (xIdx < 0 || kinds[xIdx] != enginerepo.KindTemporal) {
+		return nil, ErrTimeBucketRequiresTimestamp
+	}
+
+	data.XColumn = xColumn
+
+	if widget.Type == entity.WidgetTypeSingleStat {
+		data.Series = singleStat(result, yColumns[0], yIdx[0])
+		return &data, nil
+	}
+
+	var (
+		builders []*seriesBuilder
+		byName   = make(map[string]*seriesBuilder)
+	)
+
+	for _, row := range result.Rows {
+		var x any
+		if xIdx >= 0 {
+			x = row[xIdx]
+		}
+
+		if opts.TimeBucket != entity.TimeBucketNone {
+			t, ok := toTime(x)
+			if !ok {
+				continue
+			}
+
+			x = truncateTime(t, opts.TimeBucket)
+		}
+
+		for i, idx := range yIdx {
+			name := yColumns[i]
+
+			if groupIdx >= 0 {
+				group := "null"
+				if row[groupIdx] != nil {
+					group = fmt.Sprint(row[groupIdx])
+				}
+
+				name = group
+				if len(yColumns) > 1 {
+					name = fmt.Sprintf("%v: %v", group, yColumns[i])
+				}
+			}
+
+			builder, ok := byName[name]
+			if !ok {
+				builder = &seriesBuilder{name: name, index: make(map[string]int)}
+
+				byName[name] = builder
+				builders = append(builders, builder)
+			}
+
+			var y *float64
+			if v, ok := toFloat(row[idx]); ok {
+				y = &v
+			}
+
+			builder.add(x, y)
+		}
+	}
+
+	data.Series = sliceutils.Map(builders, func(b *seriesBuilder) entity.ChartSeries {
+		points := make([]entity.ChartPoint, len(b.xs))
+
+		for i := range b.xs {
+			points[i] = entity.ChartPoint{X: b.xs[i], Y: b.accums[i].result(opts.Aggregate)}
+		}
+
+		if opts.TimeBucket != entity.TimeBucketNone {
+			sort.SliceStable(points, func(i, j int) bool {
+				return points[i].X.(time.Time).Before(points[j].X.(time.Time))
+			})
+		}
+
+		return entity.ChartSeries{Name: b.name, Points: points}
+	})
+
+	return &data, nil
+}
+
+func singleStat(result *entity.QueryResult, name string, idx int) []entity.ChartSeries {
+	point := entity.ChartPoint{}
+
+	if len(result.Rows) > 0 {
+		if v, ok := toFloat(result.Rows[0][idx]); ok {
+			point.Y = &v
+		}
+	}
+
+	return []entity.ChartSeries{{Name: name, Points: []entity.ChartPoint{point}}}
+}
+
+// defaultXColumn prefers temporal column, then first non numeric one
+func defaultXColumn(names []string, kinds []enginerepo.TypeKind, groupBy string) string {
+	for i, name := range names {
+		if kinds[i] == enginerepo.KindTemporal && name != groupBy {
+			return name
+		}
+	}
+
+	for i, name := range names {
+		if kinds[i] != enginerepo.KindNumeric && name != groupBy {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// columnKinds classifies columns by database types, untyped columns (e.g. sqlite expressions) are classified by values
+func columnKinds(result *entity.QueryResult) []enginerepo.TypeKind {
+	kinds := make([]enginerepo.TypeKind, len(result.Columns))
+
+	for i, column := range result.Columns {
+		kinds[i] = enginerepo.KindOf(column.Type)
+
+		if kinds[i] == enginerepo.KindOther && column.Type == "" {
+			kinds[i] = kindOfValues(result.Rows, i)
+		}
+	}
+
+	return kinds
+}
+
+func kindOfValues(rows [][]any, idx int) enginerepo.TypeKind {
+	kind := enginerepo.KindOther
+
+	for _, row := range rows {
+		var valueKind enginerepo.TypeKind
+
+		switch row[idx].(type) {
+		case nil:
+			continue
+
+		case int64, int32, int, float64, float32:
+			valueKind = enginerepo.KindNumeric
+
+		case time.Time:
+			valueKind = enginerepo.KindTemporal
+
+		case bool:
+			valueKind = enginerepo.KindBoolean
+
+		default:
+			return enginerepo.KindOther
+		}
+
+		if kind != enginerepo.KindOther && kind != valueKind {
+			return enginerepo.KindOther
+		}
+
+		kind = valueKind
+	}
+
+	return kind
+}
+
+func toFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+
+	case int32:
+		return float64(v), true
+
+	case int:
+		return float64(v), true
+
+	case float64:
+		return v, !math.IsNaN(v)
+
+	case float32:
+		return float64(v), true
+
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+
+	case []byte:
+		f, err := strconv.ParseFloat(string(v), 64)
+		return f, err == nil
+
+	default:
+		return 0, false
+	}
+}
+
+func toTime(v any) (time.Time, bool) {
+	switch v := v.(type) {
+	case time.Time:
+		return v, true
+
+	case string:
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+func truncateTime(t time.Time, bucket entity.TimeBucket) time.Time {
+	year, month, day := t.Date()
+
+	switch bucket {
+	case entity.TimeBucketMinute:
+		return t.Truncate(time.Minute)
+
+	case entity.TimeBucketHour:
+		return time.Date(year, month, day, t.Hour(), 0, 0, 0, t.Location())
+
+	case entity.TimeBucketDay:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+
+	case entity.TimeBucketWeek:
+		// weeks start on monday
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+
+	case entity.TimeBucketMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+
+	case entity.TimeBucketYear:
+		return time.Date(year, 1, 1, 0, 0, 0, 0, t.Location())
+
+	default:
+		return t
+	}
+}
+
+func indexOf(names []string, name string) int {
+	if name == "" {
+		return -1
+	}
+
+	for i, n := range names {
+		if n == name {
+			return i
+		}
+	}
+
+	return -1
+}
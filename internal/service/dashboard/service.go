@@ -0,0 +1,437 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/domain/identity"
+
+	dashboardrepo "db-dashboards/internal/repository/dashboard"
+	enginerepo "db-dashboards/internal/repository/engine"
+	historyservice "db-dashboards/internal/service/history"
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+type Repo interface {
+	GetAllDashboards(ctx context.Context, userID int, workspaceID *int, offset, limit int) ([]*entity.Dashboard, error)
+	GetDashboardByID(ctx context.Context, id int) (*entity.Dashboard, error)
+	CreateDashboard(ctx context.Context, dashboard entity.Dashboard) (*entity.Dashboard, error)
+	UpdateDashboard(ctx context.Context, dashboard entity.Dashboard) (*entity.Dashboard, error)
+	DeleteDashboard(ctx context.Context, id int) (*entity.Dashboard, error)
+
+	GetWidgets(ctx context.Context, dashboardID int) ([]*entity.Widget, error)
+	GetWidgetByID(ctx context.Context, dashboardID, id int) (*entity.Widget, error)
+	CreateWidget(ctx context.Context, widget entity.Widget) (*entity.Widget, error)
+	UpdateWidget(ctx context.Context, widget entity.Widget) (*entity.Widget, error)
+	DeleteWidget(ctx context.Context, dashboardID, id int) (*entity.Widget, error)
+	ReorderWidgets(ctx context.Context, dashboardID int, ids []int, updatedAt time.Time) error
+
+	GetDashboardPermissions(ctx context.Context, dashboardID int) ([]*entity.DashboardPermission, error)
+	GetDashboardPermission(ctx context.Context, dashboardID, userID int) (*entity.DashboardPermission, error)
+	SetDashboardPermission(ctx context.Context, perm entity.DashboardPermission) (*entity.DashboardPermission, error)
+	DeleteDashboardPermission(ctx context.Context, dashboardID, userID int) (*entity.DashboardPermission, error)
+}
+
+type ConnectionService interface {
+	Authorize(ctx context.Context, userID, id int, need entity.ConnectionAccess) (*entity.Connection, error)
+	OpenConnection(ctx context.Context, userID, id int, need entity.ConnectionAccess) (*entity.Connection, enginerepo.Repo, error)
+}
+
+type SavedQueryService interface {
+	GetSavedQueryByID(ctx context.Context, userID, id int) (*entity.SavedQuery, error)
+	ExecuteSavedQuery(ctx context.Context, userID, id int, values map[string]any) (*entity.QueryResult, error)
+}
+
+// HistoryService runs queries and records them to history of user
+type HistoryService interface {
+	Execute(ctx context.Context,
+		userID int,
+		conn *entity.Connection,
+		repo enginerepo.Repo,
+		query string,
+		params []any,
+		allowWrite bool,
+	) (*entity.QueryResult, error)
+}
+
+type Service struct {
+	Repo              Repo
+	ConnectionService ConnectionService
+	SavedQueryService SavedQueryService
+	HistoryService    HistoryService
+}
+
+func New(repo Repo,
+	connectionService ConnectionService,
+	savedQueryService SavedQueryService,
+	historyService HistoryService,
+) *Service {
+	return &Service{
+		Repo:              repo,
+		ConnectionService: connectionService,
+		SavedQueryService: savedQueryService,
+		HistoryService:    historyService,
+	}
+}
+
+// GetAllDashboards returns dashboards of active workspace of request or personal dashboards of user
+func (s *Service) GetAllDashboards(ctx context.Context, userID, offset, limit int) ([]*entity.Dashboard, error) {
+	dashboards, err := s.Repo.GetAllDashboards(ctx, userID, identity.WorkspaceID(ctx), offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	role := identity.Role(ctx)
+	ws, inWorkspace := identity.ActiveWorkspace(ctx)
+
+	for _, dashboard := range dashboards {
+		if role == entity.RoleAdmin || dashboard.UserID == userID {
+			dashboard.Access = entity.DashboardAccessOwn
+		}
+
+		if inWorkspace && !dashboard.Access.Allows(ws.Role.DashboardAccess()) {
+			dashboard.Access = ws.Role.DashboardAccess()
+		}
+
+		dashboard.Access = dashboard.Access.Cap(role.MaxDashboardAccess())
+	}
+
+	return dashboards, nil
+}
+
+// GetDashboardByID returns dashboard owned by or shared with user, access of user is set to returned dashboard.
+// Dashboards out of workspace scope of request are not found
+func (s *Service) GetDashboardByID(ctx context.Context, userID, id int) (*entity.Dashboard, error) {
+	dashboard, err := s.Repo.GetDashboardByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := s.access(ctx, userID, dashboard)
+	if err != nil {
+		return nil, err
+	}
+
+	// do not reveal existence of dashboards not shared with user
+	if !access.Valid() {
+		return nil, dashboardrepo.ErrDashboardNotFound
+	}
+
+	dashboard.Access = access
+
+	return dashboard, nil
+}
+
+func (s *Service) CreateDashboard(ctx context.Context, dashboard entity.Dashboard) (*entity.Dashboard, error) {
+	if !identity.HasRole(ctx, entity.RoleAdmin, entity.RoleEditor) {
+		return nil, fmt.Errorf("%w: viewers can not create dashboards", ErrAccessDenied)
+	}
+
+	if ws, ok := identity.ActiveWorkspace(ctx); ok && !ws.Role.Allows(entity.WorkspaceRoleEditor) {
+		return nil, fmt.Errorf("%w: workspace viewers can not create dashboards", ErrAccessDenied)
+	}
+
+	now := time.Now()
+
+	dashboard.WorkspaceID = identity.WorkspaceID(ctx)
+	dashboard.CreatedAt = now
+	dashboard.UpdatedAt = now
+
+	created, err := s.Repo.CreateDashboard(ctx, dashboard)
+	if err != nil {
+		return nil, err
+	}
+
+	created.Access = entity.DashboardAccessOwn
+
+	return created, nil
+}
+
+func (s *Service) UpdateDashboard(ctx context.Context, userID int, dashboard entity.Dashboard) (*entity.Dashboard, error) {
+	existing, err := s.authorize(ctx, userID, dashboard.ID, entity.DashboardAccessEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	if dashboard.Title != "" {
+		existing.Title = dashboard.Title
+	}
+
+	if dashboard.Description != "" {
+		existing.Description = dashboard.Description
+	}
+
+	existing.UpdatedAt = time.Now()
+
+	updated, err := s.Repo.UpdateDashboard(ctx, *existing)
+	if err != nil {
+		return nil, err
+	}
+
+	updated.Access = existing.Access
+
+	return updated, nil
+}
+
+func (s *Service) DeleteDashboard(ctx context.Context, userID, id int) (*entity.Dashboard, error) {
+	if _, err := s.authorize(ctx, userID, id, entity.DashboardAccessOwn); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.DeleteDashboard(ctx, id)
+}
+
+func (s *Service) GetDashboardPermissions(ctx context.Context, userID, id int) ([]*entity.DashboardPermission, error) {
+	if _, err := s.authorize(ctx, userID, id, entity.DashboardAccessOwn); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.GetDashboardPermissions(ctx, id)
+}
+
+// SetDashboardPermission shares dashboard with another user or changes access already granted
+func (s *Service) SetDashboardPermission(ctx context.Context, userID int, perm entity.DashboardPermission) (*entity.DashboardPermission, error) {
+	dashboard, err := s.authorize(ctx, userID, perm.DashboardID, entity.DashboardAccessOwn)
+	if err != nil {
+		return nil, err
+	}
+
+	if !perm.Access.Valid() {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAccess, perm.Access)
+	}
+
+	if perm.UserID == dashboard.UserID {
+		return nil, ErrGrantToOwner
+	}
+
+	now := time.Now()
+
+	perm.CreatedAt = now
+	perm.UpdatedAt = now
+
+	return s.Repo.SetDashboardPermission(ctx, perm)
+}
+
+func (s *Service) RevokeDashboardPermission(ctx context.Context, userID, id, granteeID int) (*entity.DashboardPermission, error) {
+	if _, err := s.authorize(ctx, userID, id, entity.DashboardAccessOwn); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.DeleteDashboardPermission(ctx, id, granteeID)
+}
+
+func (s *Service) GetWidgets(ctx context.Context, userID, dashboardID int) ([]*entity.Widget, error) {
+	if _, err := s.GetDashboardByID(ctx, userID, dashboardID); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.GetWidgets(ctx, dashboardID)
+}
+
+func (s *Service) GetWidgetByID(ctx context.Context, userID, dashboardID, id int) (*entity.Widget, error) {
+	if _, err := s.GetDashboardByID(ctx, userID, dashboardID); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.GetWidgetByID(ctx, dashboardID, id)
+}
+
+func (s *Service) CreateWidget(ctx context.Context, userID int, widget entity.Widget) (*entity.Widget, error) {
+	dashboard, err := s.authorize(ctx, userID, widget.DashboardID, entity.DashboardAccessEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.validateBinding(ctx, userID, dashboard, widget); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	widget.CreatedAt = now
+	widget.UpdatedAt = now
+
+	return s.Repo.CreateWidget(ctx, widget)
+}
+
+// UpdateWidget replaces widget definition, position is changed only by ReorderWidgets
+func (s *Service) UpdateWidget(ctx context.Context, userID int, widget entity.Widget) (*entity.Widget, error) {
+	dashboard, err := s.authorize(ctx, userID, widget.DashboardID, entity.DashboardAccessEdit)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = s.Repo.GetWidgetByID(ctx, widget.DashboardID, widget.ID); err != nil {
+		return nil, err
+	}
+
+	if err = s.validateBinding(ctx, userID, dashboard, widget); err != nil {
+		return nil, err
+	}
+
+	widget.UpdatedAt = time.Now()
+
+	return s.Repo.UpdateWidget(ctx, widget)
+}
+
+func (s *Service) DeleteWidget(ctx context.Context, userID, dashboardID, id int) (*entity.Widget, error) {
+	if _, err := s.authorize(ctx, userID, dashboardID, entity.DashboardAccessEdit); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.DeleteWidget(ctx, dashboardID, id)
+}
+
+// ReorderWidgets sets widget positions by order of ids, ids must contain every widget of dashboard
+func (s *Service) ReorderWidgets(ctx context.Context, userID, dashboardID int, ids []int) ([]*entity.Widget, error) {
+	if _, err := s.authorize(ctx, userID, dashboardID, entity.DashboardAccessEdit); err != nil {
+		return nil, err
+	}
+
+	widgets, err := s.Repo.GetWidgets(ctx, dashboardID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ids) != len(widgets) || len(sliceutils.Unique(ids)) != len(ids) {
+		return nil, ErrInvalidWidgetOrder
+	}
+
+	if err = s.Repo.ReorderWidgets(ctx, dashboardID, ids, time.Now()); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.GetWidgets(ctx, dashboardID)
+}
+
+// GetWidgetData executes widget query and shapes result for widget type,
+// saved queries are executed with default param values. Queries run on behalf of dashboard owner,
+// so that users the dashboard is shared with need no access to its connections, and are recorded to history of owner.
+// Data is returned along with historyservice.ErrNotRecorded when only recording failed
+func (s *Service) GetWidgetData(ctx context.Context, userID, dashboardID, id int) (*entity.WidgetData, error) {
+	dashboard, err := s.GetDashboardByID(ctx, userID, dashboardID)
+	if err != nil {
+		return nil, err
+	}
+
+	widget, err := s.Repo.GetWidgetByID(ctx, dashboardID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *entity.QueryResult
+
+	switch {
+	case widget.SavedQueryID != nil:
+		result, err = s.SavedQueryService.ExecuteSavedQuery(ctx, dashboard.UserID, *widget.SavedQueryID, nil)
+
+	case widget.ConnectionID != nil:
+		var (
+			conn *entity.Connection
+			repo enginerepo.Repo
+		)
+
+		conn, repo, err = s.ConnectionService.OpenConnection(ctx, dashboard.UserID, *widget.ConnectionID, entity.ConnectionAccessQuery)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err = s.HistoryService.Execute(ctx, dashboard.UserID, conn, repo, widget.SQL, nil, false)
+
+	// bound query or connection was deleted
+	default:
+		return nil, ErrWidgetQueryDetached
+	}
+
+	if err != nil && !errors.Is(err, historyservice.ErrNotRecorded) {
+		return nil, err
+	}
+
+	data, shapeErr := shapeWidgetData(widget, result)
+	if shapeErr != nil {
+		return nil, shapeErr
+	}
+
+	return data, err
+}
+
+// access returns level of user on dashboard capped by role of authenticated user, admins act as owners.
+// In workspace the higher of workspace role and permission of user applies
+func (s *Service) access(ctx context.Context, userID int, dashboard *entity.Dashboard) (entity.DashboardAccess, error) {
+	if !identity.InScope(ctx, dashboard.WorkspaceID) {
+		return entity.DashboardAccessNone, nil
+	}
+
+	role := identity.Role(ctx)
+
+	if role == entity.RoleAdmin || dashboard.UserID == userID {
+		return entity.DashboardAccessOwn.Cap(role.MaxDashboardAccess()), nil
+	}
+
+	access := entity.DashboardAccessNone
+
+	if ws, ok := identity.ActiveWorkspace(ctx); ok {
+		access = ws.Role.DashboardAccess()
+	}
+
+	perm, err := s.Repo.GetDashboardPermission(ctx, dashboard.ID, userID)
+	if err != nil && !errors.Is(err, dashboardrepo.ErrPermissionNotFound) {
+		return entity.DashboardAccessNone, err
+	}
+
+	if err == nil && !access.Allows(perm.Access) {
+		access = perm.Access
+	}
+
+	return access.Cap(role.MaxDashboardAccess()), nil
+}
+
+// authorize returns dashboard if user has at least need access to it
+func (s *Service) authorize(ctx context.Context, userID, id int, need entity.DashboardAccess) (*entity.Dashboard, error) {
+	dashboard, err := s.GetDashboardByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !dashboard.Access.Allows(need) {
+		return nil, fmt.Errorf("%w: %v access required", ErrAccessDenied, need)
+	}
+
+	return dashboard, nil
+}
+
+// validateBinding ensures widget query is reachable by dashboard owner who widget data is fetched for,
+// editors other than owner may bind only saved queries they can read and sql only to connections they can query themselves
+func (s *Service) validateBinding(ctx context.Context, userID int, dashboard *entity.Dashboard, widget entity.Widget) error {
+	switch {
+	case widget.SavedQueryID != nil && widget.ConnectionID == nil && widget.SQL == "":
+		if _, err := s.SavedQueryService.GetSavedQueryByID(ctx, userID, *widget.SavedQueryID); err != nil {
+			return err
+		}
+
+		if userID == dashboard.UserID {
+			return nil
+		}
+
+		_, err := s.SavedQueryService.GetSavedQueryByID(ctx, dashboard.UserID, *widget.SavedQueryID)
+		return err
+
+	case widget.SavedQueryID == nil && widget.ConnectionID != nil && widget.SQL != "":
+		if _, err := s.ConnectionService.Authorize(ctx, dashboard.UserID, *widget.ConnectionID, entity.ConnectionAccessQuery); err != nil {
+			return err
+		}
+
+		if userID == dashboard.UserID {
+			return nil
+		}
+
+		_, err := s.ConnectionService.Authorize(ctx, userID, *widget.ConnectionID, entity.ConnectionAccessQuery)
+		return err
+
+	default:
+		return ErrInvalidWidgetBinding
+	}
+}
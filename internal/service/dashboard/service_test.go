@@ -0,0 +1,184 @@
+package dashboard
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/domain/identity"
+
+	enginerepo "db-dashboards/internal/repository/engine"
+	savedqueryrepo "db-dashboards/internal/repository/savedquery"
+	historyservice "db-dashboards/internal/service/history"
+)
+
+// fakeSavedQueries lets users read saved queries listed for them
+type fakeSavedQueries struct {
+	SavedQueryService
+
+	readable map[int][]int
+}
+
+func (f fakeSavedQueries) GetSavedQueryByID(_ context.Context, userID, id int) (*entity.SavedQuery, error) {
+	for _, readable := range f.readable[userID] {
+		if readable == id {
+			return &entity.SavedQuery{ID: id}, nil
+		}
+	}
+
+	return nil, savedqueryrepo.ErrSavedQueryNotFound
+}
+
+func TestValidateBindingSavedQuery(t *testing.T) {
+	const (
+		owner  = 1
+		editor = 2
+
+		ownerPrivate  = 10
+		editorPrivate = 20
+		shared        = 30
+	)
+
+	s := &Service{
+		SavedQueryService: fakeSavedQueries{readable: map[int][]int{
+			owner:  {ownerPrivate, shared},
+			editor: {editorPrivate, shared},
+		}},
+	}
+
+	dashboard := &entity.Dashboard{ID: 1, UserID: owner}
+
+	tests := []struct {
+		name    string
+		userID  int
+		queryID int
+		wantErr error
+	}{
+		{"owner binds own query", owner, ownerPrivate, nil},
+		{"owner binds shared query", owner, shared, nil},
+		{"editor binds shared query", editor, shared, nil},
+		{"editor binds private query of owner", editor, ownerPrivate, savedqueryrepo.ErrSavedQueryNotFound},
+		{"editor binds query owner can not run", editor, editorPrivate, savedqueryrepo.ErrSavedQueryNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queryID := tt.queryID
+			widget := entity.Widget{DashboardID: dashboard.ID, SavedQueryID: &queryID}
+
+			if err := s.validateBinding(context.Background(), tt.userID, dashboard, widget); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// fakeDashboards holds single dashboard with single sql widget shared with viewer
+type fakeDashboards struct {
+	Repo
+
+	dashboard *entity.Dashboard
+	widget    *entity.Widget
+	viewer    int
+}
+
+func (f fakeDashboards) GetDashboardByID(_ context.Context, _ int) (*entity.Dashboard, error) {
+	dashboard := *f.dashboard
+	return &dashboard, nil
+}
+
+func (f fakeDashboards) GetWidgetByID(_ context.Context, _, _ int) (*entity.Widget, error) {
+	return f.widget, nil
+}
+
+func (f fakeDashboards) GetDashboardPermission(_ context.Context, dashboardID, userID int) (*entity.DashboardPermission, error) {
+	if userID != f.viewer {
+		return nil, errors.New("permission not found")
+	}
+
+	return &entity.DashboardPermission{DashboardID: dashboardID, UserID: userID, Access: entity.DashboardAccessView}, nil
+}
+
+type fakeConnections struct {
+	ConnectionService
+}
+
+func (fakeConnections) OpenConnection(_ context.Context, _, id int, _ entity.ConnectionAccess) (*entity.Connection, enginerepo.Repo, error) {
+	return &entity.Connection{ID: id}, nil, nil
+}
+
+// fakeHistory records user queries are executed for and fails recording with err
+type fakeHistory struct {
+	userIDs []int
+	err     error
+}
+
+func (f *fakeHistory) Execute(_ context.Context,
+	userID int,
+	_ *entity.Connection,
+	_ enginerepo.Repo,
+	_ string,
+	_ []any,
+	allowWrite bool,
+) (*entity.QueryResult, error) {
+	if allowWrite {
+		return nil, errors.New("widget query allowed to write")
+	}
+
+	f.userIDs = append(f.userIDs, userID)
+
+	return &entity.QueryResult{
+		Columns: []entity.QueryColumn{{Name: "n"}},
+		Rows:    [][]any{{int64(1)}},
+	}, f.err
+}
+
+func TestGetWidgetDataRecordsHistoryOfOwner(t *testing.T) {
+	const (
+		owner  = 1
+		viewer = 2
+	)
+
+	connectionID := 5
+
+	tests := []struct {
+		name      string
+		recordErr error
+		wantErr   error
+	}{
+		{"recorded", nil, nil},
+		{"recording failed", historyservice.ErrNotRecorded, historyservice.ErrNotRecorded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := &fakeHistory{err: tt.recordErr}
+
+			s := &Service{
+				Repo: fakeDashboards{
+					dashboard: &entity.Dashboard{ID: 1, UserID: owner},
+					widget:    &entity.Widget{ID: 1, DashboardID: 1, Type: entity.WidgetTypeTable, ConnectionID: &connectionID, SQL: "SELECT 1 AS n"},
+					viewer:    viewer,
+				},
+				ConnectionService: fakeConnections{},
+				HistoryService:    history,
+			}
+
+			ctx := identity.WithIdentity(context.Background(), identity.Identity{UserID: viewer, Role: entity.RoleViewer})
+
+			data, err := s.GetWidgetData(ctx, viewer, 1, 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			if data == nil || len(data.Rows) != 1 {
+				t.Fatalf("data = %+v, want single row", data)
+			}
+
+			if len(history.userIDs) != 1 || history.userIDs[0] != owner {
+				t.Errorf("recorded for users %v, want [%v]", history.userIDs, owner)
+			}
+		})
+	}
+}
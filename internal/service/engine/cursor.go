@@ -0,0 +1,139 @@
+package engine
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"db-dashboards/internal/domain/entity"
+)
+
+// cursor is bound to sort it was produced for, values are kept as strings and converted by target database
+type cursor struct {
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+}
+
+// keysetSort appends primary key columns missing from sort, so that sort orders rows uniquely and
+// no row is skipped between pages when sort values repeat. Keyset pagination is only possible when rows
+// are sorted, table has primary key and no sort column is nullable, as nulls can not be compared
+func keysetSort(sort []entity.Sort, columns []*entity.Column) ([]entity.Sort, bool) {
+	if len(sort) == 0 {
+		return sort, false
+	}
+
+	sorted := make(map[string]bool, len(sort))
+
+	for _, s := range sort {
+		sorted[s.Column] = true
+	}
+
+	var (
+		pk          []entity.Sort
+		hasPK       bool
+		hasNullable bool
+	)
+
+	for _, column := range columns {
+		switch {
+		case column.PrimaryKey:
+			hasPK = true
+
+			if !sorted[column.Name] {
+				pk = append(pk, entity.Sort{Column: column.Name})
+			}
+
+		case column.Nullable && sorted[column.Name]:
+			hasNullable = true
+		}
+	}
+
+	return append(slices.Clip(sort), pk...), hasPK && !hasNullable
+}
+
+func sortKey(sort []entity.Sort) string {
+	parts := make([]string, len(sort))
+
+	for i, s := range sort {
+		if s.Desc {
+			parts[i] = "-" + s.Column
+		} else {
+			parts[i] = s.Column
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// encodeCursor returns cursor pointing after row, false if any of sort values is null or missing from columns
+func encodeCursor(sort []entity.Sort, columns []entity.QueryColumn, row []any) (string, bool) {
+	c := cursor{
+		Sort:   sortKey(sort),
+		Values: make([]string, len(sort)),
+	}
+
+	for i, s := range sort {
+		idx := slices.IndexFunc(columns, func(column entity.QueryColumn) bool { return column.Name == s.Column })
+		if idx < 0 {
+			return "", false
+		}
+
+		val, ok := cursorValue(row[idx])
+		if !ok {
+			return "", false
+		}
+
+		c.Values[i] = val
+	}
+
+	bytes, err := json.Marshal(c)
+	if err != nil {
+		return "", false
+	}
+
+	return base64.RawURLEncoding.EncodeToString(bytes), true
+}
+
+func decodeCursor(encoded string, sort []entity.Sort) ([]any, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c cursor
+
+	if err = json.Unmarshal(bytes, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if c.Sort != sortKey(sort) || len(c.Values) != len(sort) {
+		return nil, ErrInvalidCursor
+	}
+
+	values := make([]any, len(c.Values))
+
+	for i, val := range c.Values {
+		values[i] = val
+	}
+
+	return values, nil
+}
+
+func cursorValue(val any) (string, bool) {
+	switch v := val.(type) {
+	case nil:
+		return "", false
+
+	case time.Time:
+		return v.Format(time.RFC3339Nano), true
+
+	case []byte:
+		return string(v), true
+
+	default:
+		return fmt.Sprint(v), true
+	}
+}
@@ -0,0 +1,27 @@
+package engine
+
+import "errors"
+
+var (
+	ErrInvalidCursor       = errors.New("invalid cursor")
+	ErrCursorRequiresSort  = errors.New("cursor pagination requires sort")
+	ErrCursorWithOffset    = errors.New("cursor can not be combined with offset")
+	ErrCursorUnsupported   = errors.New("cursor pagination requires table with primary key and sort by not null columns")
+	ErrInvalidFilterValues = errors.New("invalid number of filter values")
+
+	ErrEmptyQuery         = errors.New("query is empty")
+	ErrMultipleStatements = errors.New("multiple statements require write access")
+
+	ErrEmptyAggregate            = errors.New("aggregate requires at least one dimension, measure or time grain")
+	ErrMeasureRequiresColumn     = errors.New("measure requires column")
+	ErrMeasureRequiresNumeric    = errors.New("measure requires numeric column")
+	ErrTimeGrainRequiresTemporal = errors.New("time grain requires date or timestamp column")
+	ErrDuplicateOutputColumn     = errors.New("duplicate output column name")
+	ErrUnknownOrderColumn        = errors.New("order column is not a dimension or measure")
+
+	ErrUnsupportedImportFormat = errors.New("unsupported import format")
+	ErrInvalidImportFile       = errors.New("invalid import file")
+	ErrImportTooLarge          = errors.New("import file exceeds size limit")
+	ErrTooManyImportRows       = errors.New("import file exceeds row limit")
+	ErrDuplicateImportColumn   = errors.New("table column is bound to several file columns")
+)
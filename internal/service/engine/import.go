@@ -0,0 +1,568 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"db-dashboards/internal/domain/entity"
+
+	enginerepo "db-dashboards/internal/repository/engine"
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+// validation errors reported in result, further invalid rows are only counted
+const maxImportErrors = 100
+
+// layouts of temporal values accepted in import files
+var importTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	time.DateOnly,
+}
+
+// importRecord is file row keyed by file column, csv values are strings, values of ndjson are decoded
+// with numbers kept as json.Number and NULL is nil
+type importRecord map[string]any
+
+// ImportRows validates file rows against columns of table and inserts them in one transaction.
+// Nothing is inserted if any row is invalid, validation errors are returned in result.
+// If rows were inserted but import could not be audited, result is returned along with the error
+func (s *Service) ImportRows(ctx context.Context, repo enginerepo.Repo, imp entity.Import, file io.Reader) (*entity.ImportResult, error) {
+	schema, err := s.resolveSchema(ctx, repo, imp.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := enginerepo.LookupTable(ctx, repo, schema, imp.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = enginerepo.ValidateColumns(columns, mappingTargets(imp.Mapping)...); err != nil {
+		return nil, err
+	}
+
+	if s.MaxImportBytes > 0 {
+		file = &sizeLimitedReader{r: file, left: s.MaxImportBytes}
+	}
+
+	header, records, err := s.readImportFile(imp, file)
+	if err != nil {
+		return nil, err
+	}
+
+	binding, err := bindImportColumns(imp, columns, header, records)
+	if err != nil {
+		return nil, err
+	}
+
+	// inserted columns keep table order
+	var targets []*entity.Column
+
+	for _, column := range columns {
+		if slices.Contains(binding.targets(), column) {
+			targets = append(targets, column)
+		}
+	}
+
+	result := entity.ImportResult{
+		Rows:   len(records),
+		DryRun: imp.DryRun,
+	}
+
+	rows := make([][]any, 0, len(records))
+
+	for i, record := range records {
+		row, rowErrs := coerceImportRecord(record, binding, targets)
+
+		if len(rowErrs) > 0 {
+			result.InvalidRows++
+
+			for _, rowErr := range rowErrs {
+				if len(result.Errors) < maxImportErrors {
+					rowErr.Row = i + 1
+					result.Errors = append(result.Errors, rowErr)
+				}
+			}
+
+			continue
+		}
+
+		rows = append(rows, row)
+	}
+
+	if result.InvalidRows > 0 || len(rows) == 0 || len(targets) == 0 {
+		return &result, nil
+	}
+
+	if s.QueryTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, s.QueryTimeout)
+		defer cancel()
+	}
+
+	result.Inserted, err = repo.ImportRows(ctx, enginerepo.ImportQuery{
+		Schema:  schema,
+		Table:   imp.Table,
+		Columns: sliceutils.Map(targets, func(c *entity.Column) string { return c.Name }),
+		Rows:    rows,
+		DryRun:  imp.DryRun,
+	})
+	if err != nil && result.Inserted == 0 {
+		return nil, err
+	}
+
+	// rows are inserted if only recording of import failed, result is returned along with the error
+	return &result, err
+}
+
+// readImportFile returns csv header, it is nil for ndjson, and file rows
+func (s *Service) readImportFile(imp entity.Import, file io.Reader) ([]string, []importRecord, error) {
+	switch imp.Format {
+	case entity.ImportFormatCSV:
+		return s.readCSV(imp, file)
+
+	case entity.ImportFormatNDJSON:
+		records, err := s.readNDJSON(file)
+		return nil, records, err
+
+	default:
+		return nil, nil, fmt.Errorf("%w: %v", ErrUnsupportedImportFormat, imp.Format)
+	}
+}
+
+func (s *Service) readCSV(imp entity.Import, file io.Reader) ([]string, []importRecord, error) {
+	reader := csv.NewReader(file)
+
+	if imp.Delimiter != 0 {
+		reader.Comma = imp.Delimiter
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil, fmt.Errorf("%w: no header", ErrInvalidImportFile)
+		}
+
+		return nil, nil, importReadErr(err)
+	}
+
+	// byte order mark of spreadsheet exports
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	for i, name := range header {
+		if slices.Contains(header[:i], name) {
+			return nil, nil, fmt.Errorf("%w: duplicate column %v", ErrInvalidImportFile, name)
+		}
+	}
+
+	var records []importRecord
+
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return header, records, nil
+		}
+
+		if err != nil {
+			return nil, nil, importReadErr(err)
+		}
+
+		if s.MaxImportRows > 0 && len(records) == s.MaxImportRows {
+			return nil, nil, fmt.Errorf("%w: %v", ErrTooManyImportRows, s.MaxImportRows)
+		}
+
+		record := make(importRecord, len(fields))
+
+		for i, field := range fields {
+			if field == imp.Null {
+				record[header[i]] = nil
+			} else {
+				record[header[i]] = field
+			}
+		}
+
+		records = append(records, record)
+	}
+}
+
+func (s *Service) readNDJSON(file io.Reader) ([]importRecord, error) {
+	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
+
+	var records []importRecord
+
+	for {
+		var record importRecord
+
+		err := decoder.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+
+		if err != nil {
+			if errors.Is(err, ErrImportTooLarge) {
+				return nil, err
+			}
+
+			return nil, fmt.Errorf("%w: line %v: %v", ErrInvalidImportFile, len(records)+1, err)
+		}
+
+		if s.MaxImportRows > 0 && len(records) == s.MaxImportRows {
+			return nil, fmt.Errorf("%w: %v", ErrTooManyImportRows, s.MaxImportRows)
+		}
+
+		if record == nil {
+			return nil, fmt.Errorf("%w: line %v: null is not an object", ErrInvalidImportFile, len(records)+1)
+		}
+
+		records = append(records, record)
+	}
+}
+
+func importReadErr(err error) error {
+	if errors.Is(err, ErrImportTooLarge) {
+		return err
+	}
+
+	return fmt.Errorf("%w: %v", ErrInvalidImportFile, err)
+}
+
+// importBinding maps file columns to table columns
+type importBinding map[string]*entity.Column
+
+func (b importBinding) targets() []*entity.Column {
+	targets := make([]*entity.Column, 0, len(b))
+
+	for _, column := range b {
+		targets = append(targets, column)
+	}
+
+	return targets
+}
+
+// bindImportColumns binds file columns, those of csv header or keys of ndjson rows, to table columns.
+// Explicit mapping binds only mapped file columns, otherwise every file column has to match table column
+// by name, exact match is preferred over case insensitive one
+func bindImportColumns(imp entity.Import,
+	columns []*entity.Column,
+	header []string,
+	records []importRecord,
+) (importBinding, error) {
+	fileColumns := header
+
+	if imp.Format == entity.ImportFormatNDJSON {
+		for _, record := range records {
+			for key := range record {
+				if !slices.Contains(fileColumns, key) {
+					fileColumns = append(fileColumns, key)
+				}
+			}
+		}
+
+		slices.Sort(fileColumns)
+	}
+
+	binding := make(importBinding, len(fileColumns))
+
+	for _, fileColumn := range fileColumns {
+		var (
+			column *entity.Column
+			found  bool
+		)
+
+		if imp.Mapping != nil {
+			target, mapped := imp.Mapping[fileColumn]
+			if !mapped {
+				continue
+			}
+
+			column, found = findColumn(columns, target, false)
+		} else {
+			if column, found = findColumn(columns, fileColumn, false); !found {
+				column, found = findColumn(columns, fileColumn, true)
+			}
+		}
+
+		if !found {
+			return nil, fmt.Errorf("%w: file column %v", enginerepo.ErrUnknownColumn, fileColumn)
+		}
+
+		for other, bound := range binding {
+			if bound == column {
+				return nil, fmt.Errorf("%w: %v from %v and %v", ErrDuplicateImportColumn, column.Name, other, fileColumn)
+			}
+		}
+
+		binding[fileColumn] = column
+	}
+
+	return binding, nil
+}
+
+func findColumn(columns []*entity.Column, name string, foldCase bool) (*entity.Column, bool) {
+	for _, column := range columns {
+		if column.Name == name || foldCase && strings.EqualFold(column.Name, name) {
+			return column, true
+		}
+	}
+
+	return nil, false
+}
+
+func mappingTargets(mapping map[string]string) []string {
+	targets := make([]string, 0, len(mapping))
+
+	for _, target := range mapping {
+		targets = append(targets, target)
+	}
+
+	return targets
+}
+
+// coerceImportRecord returns values of targets in their order, targets missing from record are NULL
+func coerceImportRecord(record importRecord, binding importBinding, targets []*entity.Column) ([]any, []entity.ImportError) {
+	row := make([]any, len(targets))
+
+	var errs []entity.ImportError
+
+	for fileColumn, val := range record {
+		column, bound := binding[fileColumn]
+		if !bound {
+			continue
+		}
+
+		coerced, err := coerceImportValue(column, val)
+		if err != nil {
+			errs = append(errs, entity.ImportError{Column: column.Name, Message: err.Error()})
+			continue
+		}
+
+		row[slices.Index(targets, column)] = coerced
+	}
+
+	// stable order of errors within row
+	slices.SortFunc(errs, func(a, b entity.ImportError) int { return strings.Compare(a.Column, b.Column) })
+
+	return row, errs
+}
+
+// coerceImportValue converts file value to value bound for column of type, temporal values are normalized to text
+// understood by every engine and decimals are kept as text to keep their precision
+func coerceImportValue(column *entity.Column, val any) (any, error) {
+	if val == nil {
+		return nil, nil
+	}
+
+	typeName := strings.ToLower(column.Type)
+	baseType := enginerepo.BaseType(typeName)
+
+	switch {
+	// postgres arrays
+	case strings.HasPrefix(typeName, "_"):
+		if items, ok := val.([]any); ok {
+			return arrayLiteral(items), nil
+		}
+
+		return importText(val), nil
+
+	case baseType == "json" || baseType == "jsonb":
+		if text, ok := val.(string); ok {
+			if !json.Valid([]byte(text)) {
+				return nil, errors.New("invalid json")
+			}
+
+			return text, nil
+		}
+
+		encoded, err := json.Marshal(val)
+		if err != nil {
+			return nil, err
+		}
+
+		return string(encoded), nil
+
+	case baseType == "uuid":
+		text := importText(val)
+
+		if digits := strings.ReplaceAll(strings.Trim(text, "{}"), "-", ""); len(digits) != 32 || !isHex(digits) {
+			return nil, fmt.Errorf("invalid uuid %q", text)
+		}
+
+		return text, nil
+
+	case baseType == "bytea" || strings.HasSuffix(baseType, "blob") || strings.HasSuffix(baseType, "binary"):
+		text := importText(val)
+
+		if encoded, ok := strings.CutPrefix(text, `\x`); ok {
+			decoded, err := hex.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("invalid hex literal: %v", err)
+			}
+
+			return decoded, nil
+		}
+
+		return []byte(text), nil
+	}
+
+	switch enginerepo.KindOf(typeName) {
+	case enginerepo.KindNumeric:
+		if b, ok := val.(bool); ok {
+			if b {
+				return int64(1), nil
+			}
+
+			return int64(0), nil
+		}
+
+		text := strings.TrimSpace(importText(val))
+
+		if enginerepo.IsInteger(typeName) {
+			n, err := strconv.ParseInt(text, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid integer %q", text)
+			}
+
+			return n, nil
+		}
+
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", text)
+		}
+
+		if baseType == "numeric" || baseType == "decimal" {
+			return text, nil
+		}
+
+		return f, nil
+
+	case enginerepo.KindBoolean:
+		if b, ok := val.(bool); ok {
+			return b, nil
+		}
+
+		text := strings.TrimSpace(importText(val))
+
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boolean %q", text)
+		}
+
+		return b, nil
+
+	case enginerepo.KindTemporal:
+		text := strings.TrimSpace(importText(val))
+
+		for _, layout := range importTimeLayouts {
+			t, err := time.Parse(layout, text)
+			if err != nil {
+				continue
+			}
+
+			switch baseType {
+			case "date":
+				return t.Format(time.DateOnly), nil
+
+			case "timestamptz":
+				return t.Format("2006-01-02 15:04:05.999999999Z07:00"), nil
+
+			default:
+				// zone is dropped like by columns without time zone
+				return t.Format("2006-01-02 15:04:05.999999999"), nil
+			}
+		}
+
+		return nil, fmt.Errorf("invalid date or time %q", text)
+	}
+
+	return importText(val), nil
+}
+
+// importText renders file value as text, structured ndjson values as json
+func importText(val any) string {
+	switch v := val.(type) {
+	case string:
+		return v
+
+	case json.Number:
+		return v.String()
+
+	case bool:
+		return strconv.FormatBool(v)
+
+	default:
+		encoded, _ := json.Marshal(v)
+		return string(encoded)
+	}
+}
+
+// arrayLiteral renders json array as postgres array literal, e.g. {"a",NULL,{"1","2"}}
+func arrayLiteral(items []any) string {
+	var b bytes.Buffer
+
+	b.WriteByte('{')
+
+	for i, item := range items {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+
+		switch v := item.(type) {
+		case nil:
+			b.WriteString("NULL")
+
+		case []any:
+			b.WriteString(arrayLiteral(v))
+
+		default:
+			b.WriteByte('"')
+			b.WriteString(strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(importText(v)))
+			b.WriteByte('"')
+		}
+	}
+
+	b.WriteByte('}')
+
+	return b.String()
+}
+
+func isHex(s string) bool {
+	_, err := hex.DecodeString(s)
+	return err == nil
+}
+
+// sizeLimitedReader fails reading beyond limit instead of silently truncating file
+type sizeLimitedReader struct {
+	r    io.Reader
+	left int64
+}
+
+func (r *sizeLimitedReader) Read(p []byte) (int, error) {
+	// one byte beyond limit tells whether file is too large
+	if int64(len(p)) > r.left+1 {
+		p = p[:r.left+1]
+	}
+
+	n, err := r.r.Read(p)
+
+	if r.left -= int64(n); r.left < 0 {
+		return n, ErrImportTooLarge
+	}
+
+	return n, err
+}
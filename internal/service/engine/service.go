@@ -0,0 +1,395 @@
+package engine
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/domain/identity"
+
+	enginerepo "db-dashboards/internal/repository/engine"
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+type Service struct {
+	QueryTimeout   time.Duration
+	MaxRows        int
+	MaxStreamRows  int
+	MaxImportRows  int
+	MaxImportBytes int64
+}
+
+func New(queryTimeout time.Duration, maxRows, maxStreamRows, maxImportRows int, maxImportBytes int64) *Service {
+	return &Service{
+		QueryTimeout:   queryTimeout,
+		MaxRows:        maxRows,
+		MaxStreamRows:  maxStreamRows,
+		MaxImportRows:  maxImportRows,
+		MaxImportBytes: maxImportBytes,
+	}
+}
+
+func (s *Service) GetAllSchemas(ctx context.Context, repo enginerepo.Repo) ([]string, error) {
+	return repo.GetAllSchemas(ctx)
+}
+
+func (s *Service) GetAllTables(ctx context.Context, repo enginerepo.Repo, schema string) ([]*entity.Table, error) {
+	schema, err := s.resolveSchema(ctx, repo, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return repo.GetAllTables(ctx, schema)
+}
+
+func (s *Service) GetColumnsFromTable(ctx context.Context, repo enginerepo.Repo, schema, tableName string) ([]*entity.Column, error) {
+	schema, err := s.resolveSchema(ctx, repo, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return enginerepo.LookupTable(ctx, repo, schema, tableName)
+}
+
+func (s *Service) GetRowsFromTable(ctx context.Context, repo enginerepo.Repo, q entity.RowsQuery) (*entity.RowsPage, error) {
+	schema, err := s.resolveSchema(ctx, repo, q.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := enginerepo.LookupTable(ctx, repo, schema, q.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validateRowsQuery(q, columns); err != nil {
+		return nil, err
+	}
+
+	sort, keyset := keysetSort(q.Sort, columns)
+
+	selectQuery := enginerepo.SelectQuery{
+		Schema:  schema,
+		Table:   q.Table,
+		Filters: q.Filters,
+		Sort:    sort,
+		Limit:   q.Limit,
+		Offset:  q.Offset,
+	}
+
+	if q.Cursor != "" {
+		if !keyset {
+			return nil, ErrCursorUnsupported
+		}
+
+		if selectQuery.After, err = decodeCursor(q.Cursor, sort); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err := repo.GetRows(ctx, selectQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	page := entity.RowsPage{
+		Columns: result.Columns,
+		Rows:    result.Rows,
+	}
+
+	if rows := result.Rows; keyset && len(rows) == q.Limit && len(rows) > 0 {
+		if next, ok := encodeCursor(sort, result.Columns, rows[len(rows)-1]); ok {
+			page.NextCursor = next
+		}
+	}
+
+	switch q.Count {
+	case entity.CountModeExact:
+		total, err := repo.CountRows(ctx, selectQuery)
+		if err != nil {
+			return nil, err
+		}
+
+		page.Total = &total
+
+	case entity.CountModeEstimated:
+		var total int64
+
+		// statistics know nothing about filters
+		if len(q.Filters) > 0 {
+			total, err = repo.CountRows(ctx, selectQuery)
+		} else {
+			total, err = repo.EstimateRowCount(ctx, schema, q.Table)
+			page.TotalEstimated = true
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		page.Total = &total
+	}
+
+	return &page, nil
+}
+
+// StreamRowsFromTable writes filtered and sorted rows of table to w as they are read, rows above
+// server stream limit are not written. Paging by cursor or offset is not supported
+func (s *Service) StreamRowsFromTable(ctx context.Context,
+	repo enginerepo.Repo,
+	q entity.RowsQuery,
+	w enginerepo.RowWriter,
+) (*enginerepo.StreamResult, error) {
+	schema, err := s.resolveSchema(ctx, repo, q.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := enginerepo.LookupTable(ctx, repo, schema, q.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validateRowsQuery(q, columns); err != nil {
+		return nil, err
+	}
+
+	return repo.StreamRows(ctx, enginerepo.SelectQuery{
+		Schema:  schema,
+		Table:   q.Table,
+		Filters: q.Filters,
+		Sort:    q.Sort,
+		Limit:   q.Limit,
+	}, s.MaxStreamRows, w)
+}
+
+// ExecuteQuery runs single statement in read only transaction. When write is allowed statements run
+// in one read write transaction and result of the last one is returned, params are bound to the last statement.
+// Write is allowed only to editors and admins, viewers stay read only even with write access to connection
+func (s *Service) ExecuteQuery(ctx context.Context, repo enginerepo.Repo, query string, params []any, allowWrite bool) (*entity.QueryResult, error) {
+	q, err := s.rawQuery(ctx, repo, query, params, allowWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	q.MaxRows = s.MaxRows
+
+	return repo.ExecuteQuery(ctx, q)
+}
+
+// StreamQuery runs query like ExecuteQuery but writes result rows to w as they are read up to server stream limit
+func (s *Service) StreamQuery(ctx context.Context,
+	repo enginerepo.Repo,
+	query string,
+	params []any,
+	allowWrite bool,
+	w enginerepo.RowWriter,
+) (*enginerepo.StreamResult, error) {
+	q, err := s.rawQuery(ctx, repo, query, params, allowWrite)
+	if err != nil {
+		return nil, err
+	}
+
+	q.MaxRows = s.MaxStreamRows
+
+	return repo.StreamQuery(ctx, q, w)
+}
+
+func (s *Service) rawQuery(ctx context.Context, repo enginerepo.Repo, query string, params []any, allowWrite bool) (enginerepo.RawQuery, error) {
+	// write access may be inherited from workspace role or grant, role of user still has to allow writes
+	allowWrite = allowWrite && identity.HasRole(ctx, entity.RoleAdmin, entity.RoleEditor)
+
+	statements := enginerepo.SplitStatements(query, repo.Dialect().LexOptions())
+
+	switch {
+	case len(statements) == 0:
+		return enginerepo.RawQuery{}, ErrEmptyQuery
+
+	case len(statements) > 1 && !allowWrite:
+		return enginerepo.RawQuery{}, ErrMultipleStatements
+	}
+
+	last := len(statements) - 1
+
+	return enginerepo.RawQuery{
+		Preceding: statements[:last],
+		SQL:       statements[last],
+		Args:      params,
+		ReadOnly:  !allowWrite,
+		Timeout:   s.QueryTimeout,
+	}, nil
+}
+
+// ExecuteAggregate validates aggregate query against table columns, compiles it for repo dialect and runs it read only
+func (s *Service) ExecuteAggregate(ctx context.Context, repo enginerepo.Repo, q entity.AggregateQuery) (*entity.AggregateResult, error) {
+	schema, err := s.resolveSchema(ctx, repo, q.Schema)
+	if err != nil {
+		return nil, err
+	}
+
+	columns, err := enginerepo.LookupTable(ctx, repo, schema, q.Table)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validateAggregateQuery(q, columns); err != nil {
+		return nil, err
+	}
+
+	q.Schema = schema
+
+	// time series are expected in chronological order
+	if len(q.Order) == 0 && q.TimeGrain != nil {
+		q.Order = []entity.Sort{{Column: q.TimeGrain.Column}}
+	}
+
+	if q.Limit <= 0 || q.Limit > s.MaxRows {
+		q.Limit = s.MaxRows
+	}
+
+	query, args := enginerepo.BuildAggregate(repo.Dialect(), q)
+
+	result, err := repo.ExecuteQuery(ctx, enginerepo.RawQuery{
+		SQL:      query,
+		Args:     args,
+		ReadOnly: true,
+		Timeout:  s.QueryTimeout,
+		MaxRows:  s.MaxRows,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.AggregateResult{
+		SQL:    query,
+		Args:   args,
+		Result: result,
+	}, nil
+}
+
+// resolveSchema falls back to default schema of connection when none requested
+func (s *Service) resolveSchema(ctx context.Context, repo enginerepo.Repo, schema string) (string, error) {
+	if schema != "" {
+		return schema, nil
+	}
+
+	return repo.GetDefaultSchema(ctx)
+}
+
+func validateRowsQuery(q entity.RowsQuery, columns []*entity.Column) error {
+	names := sliceutils.Map(q.Sort, func(s entity.Sort) string { return s.Column })
+	names = append(names, sliceutils.Map(q.Filters, func(f entity.Filter) string { return f.Column })...)
+
+	if err := enginerepo.ValidateColumns(columns, names...); err != nil {
+		return err
+	}
+
+	if err := validateFilters(q.Filters); err != nil {
+		return err
+	}
+
+	if q.Cursor != "" {
+		if len(q.Sort) == 0 {
+			return ErrCursorRequiresSort
+		}
+
+		if q.Offset != 0 {
+			return ErrCursorWithOffset
+		}
+	}
+
+	return nil
+}
+
+func validateFilters(filters []entity.Filter) error {
+	for _, filter := range filters {
+		switch filter.Op {
+		case entity.FilterOpIsNull, entity.FilterOpNotNull:
+			if len(filter.Values) != 0 {
+				return fmt.Errorf("%w: %v takes no value", ErrInvalidFilterValues, filter.Op)
+			}
+
+		case entity.FilterOpIn:
+			if len(filter.Values) == 0 {
+				return fmt.Errorf("%w: %v takes at least one value", ErrInvalidFilterValues, filter.Op)
+			}
+
+		default:
+			if len(filter.Values) != 1 {
+				return fmt.Errorf("%w: %v takes exactly one value", ErrInvalidFilterValues, filter.Op)
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateAggregateQuery(q entity.AggregateQuery, columns []*entity.Column) error {
+	if len(q.Dimensions) == 0 && len(q.Measures) == 0 && q.TimeGrain == nil {
+		return ErrEmptyAggregate
+	}
+
+	names := append([]string{}, q.Dimensions...)
+	names = append(names, sliceutils.Map(q.Filters, func(f entity.Filter) string { return f.Column })...)
+
+	if err := enginerepo.ValidateColumns(columns, names...); err != nil {
+		return err
+	}
+
+	if err := validateFilters(q.Filters); err != nil {
+		return err
+	}
+
+	kinds := make(map[string]enginerepo.TypeKind, len(columns))
+
+	for _, column := range columns {
+		kinds[column.Name] = enginerepo.KindOf(column.Type)
+	}
+
+	var outputs []string
+
+	if q.TimeGrain != nil {
+		if err := enginerepo.ValidateColumns(columns, q.TimeGrain.Column); err != nil {
+			return err
+		}
+
+		if kinds[q.TimeGrain.Column] != enginerepo.KindTemporal {
+			return fmt.Errorf("%w: %v", ErrTimeGrainRequiresTemporal, q.TimeGrain.Column)
+		}
+
+		outputs = append(outputs, q.TimeGrain.Column)
+	}
+
+	outputs = append(outputs, q.Dimensions...)
+
+	for _, m := range q.Measures {
+		switch {
+		case m.Column == "" && m.Func != entity.MeasureCount:
+			return fmt.Errorf("%w: %v", ErrMeasureRequiresColumn, m.Func)
+
+		case m.Column != "":
+			if err := enginerepo.ValidateColumns(columns, m.Column); err != nil {
+				return err
+			}
+		}
+
+		if (m.Func == entity.MeasureSum || m.Func == entity.MeasureAvg) && kinds[m.Column] != enginerepo.KindNumeric {
+			return fmt.Errorf("%w: %v(%v)", ErrMeasureRequiresNumeric, m.Func, m.Column)
+		}
+
+		outputs = append(outputs, enginerepo.MeasureName(m))
+	}
+
+	if len(sliceutils.Unique(outputs)) != len(outputs) {
+		return ErrDuplicateOutputColumn
+	}
+
+	for _, sort := range q.Order {
+		if !sliceutils.Contains(outputs, sort.Column) {
+			return fmt.Errorf("%w: %v", ErrUnknownOrderColumn, sort.Column)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,423 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/domain/identity"
+	enginerepo "db-dashboards/internal/repository/engine"
+	"db-dashboards/internal/repository/sqlite"
+)
+
+// newTestRepo returns sqlite repo of in-memory items table, id is 1..10, score is id % 3, tag is NULL for even ids
+func newTestRepo(t *testing.T) enginerepo.Repo {
+	t.Helper()
+
+	db, err := sqlx.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	// every pooled connection would get its own in-memory database
+	db.SetMaxOpenConns(1)
+
+	db.MustExec(`CREATE TABLE items (id INTEGER PRIMARY KEY, name TEXT NOT NULL, score INTEGER NOT NULL, tag TEXT)`)
+
+	for id := 1; id <= 10; id++ {
+		var tag any
+		if id%2 == 1 {
+			tag = fmt.Sprintf("tag%v", id)
+		}
+
+		db.MustExec(`INSERT INTO items (id, name, score, tag) VALUES (?, ?, ?, ?)`, id, fmt.Sprintf("item%02d", id), id%3, tag)
+	}
+
+	return sqlite.New(db)
+}
+
+func newTestService() *Service {
+	return New(time.Minute, 100, 100, 100, 1<<20)
+}
+
+// ids returns first column of rows
+func ids(rows [][]any) []int64 {
+	result := make([]int64, len(rows))
+
+	for i, row := range rows {
+		result[i] = row[0].(int64)
+	}
+
+	return result
+}
+
+func TestGetRowsFromTablePaging(t *testing.T) {
+	repo := newTestRepo(t)
+	s := newTestService()
+
+	tests := []struct {
+		name    string
+		q       entity.RowsQuery
+		wantIDs []int64
+	}{
+		{"first page", entity.RowsQuery{Sort: []entity.Sort{{Column: "id"}}, Limit: 3}, []int64{1, 2, 3}},
+		{"second page", entity.RowsQuery{Sort: []entity.Sort{{Column: "id"}}, Limit: 3, Offset: 3}, []int64{4, 5, 6}},
+		{"last page", entity.RowsQuery{Sort: []entity.Sort{{Column: "id"}}, Limit: 3, Offset: 9}, []int64{10}},
+		{"past end", entity.RowsQuery{Sort: []entity.Sort{{Column: "id"}}, Limit: 3, Offset: 10}, []int64{}},
+		{"descending", entity.RowsQuery{Sort: []entity.Sort{{Column: "id", Desc: true}}, Limit: 2}, []int64{10, 9}},
+		{
+			"mixed directions",
+			entity.RowsQuery{Sort: []entity.Sort{{Column: "score", Desc: true}, {Column: "id"}}, Limit: 4},
+			[]int64{2, 5, 8, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.q.Table = "items"
+
+			page, err := s.GetRowsFromTable(context.Background(), repo, tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := ids(page.Rows); !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", got, tt.wantIDs)
+			}
+
+			if page.Total != nil {
+				t.Errorf("total = %v, want none", *page.Total)
+			}
+		})
+	}
+}
+
+func TestGetRowsFromTableColumns(t *testing.T) {
+	page, err := newTestService().GetRowsFromTable(context.Background(), newTestRepo(t),
+		entity.RowsQuery{Table: "items", Sort: []entity.Sort{{Column: "id"}}, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, len(page.Columns))
+
+	for i, column := range page.Columns {
+		names[i] = column.Name
+	}
+
+	if want := []string{"id", "name", "score", "tag"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("columns = %v, want %v", names, want)
+	}
+
+	if want := []any{int64(1), "item01", int64(1), "tag1"}; !reflect.DeepEqual(page.Rows[0], want) {
+		t.Errorf("row = %#v, want %#v", page.Rows[0], want)
+	}
+}
+
+func TestGetRowsFromTableFilters(t *testing.T) {
+	repo := newTestRepo(t)
+	s := newTestService()
+
+	tests := []struct {
+		name    string
+		filters []entity.Filter
+		wantIDs []int64
+	}{
+		{"eq", []entity.Filter{{Column: "score", Op: entity.FilterOpEq, Values: []string{"0"}}}, []int64{3, 6, 9}},
+		{"ne", []entity.Filter{{Column: "score", Op: entity.FilterOpNe, Values: []string{"0"}}}, []int64{1, 2, 4, 5, 7, 8, 10}},
+		{"lt", []entity.Filter{{Column: "id", Op: entity.FilterOpLt, Values: []string{"3"}}}, []int64{1, 2}},
+		{"gt", []entity.Filter{{Column: "id", Op: entity.FilterOpGt, Values: []string{"8"}}}, []int64{9, 10}},
+		{"like", []entity.Filter{{Column: "name", Op: entity.FilterOpLike, Values: []string{"item1%"}}}, []int64{10}},
+		{"in", []entity.Filter{{Column: "id", Op: entity.FilterOpIn, Values: []string{"2", "4", "11"}}}, []int64{2, 4}},
+		{"is null", []entity.Filter{{Column: "tag", Op: entity.FilterOpIsNull}}, []int64{2, 4, 6, 8, 10}},
+		{"not null", []entity.Filter{{Column: "tag", Op: entity.FilterOpNotNull}}, []int64{1, 3, 5, 7, 9}},
+		{
+			"combined",
+			[]entity.Filter{
+				{Column: "tag", Op: entity.FilterOpNotNull},
+				{Column: "score", Op: entity.FilterOpEq, Values: []string{"1"}},
+			},
+			[]int64{1, 7},
+		},
+		{"no match", []entity.Filter{{Column: "name", Op: entity.FilterOpEq, Values: []string{"' OR '1'='1"}}}, []int64{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := s.GetRowsFromTable(context.Background(), repo, entity.RowsQuery{
+				Table:   "items",
+				Filters: tt.filters,
+				Sort:    []entity.Sort{{Column: "id"}},
+				Limit:   100,
+				Count:   entity.CountModeExact,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := ids(page.Rows); !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", got, tt.wantIDs)
+			}
+
+			if page.Total == nil || *page.Total != int64(len(tt.wantIDs)) {
+				t.Errorf("total = %v, want %v", page.Total, len(tt.wantIDs))
+			}
+		})
+	}
+}
+
+func TestGetRowsFromTableCount(t *testing.T) {
+	repo := newTestRepo(t)
+	s := newTestService()
+
+	tests := []struct {
+		name          string
+		q             entity.RowsQuery
+		wantTotal     int64
+		wantEstimated bool
+	}{
+		{"exact", entity.RowsQuery{Count: entity.CountModeExact, Limit: 2}, 10, false},
+		{"estimated", entity.RowsQuery{Count: entity.CountModeEstimated, Limit: 2}, 10, true},
+		{
+			"estimated with filter is exact",
+			entity.RowsQuery{
+				Count:   entity.CountModeEstimated,
+				Filters: []entity.Filter{{Column: "score", Op: entity.FilterOpEq, Values: []string{"2"}}},
+				Limit:   1,
+			},
+			3,
+			false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.q.Table = "items"
+
+			page, err := s.GetRowsFromTable(context.Background(), repo, tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if page.Total == nil || *page.Total != tt.wantTotal || page.TotalEstimated != tt.wantEstimated {
+				t.Errorf("total = %v estimated %v, want %v estimated %v", page.Total, page.TotalEstimated, tt.wantTotal, tt.wantEstimated)
+			}
+		})
+	}
+}
+
+func TestGetRowsFromTableCursor(t *testing.T) {
+	repo := newTestRepo(t)
+	s := newTestService()
+
+	tests := []struct {
+		name    string
+		sort    []entity.Sort
+		filters []entity.Filter
+		wantIDs []int64
+	}{
+		{"ascending", []entity.Sort{{Column: "id"}}, nil, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}},
+		{"descending", []entity.Sort{{Column: "id", Desc: true}}, nil, []int64{10, 9, 8, 7, 6, 5, 4, 3, 2, 1}},
+		{
+			"mixed directions with ties",
+			[]entity.Sort{{Column: "score", Desc: true}, {Column: "id"}},
+			nil,
+			[]int64{2, 5, 8, 1, 4, 7, 10, 3, 6, 9},
+		},
+		{
+			"duplicate sort values",
+			[]entity.Sort{{Column: "score"}},
+			nil,
+			[]int64{3, 6, 9, 1, 4, 7, 10, 2, 5, 8},
+		},
+		{
+			"duplicate sort values descending",
+			[]entity.Sort{{Column: "score", Desc: true}},
+			nil,
+			[]int64{2, 5, 8, 1, 4, 7, 10, 3, 6, 9},
+		},
+		{
+			"text column",
+			[]entity.Sort{{Column: "name", Desc: true}},
+			[]entity.Filter{{Column: "score", Op: entity.FilterOpNe, Values: []string{"0"}}},
+			[]int64{10, 8, 7, 5, 4, 2, 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				got    []int64
+				cursor string
+			)
+
+			for pages := 0; ; pages++ {
+				if pages > 10 {
+					t.Fatal("cursor pagination does not end")
+				}
+
+				page, err := s.GetRowsFromTable(context.Background(), repo, entity.RowsQuery{
+					Table:   "items",
+					Filters: tt.filters,
+					Sort:    tt.sort,
+					Limit:   3,
+					Cursor:  cursor,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				got = append(got, ids(page.Rows)...)
+
+				if page.NextCursor == "" {
+					break
+				}
+
+				cursor = page.NextCursor
+			}
+
+			if !reflect.DeepEqual(got, tt.wantIDs) {
+				t.Errorf("ids = %v, want %v", got, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestGetRowsFromTableNoCursorWithoutSort(t *testing.T) {
+	page, err := newTestService().GetRowsFromTable(context.Background(), newTestRepo(t),
+		entity.RowsQuery{Table: "items", Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if page.NextCursor != "" {
+		t.Errorf("next cursor %q returned for unordered rows", page.NextCursor)
+	}
+}
+
+func TestGetRowsFromTableNoCursorWithoutKeyset(t *testing.T) {
+	repo := newTestRepo(t)
+	s := newTestService()
+
+	db := repo.(*sqlite.Repo).DB
+	db.MustExec(`CREATE TABLE events (name TEXT NOT NULL)`)
+	db.MustExec(`INSERT INTO events (name) VALUES ('a'), ('b'), ('c'), ('d')`)
+
+	tests := []struct {
+		name string
+		q    entity.RowsQuery
+	}{
+		{"nullable sort column", entity.RowsQuery{Table: "items", Sort: []entity.Sort{{Column: "tag"}}, Limit: 3}},
+		{"nullable column among sort columns", entity.RowsQuery{Table: "items", Sort: []entity.Sort{{Column: "id"}, {Column: "tag"}}, Limit: 3}},
+		{"table without primary key", entity.RowsQuery{Table: "events", Sort: []entity.Sort{{Column: "name"}}, Limit: 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := s.GetRowsFromTable(context.Background(), repo, tt.q)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(page.Rows) != 3 || page.NextCursor != "" {
+				t.Errorf("rows = %v, next cursor = %q, want 3 rows without cursor", page.Rows, page.NextCursor)
+			}
+		})
+	}
+}
+
+func TestGetRowsFromTableInvalid(t *testing.T) {
+	repo := newTestRepo(t)
+	s := newTestService()
+
+	byID := []entity.Sort{{Column: "id"}}
+
+	first, err := s.GetRowsFromTable(context.Background(), repo, entity.RowsQuery{Table: "items", Sort: byID, Limit: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		q       entity.RowsQuery
+		wantErr error
+	}{
+		{"unknown table", entity.RowsQuery{Table: "missing"}, enginerepo.ErrTableNotFound},
+		{"unknown sort column", entity.RowsQuery{Table: "items", Sort: []entity.Sort{{Column: "missing"}}}, enginerepo.ErrUnknownColumn},
+		{
+			"unknown filter column",
+			entity.RowsQuery{Table: "items", Filters: []entity.Filter{{Column: "id; --", Op: entity.FilterOpEq, Values: []string{"1"}}}},
+			enginerepo.ErrUnknownColumn,
+		},
+		{
+			"eq without value",
+			entity.RowsQuery{Table: "items", Filters: []entity.Filter{{Column: "id", Op: entity.FilterOpEq}}},
+			ErrInvalidFilterValues,
+		},
+		{
+			"in without values",
+			entity.RowsQuery{Table: "items", Filters: []entity.Filter{{Column: "id", Op: entity.FilterOpIn}}},
+			ErrInvalidFilterValues,
+		},
+		{
+			"is null with value",
+			entity.RowsQuery{Table: "items", Filters: []entity.Filter{{Column: "tag", Op: entity.FilterOpIsNull, Values: []string{"x"}}}},
+			ErrInvalidFilterValues,
+		},
+		{"cursor without sort", entity.RowsQuery{Table: "items", Cursor: first.NextCursor}, ErrCursorRequiresSort},
+		{"cursor with offset", entity.RowsQuery{Table: "items", Sort: byID, Offset: 3, Cursor: first.NextCursor}, ErrCursorWithOffset},
+		{
+			"cursor of other sort",
+			entity.RowsQuery{Table: "items", Sort: []entity.Sort{{Column: "id", Desc: true}}, Cursor: first.NextCursor},
+			ErrInvalidCursor,
+		},
+		{"malformed cursor", entity.RowsQuery{Table: "items", Sort: byID, Cursor: "not a cursor"}, ErrInvalidCursor},
+		{
+			"cursor with nullable sort column",
+			entity.RowsQuery{Table: "items", Sort: []entity.Sort{{Column: "tag"}}, Cursor: first.NextCursor},
+			ErrCursorUnsupported,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := s.GetRowsFromTable(context.Background(), repo, tt.q); !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestExecuteQueryWriteRequiresRole(t *testing.T) {
+	tests := []struct {
+		role    entity.Role
+		wantErr error
+	}{
+		{entity.RoleAdmin, nil},
+		{entity.RoleEditor, nil},
+		// write access granted on connection doesn't let viewers change target database
+		{entity.RoleViewer, ErrMultipleStatements},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.role), func(t *testing.T) {
+			ctx := identity.WithIdentity(context.Background(), identity.Identity{UserID: 1, Role: tt.role})
+
+			result, err := newTestService().ExecuteQuery(ctx, newTestRepo(t), "DELETE FROM items; SELECT count(*) FROM items", nil, true)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ExecuteQuery() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil && result.Rows[0][0] != int64(0) {
+				t.Errorf("count = %v, want 0", result.Rows[0][0])
+			}
+		})
+	}
+}
@@ -0,0 +1,6 @@
+package history
+
+import "errors"
+
+// ErrNotRecorded is returned along with result of query that was executed but could not be saved to history
+var ErrNotRecorded = errors.New("query is not recorded to history")
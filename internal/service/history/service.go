@@ -0,0 +1,180 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/domain/identity"
+
+	enginerepo "db-dashboards/internal/repository/engine"
+	historyrepo "db-dashboards/internal/repository/history"
+)
+
+type Repo interface {
+	GetEntries(ctx context.Context, filter entity.HistoryFilter) ([]*entity.HistoryEntry, error)
+	GetEntryByID(ctx context.Context, id int) (*entity.HistoryEntry, error)
+	CreateEntry(ctx context.Context, entry entity.HistoryEntry) (*entity.HistoryEntry, error)
+	SetEntryStarred(ctx context.Context, id int, starred bool) (*entity.HistoryEntry, error)
+}
+
+type ConnectionService interface {
+	OpenConnection(ctx context.Context, userID, id int, need entity.ConnectionAccess) (*entity.Connection, enginerepo.Repo, error)
+}
+
+type QueryExecutor interface {
+	ExecuteQuery(ctx context.Context, repo enginerepo.Repo, query string, params []any, allowWrite bool) (*entity.QueryResult, error)
+	StreamQuery(ctx context.Context,
+		repo enginerepo.Repo,
+		query string,
+		params []any,
+		allowWrite bool,
+		w enginerepo.RowWriter,
+	) (*enginerepo.StreamResult, error)
+}
+
+type Service struct {
+	Repo              Repo
+	ConnectionService ConnectionService
+	QueryExecutor     QueryExecutor
+}
+
+func New(repo Repo, connectionService ConnectionService, queryExecutor QueryExecutor) *Service {
+	return &Service{
+		Repo:              repo,
+		ConnectionService: connectionService,
+		QueryExecutor:     queryExecutor,
+	}
+}
+
+// GetEntries returns history of user within active workspace of request, personal history if there is none
+func (s *Service) GetEntries(ctx context.Context, filter entity.HistoryFilter) ([]*entity.HistoryEntry, error) {
+	filter.WorkspaceID = identity.WorkspaceID(ctx)
+
+	return s.Repo.GetEntries(ctx, filter)
+}
+
+// GetEntryByID returns history entry of user, entries of other users are not revealed
+func (s *Service) GetEntryByID(ctx context.Context, userID, id int) (*entity.HistoryEntry, error) {
+	entry, err := s.Repo.GetEntryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.UserID != userID || !identity.InScope(ctx, entry.WorkspaceID) {
+		return nil, historyrepo.ErrHistoryEntryNotFound
+	}
+
+	return entry, nil
+}
+
+func (s *Service) SetStarred(ctx context.Context, userID, id int, starred bool) (*entity.HistoryEntry, error) {
+	if _, err := s.GetEntryByID(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.SetEntryStarred(ctx, id, starred)
+}
+
+// Execute runs query against connection and records it to history of user, failed queries are recorded as well.
+// Every query console, saved query and widget execution goes through it.
+// If query succeeded but could not be recorded its result is returned along with ErrNotRecorded
+func (s *Service) Execute(ctx context.Context,
+	userID int,
+	conn *entity.Connection,
+	repo enginerepo.Repo,
+	query string,
+	params []any,
+	allowWrite bool,
+) (*entity.QueryResult, error) {
+	start := time.Now()
+
+	result, err := s.QueryExecutor.ExecuteQuery(ctx, repo, query, params, allowWrite)
+
+	var rows int
+	if result != nil {
+		rows = len(result.Rows)
+	}
+
+	if recordErr := s.record(ctx, userID, conn, query, params, start, rows, err); recordErr != nil && err == nil {
+		return result, recordErr
+	}
+
+	return result, err
+}
+
+// Stream runs query like Execute but writes result rows to w as they are read
+func (s *Service) Stream(ctx context.Context,
+	userID int,
+	conn *entity.Connection,
+	repo enginerepo.Repo,
+	query string,
+	params []any,
+	allowWrite bool,
+	w enginerepo.RowWriter,
+) (*enginerepo.StreamResult, error) {
+	start := time.Now()
+
+	result, err := s.QueryExecutor.StreamQuery(ctx, repo, query, params, allowWrite, w)
+
+	var rows int
+	if result != nil {
+		rows = result.Rows
+	}
+
+	if recordErr := s.record(ctx, userID, conn, query, params, start, rows, err); recordErr != nil && err == nil {
+		return result, recordErr
+	}
+
+	return result, err
+}
+
+// record saves query started at start even if client went away
+func (s *Service) record(ctx context.Context,
+	userID int,
+	conn *entity.Connection,
+	query string,
+	params []any,
+	start time.Time,
+	rows int,
+	queryErr error,
+) error {
+	entry := entity.HistoryEntry{
+		UserID:       userID,
+		WorkspaceID:  conn.WorkspaceID,
+		ConnectionID: conn.ID,
+		SQL:          query,
+		Params:       params,
+		DurationMs:   time.Since(start).Milliseconds(),
+		CreatedAt:    start,
+	}
+
+	if queryErr != nil {
+		entry.Error = queryErr.Error()
+	} else {
+		entry.RowCount = rows
+	}
+
+	if _, err := s.Repo.CreateEntry(context.WithoutCancel(ctx), entry); err != nil {
+		return fmt.Errorf("%w: %v", ErrNotRecorded, err)
+	}
+
+	return nil
+}
+
+// Rerun replays query of history entry against the same connection, replay is recorded as new entry.
+// Write statements are allowed if user has write access to connection
+func (s *Service) Rerun(ctx context.Context, userID, id int) (*entity.QueryResult, error) {
+	entry, err := s.GetEntryByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, repo, err := s.ConnectionService.OpenConnection(ctx, userID, entry.ConnectionID, entity.ConnectionAccessQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Execute(ctx, userID, conn, repo, entry.SQL, entry.Params, conn.Access.Allows(entity.ConnectionAccessWrite))
+}
@@ -0,0 +1,15 @@
+package savedquery
+
+import "errors"
+
+var (
+	ErrAccessDenied = errors.New("insufficient access to saved query")
+
+	ErrInvalidParamName   = errors.New("invalid parameter name")
+	ErrInvalidParamType   = errors.New("invalid parameter type")
+	ErrDuplicateParam     = errors.New("duplicate parameter")
+	ErrInvalidParamValue  = errors.New("invalid parameter value")
+	ErrMissingParam       = errors.New("missing parameter value")
+	ErrUndeclaredParam    = errors.New("parameter is not declared")
+	ErrUnknownParamPassed = errors.New("unknown parameter passed")
+)
@@ -0,0 +1,159 @@
+package savedquery
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+
+	"db-dashboards/internal/domain/entity"
+
+	sliceutils "db-dashboards/pkg/utils/slice"
+)
+
+var paramName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var paramTypes = []entity.ParamType{
+	entity.ParamTypeString,
+	entity.ParamTypeInteger,
+	entity.ParamTypeNumber,
+	entity.ParamTypeBoolean,
+	entity.ParamTypeTimestamp,
+}
+
+func validateParams(params entity.QueryParameters) error {
+	seen := make(map[string]struct{}, len(params))
+
+	for _, param := range params {
+		if !paramName.MatchString(param.Name) {
+			return fmt.Errorf("%w: %q", ErrInvalidParamName, param.Name)
+		}
+
+		if _, ok := seen[param.Name]; ok {
+			return fmt.Errorf("%w: %v", ErrDuplicateParam, param.Name)
+		}
+
+		seen[param.Name] = struct{}{}
+
+		if !sliceutils.Contains(paramTypes, param.Type) {
+			return fmt.Errorf("%w: %v", ErrInvalidParamType, param.Type)
+		}
+
+		if param.Default == nil {
+			continue
+		}
+
+		if _, err := coerceParam(param, param.Default); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// bindParams returns args for compiled query in order of placeholder names,
+// passed values are coerced to declared types, missing values are taken from defaults
+func bindParams(declared entity.QueryParameters, names []string, values map[string]any) ([]any, error) {
+	byName := make(map[string]entity.QueryParameter, len(declared))
+
+	for _, param := range declared {
+		byName[param.Name] = param
+	}
+
+	for name := range values {
+		if _, ok := byName[name]; !ok {
+			return nil, fmt.Errorf("%w: %v", ErrUnknownParamPassed, name)
+		}
+	}
+
+	args := make([]any, 0, len(names))
+
+	for _, name := range names {
+		param, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %v", ErrUndeclaredParam, name)
+		}
+
+		value, ok := values[name]
+		if !ok {
+			if param.Default == nil {
+				return nil, fmt.Errorf("%w: %v", ErrMissingParam, name)
+			}
+
+			value = param.Default
+		}
+
+		arg, err := coerceParam(param, value)
+		if err != nil {
+			return nil, err
+		}
+
+		args = append(args, arg)
+	}
+
+	return args, nil
+}
+
+// coerceParam converts json decoded value to declared type, null is bound as is
+func coerceParam(param entity.QueryParameter, value any) (any, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	invalid := fmt.Errorf("%w: %v expects %v, got %v", ErrInvalidParamValue, param.Name, param.Type, value)
+
+	switch param.Type {
+	case entity.ParamTypeString:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+
+	case entity.ParamTypeInteger:
+		switch v := value.(type) {
+		case float64:
+			if v == math.Trunc(v) {
+				return int64(v), nil
+			}
+
+		case string:
+			if i, err := strconv.ParseInt(v, 10, 64); err == nil {
+				return i, nil
+			}
+		}
+
+	case entity.ParamTypeNumber:
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+
+		case string:
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				return f, nil
+			}
+		}
+
+	case entity.ParamTypeBoolean:
+		switch v := value.(type) {
+		case bool:
+			return v, nil
+
+		case string:
+			if b, err := strconv.ParseBool(v); err == nil {
+				return b, nil
+			}
+		}
+
+	case entity.ParamTypeTimestamp:
+		if v, ok := value.(string); ok {
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				return t, nil
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParamType, param.Type)
+	}
+
+	return nil, invalid
+}
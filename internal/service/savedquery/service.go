@@ -0,0 +1,224 @@
+package savedquery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/domain/identity"
+
+	enginerepo "db-dashboards/internal/repository/engine"
+	savedqueryrepo "db-dashboards/internal/repository/savedquery"
+)
+
+type Repo interface {
+	GetAllSavedQueries(ctx context.Context, userID int, workspaceID *int, tag string, offset, limit int) ([]*entity.SavedQuery, error)
+	GetSavedQueryByID(ctx context.Context, id int) (*entity.SavedQuery, error)
+	CreateSavedQuery(ctx context.Context, query entity.SavedQuery) (*entity.SavedQuery, error)
+	UpdateSavedQuery(ctx context.Context, query entity.SavedQuery) (*entity.SavedQuery, error)
+	DeleteSavedQuery(ctx context.Context, id int) (*entity.SavedQuery, error)
+}
+
+type ConnectionService interface {
+	Authorize(ctx context.Context, userID, id int, need entity.ConnectionAccess) (*entity.Connection, error)
+	OpenConnection(ctx context.Context, userID, id int, need entity.ConnectionAccess) (*entity.Connection, enginerepo.Repo, error)
+}
+
+// HistoryService runs queries and records them to history of user
+type HistoryService interface {
+	Execute(ctx context.Context,
+		userID int,
+		conn *entity.Connection,
+		repo enginerepo.Repo,
+		query string,
+		params []any,
+		allowWrite bool,
+	) (*entity.QueryResult, error)
+	Stream(ctx context.Context,
+		userID int,
+		conn *entity.Connection,
+		repo enginerepo.Repo,
+		query string,
+		params []any,
+		allowWrite bool,
+		w enginerepo.RowWriter,
+	) (*enginerepo.StreamResult, error)
+}
+
+type Service struct {
+	Repo              Repo
+	ConnectionService ConnectionService
+	HistoryService    HistoryService
+}
+
+func New(repo Repo, connectionService ConnectionService, historyService HistoryService) *Service {
+	return &Service{
+		Repo:              repo,
+		ConnectionService: connectionService,
+		HistoryService:    historyService,
+	}
+}
+
+// GetAllSavedQueries returns saved queries of active workspace of request or personal saved queries of user
+func (s *Service) GetAllSavedQueries(ctx context.Context, userID int, tag string, offset, limit int) ([]*entity.SavedQuery, error) {
+	return s.Repo.GetAllSavedQueries(ctx, userID, identity.WorkspaceID(ctx), tag, offset, limit)
+}
+
+// GetSavedQueryByID returns personal saved query of user or saved query of active workspace of request
+func (s *Service) GetSavedQueryByID(ctx context.Context, userID, id int) (*entity.SavedQuery, error) {
+	query, err := s.Repo.GetSavedQueryByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// do not reveal existence of queries owned by other users or out of workspace scope
+	if !identity.InScope(ctx, query.WorkspaceID) || (query.WorkspaceID == nil && query.UserID != userID) {
+		return nil, savedqueryrepo.ErrSavedQueryNotFound
+	}
+
+	return query, nil
+}
+
+func (s *Service) CreateSavedQuery(ctx context.Context, query entity.SavedQuery) (*entity.SavedQuery, error) {
+	if ws, ok := identity.ActiveWorkspace(ctx); ok && !ws.Role.Allows(entity.WorkspaceRoleEditor) {
+		return nil, fmt.Errorf("%w: workspace viewers can not create saved queries", ErrAccessDenied)
+	}
+
+	if err := validateParams(query.Parameters); err != nil {
+		return nil, err
+	}
+
+	// query can be bound only to connection of the same user
+	if _, err := s.ConnectionService.Authorize(ctx, query.UserID, query.ConnectionID, entity.ConnectionAccessQuery); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	query.WorkspaceID = identity.WorkspaceID(ctx)
+	query.CreatedAt = now
+	query.UpdatedAt = now
+
+	return s.Repo.CreateSavedQuery(ctx, query)
+}
+
+// UpdateSavedQuery overwrites only non-zero fields, nil parameters and tags keep stored ones
+func (s *Service) UpdateSavedQuery(ctx context.Context, userID int, query entity.SavedQuery) (*entity.SavedQuery, error) {
+	existing, err := s.getEditableQuery(ctx, userID, query.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.ConnectionID != 0 {
+		if _, err = s.ConnectionService.Authorize(ctx, userID, query.ConnectionID, entity.ConnectionAccessQuery); err != nil {
+			return nil, err
+		}
+
+		existing.ConnectionID = query.ConnectionID
+	}
+
+	if query.Name != "" {
+		existing.Name = query.Name
+	}
+
+	if query.Description != "" {
+		existing.Description = query.Description
+	}
+
+	if query.SQL != "" {
+		existing.SQL = query.SQL
+	}
+
+	if query.Parameters != nil {
+		if err = validateParams(query.Parameters); err != nil {
+			return nil, err
+		}
+
+		existing.Parameters = query.Parameters
+	}
+
+	if query.Tags != nil {
+		existing.Tags = query.Tags
+	}
+
+	existing.UpdatedAt = time.Now()
+
+	return s.Repo.UpdateSavedQuery(ctx, *existing)
+}
+
+func (s *Service) DeleteSavedQuery(ctx context.Context, userID, id int) (*entity.SavedQuery, error) {
+	if _, err := s.getEditableQuery(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.DeleteSavedQuery(ctx, id)
+}
+
+// ExecuteSavedQuery runs saved query against its connection, named params are bound by values or declared defaults
+func (s *Service) ExecuteSavedQuery(ctx context.Context, userID, id int, values map[string]any) (*entity.QueryResult, error) {
+	conn, repo, compiled, args, err := s.prepareSavedQuery(ctx, userID, id, values)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.HistoryService.Execute(ctx, userID, conn, repo, compiled, args, false)
+}
+
+// StreamSavedQuery runs saved query like ExecuteSavedQuery but writes result rows to w as they are read
+func (s *Service) StreamSavedQuery(ctx context.Context,
+	userID, id int,
+	values map[string]any,
+	w enginerepo.RowWriter,
+) (*enginerepo.StreamResult, error) {
+	conn, repo, compiled, args, err := s.prepareSavedQuery(ctx, userID, id, values)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.HistoryService.Stream(ctx, userID, conn, repo, compiled, args, false, w)
+}
+
+// prepareSavedQuery opens connection of saved query and compiles it with bound param values
+func (s *Service) prepareSavedQuery(ctx context.Context,
+	userID, id int,
+	values map[string]any,
+) (*entity.Connection, enginerepo.Repo, string, []any, error) {
+	query, err := s.GetSavedQueryByID(ctx, userID, id)
+	if err != nil {
+		return nil, nil, "", nil, err
+	}
+
+	conn, repo, err := s.ConnectionService.OpenConnection(ctx, userID, query.ConnectionID, entity.ConnectionAccessQuery)
+	if err != nil {
+		return nil, nil, "", nil, err
+	}
+
+	compiled, names := enginerepo.CompileNamedParams(query.SQL, repo.Dialect())
+
+	args, err := bindParams(query.Parameters, names, values)
+	if err != nil {
+		return nil, nil, "", nil, err
+	}
+
+	return conn, repo, compiled, args, nil
+}
+
+// getEditableQuery returns saved query if user may change it, in workspace editors change queries of other members
+func (s *Service) getEditableQuery(ctx context.Context, userID, id int) (*entity.SavedQuery, error) {
+	query, err := s.GetSavedQueryByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.UserID == userID {
+		return query, nil
+	}
+
+	if !identity.HasWorkspaceRole(ctx, entity.WorkspaceRoleOwner, entity.WorkspaceRoleEditor) ||
+		!identity.HasRole(ctx, entity.RoleAdmin, entity.RoleEditor) {
+		return nil, fmt.Errorf("%w: only owner or workspace editors change saved query", ErrAccessDenied)
+	}
+
+	return query, nil
+}
@@ -0,0 +1,12 @@
+package user
+
+import "errors"
+
+var (
+	ErrAdminRequired = errors.New("admin rights required")
+	ErrInvalidRole   = errors.New("invalid role")
+	ErrLastAdmin     = errors.New("can not remove the last admin")
+	ErrManageSelf    = errors.New("admins can not delete or disable themselves")
+	ErrWrongPassword = errors.New("current password is wrong")
+	ErrEmailInvited  = errors.New("email has pending workspace invitations")
+)
@@ -2,38 +2,57 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/domain/identity"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type Repo interface {
-	CreateUser(ctx context.Context, user entity.User) (*entity.User, error)
+	RegisterUser(ctx context.Context, user entity.User, firstRole entity.Role) (*entity.User, error)
 	GetUserByID(ctx context.Context, id int) (*entity.User, error)
 	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
-	GetAllUsers(ctx context.Context, offset, limit int) ([]*entity.User, error)
+	GetAllUsers(ctx context.Context, search string, offset, limit int) ([]*entity.User, error)
+	UpdateUser(ctx context.Context, user entity.User) (*entity.User, error)
 	DeleteUser(ctx context.Context, id int) (*entity.User, error)
 	CheckUniqueConstraints(ctx context.Context, email string) error
+	UpdateUserRole(ctx context.Context, id int, role entity.Role, updatedAt time.Time) (*entity.User, error)
+	CountUsersWithRole(ctx context.Context, role entity.Role) (int, error)
+	SetUserDisabled(ctx context.Context, id int, disabledAt *time.Time, updatedAt time.Time) (*entity.User, error)
+}
+
+type TokenRepo interface {
+	RevokeUserSessions(ctx context.Context, userID int, exceptSessionID string, revokedAt time.Time) error
+}
+
+type InvitationRepo interface {
+	GetInvitationsByEmail(ctx context.Context, email string) ([]*entity.Invitation, error)
 }
 
 type Hasher interface {
 	GenerateFromPassword(password []byte, cost int) ([]byte, error)
+	CompareHashAndPassword(hashedPassword []byte, password []byte) error
 }
 
 type Service struct {
-	Repo   Repo
-	Hasher Hasher
+	Repo           Repo
+	TokenRepo      TokenRepo
+	InvitationRepo InvitationRepo
+	Hasher         Hasher
 }
 
-func New(repo Repo, hasher Hasher) *Service {
+func New(repo Repo, tokenRepo TokenRepo, invitationRepo InvitationRepo, hasher Hasher) *Service {
 	return &Service{
-		Repo:   repo,
-		Hasher: hasher,
+		Repo:           repo,
+		TokenRepo:      tokenRepo,
+		InvitationRepo: invitationRepo,
+		Hasher:         hasher,
 	}
 }
 
-func (s *Service) GetAllUsers(ctx context.Context, offset, limit int) ([]*entity.User, error) {
-	return s.Repo.GetAllUsers(ctx, offset, limit)
+func (s *Service) GetAllUsers(ctx context.Context, search string, offset, limit int) ([]*entity.User, error) {
+	return s.Repo.GetAllUsers(ctx, search, offset, limit)
 }
 
 func (s *Service) GetUserByID(ctx context.Context, id int) (*entity.User, error) {
@@ -69,7 +88,15 @@ func (s *Service) RegisterUser(ctx context.Context, user entity.User) (*entity.U
 
 	user.HashedPassword = string(hash)
 
-	created, err := s.Repo.CreateUser(ctx, user)
+	user.Role = entity.RoleEditor
+
+	now := time.Now()
+
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	// the first registered user administers the installation
+	created, err := s.Repo.RegisterUser(ctx, user, entity.RoleAdmin)
 	if err != nil {
 		return nil, err
 	}
@@ -77,7 +104,110 @@ func (s *Service) RegisterUser(ctx context.Context, user entity.User) (*entity.U
 	return created, nil
 }
 
-func (s *Service) DeleteUser(ctx context.Context, id int) (*entity.User, error) { // todo: authorize admin rights
+// UpdateUser changes email and/or password of user after current password is confirmed,
+// password change revokes every other session of user
+func (s *Service) UpdateUser(ctx context.Context, id int, currentPassword, email, newPassword string) (*entity.User, error) {
+	user, err := s.Repo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.Hasher.CompareHashAndPassword([]byte(user.HashedPassword), []byte(currentPassword)); err != nil {
+		return nil, ErrWrongPassword
+	}
+
+	if email != "" && email != user.Email {
+		// ensure that user with this email does not exist
+		if err = s.Repo.CheckUniqueConstraints(ctx, email); err != nil {
+			return nil, err
+		}
+
+		// invitations are accepted by email, ownership of address is not verified on change
+		invitations, err := s.InvitationRepo.GetInvitationsByEmail(ctx, email)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(invitations) > 0 {
+			return nil, ErrEmailInvited
+		}
+
+		user.Email = email
+	}
+
+	if newPassword != "" {
+		hash, err := s.Hasher.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, err
+		}
+
+		user.HashedPassword = string(hash)
+	}
+
+	now := time.Now()
+
+	user.UpdatedAt = now
+
+	updated, err := s.Repo.UpdateUser(ctx, *user)
+	if err != nil {
+		return nil, err
+	}
+
+	if newPassword != "" {
+		// current session may be absent when called outside of request
+		current, _ := identity.FromContext(ctx)
+
+		if err = s.TokenRepo.RevokeUserSessions(ctx, id, current.SessionID, now); err != nil {
+			return nil, err
+		}
+	}
+
+	return updated, nil
+}
+
+// DisableUser prevents user from logging in and revokes user sessions
+func (s *Service) DisableUser(ctx context.Context, id int) (*entity.User, error) {
+	user, err := s.getManagedUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.DisabledAt != nil {
+		return user, nil
+	}
+
+	now := time.Now()
+
+	disabled, err := s.Repo.SetUserDisabled(ctx, id, &now, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.TokenRepo.RevokeUserSessions(ctx, id, "", now); err != nil {
+		return nil, err
+	}
+
+	return disabled, nil
+}
+
+func (s *Service) EnableUser(ctx context.Context, id int) (*entity.User, error) {
+	user, err := s.getManagedUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.DisabledAt == nil {
+		return user, nil
+	}
+
+	return s.Repo.SetUserDisabled(ctx, id, nil, time.Now())
+}
+
+func (s *Service) DeleteUser(ctx context.Context, id int) (*entity.User, error) {
+	if _, err := s.getManagedUser(ctx, id); err != nil {
+		return nil, err
+	}
+
 	deleted, err := s.Repo.DeleteUser(ctx, id)
 	if err != nil {
 		return nil, err
@@ -85,3 +215,71 @@ func (s *Service) DeleteUser(ctx context.Context, id int) (*entity.User, error)
 
 	return deleted, nil
 }
+
+// SetUserRole changes role of user and revokes user sessions, so that new role applies from the next login
+func (s *Service) SetUserRole(ctx context.Context, id int, role entity.Role) (*entity.User, error) {
+	if !identity.HasRole(ctx, entity.RoleAdmin) {
+		return nil, ErrAdminRequired
+	}
+
+	if !role.Valid() {
+		return nil, ErrInvalidRole
+	}
+
+	user, err := s.Repo.GetUserByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role == role {
+		return user, nil
+	}
+
+	if err = s.ensureNotLastAdmin(ctx, user); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	updated, err := s.Repo.UpdateUserRole(ctx, id, role, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.TokenRepo.RevokeUserSessions(ctx, id, "", now); err != nil {
+		return nil, err
+	}
+
+	return updated, nil
+}
+
+// getManagedUser returns user administered by authenticated admin, admins do not administer themselves
+func (s *Service) getManagedUser(ctx context.Context, id int) (*entity.User, error) {
+	current, err := identity.FromContext(ctx)
+	if err != nil || current.Role != entity.RoleAdmin {
+		return nil, ErrAdminRequired
+	}
+
+	if current.UserID == id {
+		return nil, ErrManageSelf
+	}
+
+	return s.Repo.GetUserByID(ctx, id)
+}
+
+func (s *Service) ensureNotLastAdmin(ctx context.Context, user *entity.User) error {
+	if user.Role != entity.RoleAdmin {
+		return nil
+	}
+
+	admins, err := s.Repo.CountUsersWithRole(ctx, entity.RoleAdmin)
+	if err != nil {
+		return err
+	}
+
+	if admins <= 1 {
+		return ErrLastAdmin
+	}
+
+	return nil
+}
@@ -0,0 +1,157 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"db-dashboards/internal/domain/entity"
+
+	userrepo "db-dashboards/internal/repository/user"
+)
+
+// fakeUsers records registrations, choosing role of the first user is left to repository
+type fakeUsers struct {
+	Repo
+
+	users      []entity.User
+	firstRoles []entity.Role
+	lookupErr  error
+}
+
+func (f *fakeUsers) CheckUniqueConstraints(_ context.Context, email string) error {
+	if f.lookupErr != nil {
+		return f.lookupErr
+	}
+
+	for _, user := range f.users {
+		if user.Email == email {
+			return userrepo.ErrEmailExists
+		}
+	}
+
+	return nil
+}
+
+func (f *fakeUsers) RegisterUser(_ context.Context, user entity.User, firstRole entity.Role) (*entity.User, error) {
+	f.firstRoles = append(f.firstRoles, firstRole)
+
+	user.ID = len(f.users) + 1
+	f.users = append(f.users, user)
+
+	return &user, nil
+}
+
+func (f *fakeUsers) GetUserByID(_ context.Context, id int) (*entity.User, error) {
+	for _, user := range f.users {
+		if user.ID == id {
+			return &user, nil
+		}
+	}
+
+	return nil, userrepo.ErrUserNotFound
+}
+
+func (f *fakeUsers) UpdateUser(_ context.Context, user entity.User) (*entity.User, error) {
+	f.users[user.ID-1] = user
+	return &user, nil
+}
+
+// fakeInvitations holds pending invitations by email
+type fakeInvitations map[string][]*entity.Invitation
+
+func (f fakeInvitations) GetInvitationsByEmail(_ context.Context, email string) ([]*entity.Invitation, error) {
+	return f[strings.ToLower(email)], nil
+}
+
+type plainHasher struct{}
+
+func (plainHasher) GenerateFromPassword(password []byte, _ int) ([]byte, error) {
+	return append([]byte("hash:"), password...), nil
+}
+
+func (plainHasher) CompareHashAndPassword(hashedPassword []byte, password []byte) error {
+	return nil
+}
+
+func TestRegisterUser(t *testing.T) {
+	lookupErr := errors.New("connection refused")
+
+	tests := []struct {
+		name    string
+		users   *fakeUsers
+		wantErr error
+	}{
+		{"new user", &fakeUsers{users: []entity.User{{ID: 1, Email: "other@example.com"}}}, nil},
+		{"taken email", &fakeUsers{users: []entity.User{{ID: 1, Email: "new@example.com"}}}, userrepo.ErrEmailExists},
+		{"lookup error", &fakeUsers{lookupErr: lookupErr}, lookupErr},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{Repo: tt.users, Hasher: plainHasher{}}
+
+			users := len(tt.users.users)
+
+			created, err := s.RegisterUser(context.Background(), entity.User{Email: "new@example.com", HashedPassword: "secret"})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RegisterUser() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				if len(tt.users.users) != users {
+					t.Errorf("user registered after error")
+				}
+
+				return
+			}
+
+			// repository gives the first user first role instead of user role
+			if created.Role != entity.RoleEditor || tt.users.firstRoles[0] != entity.RoleAdmin {
+				t.Errorf("roles = %v, %v, want %v, %v", created.Role, tt.users.firstRoles[0], entity.RoleEditor, entity.RoleAdmin)
+			}
+
+			if created.HashedPassword != "hash:secret" {
+				t.Errorf("hashed password = %v, want hash:secret", created.HashedPassword)
+			}
+		})
+	}
+}
+
+func TestUpdateUserEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		wantErr error
+	}{
+		{"free address", "new@example.com", nil},
+		{"taken address", "other@example.com", userrepo.ErrEmailExists},
+		{"invited address", "invited@example.com", ErrEmailInvited},
+		{"invited address in other case", "Invited@Example.com", ErrEmailInvited},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Service{
+				Repo: &fakeUsers{users: []entity.User{
+					{ID: 1, Email: "me@example.com"},
+					{ID: 2, Email: "other@example.com"},
+				}},
+				InvitationRepo: fakeInvitations{
+					"invited@example.com": {{ID: 1, WorkspaceID: 1, Email: "invited@example.com", Role: entity.WorkspaceRoleOwner}},
+				},
+				Hasher: plainHasher{},
+			}
+
+			updated, err := s.UpdateUser(context.Background(), 1, "secret", tt.email, "")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UpdateUser() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if err == nil && updated.Email != tt.email {
+				t.Errorf("email = %v, want %v", updated.Email, tt.email)
+			}
+		})
+	}
+}
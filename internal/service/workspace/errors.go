@@ -0,0 +1,9 @@
+package workspace
+
+import "errors"
+
+var (
+	ErrAccessDenied = errors.New("insufficient workspace role")
+	ErrInvalidRole  = errors.New("invalid workspace role")
+	ErrLastOwner    = errors.New("workspace must keep at least one owner")
+)
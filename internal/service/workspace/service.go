@@ -0,0 +1,332 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/domain/identity"
+
+	userrepo "db-dashboards/internal/repository/user"
+	workspacerepo "db-dashboards/internal/repository/workspace"
+)
+
+type Repo interface {
+	GetUserWorkspaces(ctx context.Context, userID, offset, limit int) ([]*entity.Workspace, error)
+	GetWorkspaceByID(ctx context.Context, id, userID int) (*entity.Workspace, error)
+	CreateWorkspace(ctx context.Context, ws entity.Workspace, ownerID int) (*entity.Workspace, error)
+	UpdateWorkspace(ctx context.Context, ws entity.Workspace) (*entity.Workspace, error)
+	DeleteWorkspace(ctx context.Context, id int) (*entity.Workspace, error)
+
+	GetMembers(ctx context.Context, workspaceID int) ([]*entity.Membership, error)
+	GetMember(ctx context.Context, workspaceID, userID int) (*entity.Membership, error)
+	UpdateMemberRole(ctx context.Context, workspaceID, userID int, role entity.WorkspaceRole, updatedAt time.Time) (*entity.Membership, error)
+	DeleteMember(ctx context.Context, workspaceID, userID, heirID int) (*entity.Membership, error)
+	CountMembersWithRole(ctx context.Context, workspaceID int, role entity.WorkspaceRole) (int, error)
+
+	GetInvitations(ctx context.Context, workspaceID int) ([]*entity.Invitation, error)
+	GetInvitationsByEmail(ctx context.Context, email string) ([]*entity.Invitation, error)
+	GetInvitationByID(ctx context.Context, id int) (*entity.Invitation, error)
+	CreateInvitation(ctx context.Context, inv entity.Invitation) (*entity.Invitation, error)
+	DeleteInvitation(ctx context.Context, id int) (*entity.Invitation, error)
+	AcceptInvitation(ctx context.Context, inv entity.Invitation, userID int, acceptedAt time.Time) (*entity.Membership, error)
+}
+
+type UserRepo interface {
+	GetUserByID(ctx context.Context, id int) (*entity.User, error)
+	GetUserByEmail(ctx context.Context, email string) (*entity.User, error)
+}
+
+type Service struct {
+	Repo     Repo
+	UserRepo UserRepo
+}
+
+func New(repo Repo, userRepo UserRepo) *Service {
+	return &Service{
+		Repo:     repo,
+		UserRepo: userRepo,
+	}
+}
+
+func (s *Service) GetUserWorkspaces(ctx context.Context, userID, offset, limit int) ([]*entity.Workspace, error) {
+	return s.Repo.GetUserWorkspaces(ctx, userID, offset, limit)
+}
+
+// GetWorkspaceByID returns workspace user is member of with role of user, admins act as owners of every workspace
+func (s *Service) GetWorkspaceByID(ctx context.Context, userID, id int) (*entity.Workspace, error) {
+	ws, err := s.Repo.GetWorkspaceByID(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if identity.HasRole(ctx, entity.RoleAdmin) {
+		ws.Role = entity.WorkspaceRoleOwner
+	}
+
+	// do not reveal existence of workspaces user is not member of
+	if !ws.Role.Valid() {
+		return nil, workspacerepo.ErrWorkspaceNotFound
+	}
+
+	return ws, nil
+}
+
+func (s *Service) CreateWorkspace(ctx context.Context, userID int, ws entity.Workspace) (*entity.Workspace, error) {
+	now := time.Now()
+
+	ws.CreatedAt = now
+	ws.UpdatedAt = now
+
+	return s.Repo.CreateWorkspace(ctx, ws, userID)
+}
+
+func (s *Service) UpdateWorkspace(ctx context.Context, userID int, ws entity.Workspace) (*entity.Workspace, error) {
+	existing, err := s.authorize(ctx, userID, ws.ID, entity.WorkspaceRoleOwner)
+	if err != nil {
+		return nil, err
+	}
+
+	existing.Name = ws.Name
+	existing.UpdatedAt = time.Now()
+
+	return s.Repo.UpdateWorkspace(ctx, *existing)
+}
+
+// DeleteWorkspace deletes workspace together with its connections, saved queries and dashboards
+func (s *Service) DeleteWorkspace(ctx context.Context, userID, id int) (*entity.Workspace, error) {
+	if _, err := s.authorize(ctx, userID, id, entity.WorkspaceRoleOwner); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.DeleteWorkspace(ctx, id)
+}
+
+func (s *Service) GetMembers(ctx context.Context, userID, id int) ([]*entity.Membership, error) {
+	if _, err := s.GetWorkspaceByID(ctx, userID, id); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.GetMembers(ctx, id)
+}
+
+func (s *Service) SetMemberRole(ctx context.Context, userID, id, memberID int, role entity.WorkspaceRole) (*entity.Membership, error) {
+	if _, err := s.authorize(ctx, userID, id, entity.WorkspaceRoleOwner); err != nil {
+		return nil, err
+	}
+
+	if !role.Valid() {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRole, role)
+	}
+
+	member, err := s.Repo.GetMember(ctx, id, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	if member.Role == role {
+		return member, nil
+	}
+
+	if err = s.ensureNotLastOwner(ctx, member); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.UpdateMemberRole(ctx, id, memberID, role, time.Now())
+}
+
+// RemoveMember removes member from workspace, owners remove anyone and every member can leave workspace.
+// Connections, saved queries and dashboards member created in workspace pass to heir of member
+func (s *Service) RemoveMember(ctx context.Context, userID, id, memberID int) (*entity.Membership, error) {
+	need := entity.WorkspaceRoleOwner
+	if memberID == userID {
+		need = entity.WorkspaceRoleViewer
+	}
+
+	if _, err := s.authorize(ctx, userID, id, need); err != nil {
+		return nil, err
+	}
+
+	member, err := s.Repo.GetMember(ctx, id, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.ensureNotLastOwner(ctx, member); err != nil {
+		return nil, err
+	}
+
+	heirID, err := s.heir(ctx, id, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Repo.DeleteMember(ctx, id, memberID, heirID)
+}
+
+func (s *Service) GetInvitations(ctx context.Context, userID, id int) ([]*entity.Invitation, error) {
+	if _, err := s.authorize(ctx, userID, id, entity.WorkspaceRoleOwner); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.GetInvitations(ctx, id)
+}
+
+// InviteMember invites user with email to workspace, user does not have to be registered yet
+func (s *Service) InviteMember(ctx context.Context, userID int, inv entity.Invitation) (*entity.Invitation, error) {
+	if _, err := s.authorize(ctx, userID, inv.WorkspaceID, entity.WorkspaceRoleOwner); err != nil {
+		return nil, err
+	}
+
+	if !inv.Role.Valid() {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidRole, inv.Role)
+	}
+
+	invitee, err := s.UserRepo.GetUserByEmail(ctx, inv.Email)
+
+	switch {
+	case err == nil:
+		if _, err = s.Repo.GetMember(ctx, inv.WorkspaceID, invitee.ID); err == nil {
+			return nil, workspacerepo.ErrMemberExists
+		}
+
+		if !errors.Is(err, workspacerepo.ErrMemberNotFound) {
+			return nil, err
+		}
+
+	case !errors.Is(err, userrepo.ErrUserNotFound):
+		return nil, err
+	}
+
+	inv.InvitedBy = &userID
+	inv.CreatedAt = time.Now()
+
+	return s.Repo.CreateInvitation(ctx, inv)
+}
+
+func (s *Service) RevokeInvitation(ctx context.Context, userID, id, invitationID int) (*entity.Invitation, error) {
+	if _, err := s.authorize(ctx, userID, id, entity.WorkspaceRoleOwner); err != nil {
+		return nil, err
+	}
+
+	inv, err := s.Repo.GetInvitationByID(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if inv.WorkspaceID != id {
+		return nil, workspacerepo.ErrInvitationNotFound
+	}
+
+	return s.Repo.DeleteInvitation(ctx, invitationID)
+}
+
+// GetUserInvitations returns pending invitations addressed to current email of user
+func (s *Service) GetUserInvitations(ctx context.Context, userID int) ([]*entity.Invitation, error) {
+	user, err := s.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Repo.GetInvitationsByEmail(ctx, user.Email)
+}
+
+func (s *Service) AcceptInvitation(ctx context.Context, userID, invitationID int) (*entity.Membership, error) {
+	inv, err := s.getUserInvitation(ctx, userID, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.Repo.AcceptInvitation(ctx, *inv, userID, time.Now())
+}
+
+func (s *Service) DeclineInvitation(ctx context.Context, userID, invitationID int) (*entity.Invitation, error) {
+	if _, err := s.getUserInvitation(ctx, userID, invitationID); err != nil {
+		return nil, err
+	}
+
+	return s.Repo.DeleteInvitation(ctx, invitationID)
+}
+
+// authorize returns workspace if user has at least need role in it
+func (s *Service) authorize(ctx context.Context, userID, id int, need entity.WorkspaceRole) (*entity.Workspace, error) {
+	ws, err := s.GetWorkspaceByID(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ws.Role.Allows(need) {
+		return nil, fmt.Errorf("%w: %v role required", ErrAccessDenied, need)
+	}
+
+	return ws, nil
+}
+
+// getUserInvitation returns invitation addressed to user, invitations of others are reported as not found
+func (s *Service) getUserInvitation(ctx context.Context, userID, invitationID int) (*entity.Invitation, error) {
+	user, err := s.UserRepo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := s.Repo.GetInvitationByID(ctx, invitationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.EqualFold(inv.Email, user.Email) {
+		return nil, workspacerepo.ErrInvitationNotFound
+	}
+
+	return inv, nil
+}
+
+// heir returns owner of workspace who joined first, or member who joined first if member is the only owner
+func (s *Service) heir(ctx context.Context, id, memberID int) (int, error) {
+	members, err := s.Repo.GetMembers(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+
+	heirID := 0
+
+	for _, m := range members {
+		if m.UserID == memberID {
+			continue
+		}
+
+		if m.Role == entity.WorkspaceRoleOwner {
+			return m.UserID, nil
+		}
+
+		if heirID == 0 {
+			heirID = m.UserID
+		}
+	}
+
+	if heirID == 0 {
+		return 0, ErrLastOwner
+	}
+
+	return heirID, nil
+}
+
+func (s *Service) ensureNotLastOwner(ctx context.Context, member *entity.Membership) error {
+	if member.Role != entity.WorkspaceRoleOwner {
+		return nil
+	}
+
+	owners, err := s.Repo.CountMembersWithRole(ctx, member.WorkspaceID, entity.WorkspaceRoleOwner)
+	if err != nil {
+		return err
+	}
+
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+
+	return nil
+}
@@ -0,0 +1,247 @@
+package workspace
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"db-dashboards/internal/domain/entity"
+	"db-dashboards/internal/domain/identity"
+
+	userrepo "db-dashboards/internal/repository/user"
+	workspacerepo "db-dashboards/internal/repository/workspace"
+)
+
+// fakeInvitations holds invitations and records who accepted them
+type fakeInvitations struct {
+	Repo
+
+	invitations map[int]*entity.Invitation
+	accepted    map[int]int
+}
+
+func (f *fakeInvitations) GetInvitationByID(_ context.Context, id int) (*entity.Invitation, error) {
+	inv, ok := f.invitations[id]
+	if !ok {
+		return nil, workspacerepo.ErrInvitationNotFound
+	}
+
+	return inv, nil
+}
+
+func (f *fakeInvitations) AcceptInvitation(_ context.Context, inv entity.Invitation, userID int, _ time.Time) (*entity.Membership, error) {
+	f.accepted[inv.ID] = userID
+
+	return &entity.Membership{WorkspaceID: inv.WorkspaceID, UserID: userID, Role: inv.Role}, nil
+}
+
+type fakeUsers map[int]*entity.User
+
+func (f fakeUsers) GetUserByID(_ context.Context, id int) (*entity.User, error) {
+	user, ok := f[id]
+	if !ok {
+		return nil, userrepo.ErrUserNotFound
+	}
+
+	return user, nil
+}
+
+func (f fakeUsers) GetUserByEmail(_ context.Context, email string) (*entity.User, error) {
+	for _, user := range f {
+		if user.Email == email {
+			return user, nil
+		}
+	}
+
+	return nil, userrepo.ErrUserNotFound
+}
+
+func TestAcceptInvitation(t *testing.T) {
+	const (
+		invitee  = 1
+		stranger = 2
+	)
+
+	tests := []struct {
+		name    string
+		userID  int
+		email   string
+		wantErr error
+	}{
+		{"addressed to user", invitee, "invitee@example.com", nil},
+		{"addressed to user in other case", invitee, "Invitee@Example.COM", nil},
+		{"addressed to other user", stranger, "invitee@example.com", workspacerepo.ErrInvitationNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeInvitations{
+				invitations: map[int]*entity.Invitation{
+					1: {ID: 1, WorkspaceID: 1, Email: tt.email, Role: entity.WorkspaceRoleEditor},
+				},
+				accepted: map[int]int{},
+			}
+
+			s := &Service{
+				Repo: repo,
+				UserRepo: fakeUsers{
+					invitee:  {ID: invitee, Email: "invitee@example.com"},
+					stranger: {ID: stranger, Email: "stranger@example.com"},
+				},
+			}
+
+			_, err := s.AcceptInvitation(context.Background(), tt.userID, 1)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AcceptInvitation() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if _, ok := repo.accepted[1]; ok != (tt.wantErr == nil) {
+				t.Errorf("accepted = %v, want %v", ok, tt.wantErr == nil)
+			}
+		})
+	}
+}
+
+// fakeMembers holds members of single workspace and records heir of removed members
+type fakeMembers struct {
+	Repo
+
+	members []*entity.Membership
+	heirs   map[int]int
+}
+
+func (f *fakeMembers) GetWorkspaceByID(_ context.Context, id, userID int) (*entity.Workspace, error) {
+	ws := &entity.Workspace{ID: id}
+
+	for _, m := range f.members {
+		if m.UserID == userID {
+			ws.Role = m.Role
+		}
+	}
+
+	return ws, nil
+}
+
+func (f *fakeMembers) GetMembers(_ context.Context, _ int) ([]*entity.Membership, error) {
+	return f.members, nil
+}
+
+func (f *fakeMembers) GetMember(_ context.Context, _, userID int) (*entity.Membership, error) {
+	for _, m := range f.members {
+		if m.UserID == userID {
+			return m, nil
+		}
+	}
+
+	return nil, workspacerepo.ErrMemberNotFound
+}
+
+func (f *fakeMembers) CountMembersWithRole(_ context.Context, _ int, role entity.WorkspaceRole) (int, error) {
+	count := 0
+
+	for _, m := range f.members {
+		if m.Role == role {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+func (f *fakeMembers) DeleteMember(_ context.Context, _, userID, heirID int) (*entity.Membership, error) {
+	f.heirs[userID] = heirID
+
+	return &entity.Membership{UserID: userID}, nil
+}
+
+func TestRemoveMember(t *testing.T) {
+	const (
+		founder = 1
+		owner   = 2
+		editor  = 3
+		viewer  = 4
+	)
+
+	tests := []struct {
+		name     string
+		members  []*entity.Membership
+		userID   int
+		memberID int
+		wantHeir int
+		wantErr  error
+	}{
+		{
+			name: "editor removed by owner",
+			members: []*entity.Membership{
+				{UserID: founder, Role: entity.WorkspaceRoleOwner},
+				{UserID: editor, Role: entity.WorkspaceRoleEditor},
+			},
+			userID:   founder,
+			memberID: editor,
+			wantHeir: founder,
+		},
+		{
+			name: "owner who joined first leaves",
+			members: []*entity.Membership{
+				{UserID: founder, Role: entity.WorkspaceRoleOwner},
+				{UserID: editor, Role: entity.WorkspaceRoleEditor},
+				{UserID: owner, Role: entity.WorkspaceRoleOwner},
+			},
+			userID:   founder,
+			memberID: founder,
+			wantHeir: owner,
+		},
+		{
+			name: "viewer leaves",
+			members: []*entity.Membership{
+				{UserID: viewer, Role: entity.WorkspaceRoleViewer},
+				{UserID: editor, Role: entity.WorkspaceRoleEditor},
+				{UserID: owner, Role: entity.WorkspaceRoleOwner},
+			},
+			userID:   viewer,
+			memberID: viewer,
+			wantHeir: owner,
+		},
+		{
+			name: "the only owner leaves",
+			members: []*entity.Membership{
+				{UserID: founder, Role: entity.WorkspaceRoleOwner},
+				{UserID: editor, Role: entity.WorkspaceRoleEditor},
+			},
+			userID:   founder,
+			memberID: founder,
+			wantErr:  ErrLastOwner,
+		},
+		{
+			name: "editor removes other member",
+			members: []*entity.Membership{
+				{UserID: founder, Role: entity.WorkspaceRoleOwner},
+				{UserID: editor, Role: entity.WorkspaceRoleEditor},
+				{UserID: viewer, Role: entity.WorkspaceRoleViewer},
+			},
+			userID:   editor,
+			memberID: viewer,
+			wantErr:  ErrAccessDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeMembers{members: tt.members, heirs: map[int]int{}}
+			s := &Service{Repo: repo}
+
+			ctx := identity.WithIdentity(context.Background(), identity.Identity{UserID: tt.userID, Role: entity.RoleEditor})
+
+			_, err := s.RemoveMember(ctx, tt.userID, 1, tt.memberID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RemoveMember() error = %v, want %v", err, tt.wantErr)
+			}
+
+			heir, removed := repo.heirs[tt.memberID]
+			if removed != (tt.wantErr == nil) || heir != tt.wantHeir {
+				t.Errorf("removed = %v with heir %v, want %v with heir %v", removed, heir, tt.wantErr == nil, tt.wantHeir)
+			}
+		})
+	}
+}
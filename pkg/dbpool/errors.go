@@ -0,0 +1,7 @@
+package dbpool
+
+import "errors"
+
+var (
+	ErrManagerClosed = errors.New("pool manager is closed")
+)
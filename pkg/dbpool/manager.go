@@ -0,0 +1,246 @@
+package dbpool
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type Options struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	IdleTimeout     time.Duration
+	PingTimeout     time.Duration
+}
+
+type pool struct {
+	db       *sqlx.DB
+	key      string // Key of target database pool was opened for
+	lastUsed time.Time
+	refs     int // callers of Get whose contexts are not done yet
+}
+
+// Manager caches one *sqlx.DB per saved connection so that pools are reused across requests. Pools are keyed
+// by connection id rather than dsn, so that evicting pool of one connection never closes pool of another
+type Manager struct {
+	opts Options
+
+	mu     sync.Mutex
+	pools  map[int]*pool
+	closed bool
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func New(opts Options) *Manager {
+	m := &Manager{
+		opts:  opts,
+		pools: make(map[int]*pool),
+		done:  make(chan struct{}),
+	}
+
+	if opts.IdleTimeout > 0 {
+		m.wg.Add(1)
+		go m.evictIdle()
+	}
+
+	return m
+}
+
+// Key returns cache key of target database, dsn is hashed so credentials are never kept in plain form
+func Key(driverName, dsn string) string {
+	sum := sha256.Sum256([]byte(driverName + "\x00" + dsn))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns cached pool of connection id or opens and pings a new one. Pool is in use until ctx is done,
+// so it isn't closed as idle before queries of caller start. Cached pool opened with other dsn is replaced
+func (m *Manager) Get(ctx context.Context, id int, driverName, dsn string) (*sqlx.DB, error) {
+	key := Key(driverName, dsn)
+
+	m.mu.Lock()
+
+	if m.closed {
+		m.mu.Unlock()
+		return nil, ErrManagerClosed
+	}
+
+	if p, ok := m.pools[id]; ok && p.key == key {
+		m.acquire(ctx, p)
+		m.mu.Unlock()
+
+		return p.db, nil
+	}
+
+	m.mu.Unlock()
+
+	// open outside of lock as ping may take a while
+	db, err := m.open(ctx, driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+
+	if m.closed {
+		m.mu.Unlock()
+		db.Close()
+
+		return nil, ErrManagerClosed
+	}
+
+	stale, ok := m.pools[id]
+
+	// another request could open the same pool concurrently
+	if ok && stale.key == key {
+		m.acquire(ctx, stale)
+		m.mu.Unlock()
+
+		db.Close()
+
+		return stale.db, nil
+	}
+
+	p := &pool{db: db, key: key}
+	m.pools[id] = p
+	m.acquire(ctx, p)
+
+	m.mu.Unlock()
+
+	// dsn of connection was changed by request which evicted pool before this one opened it
+	if ok {
+		stale.db.Close()
+	}
+
+	return db, nil
+}
+
+// acquire marks p in use until ctx is done, m.mu must be held
+func (m *Manager) acquire(ctx context.Context, p *pool) {
+	p.refs++
+	p.lastUsed = time.Now()
+
+	context.AfterFunc(ctx, func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+
+		p.refs--
+		p.lastUsed = time.Now()
+	})
+}
+
+// Evict closes and forgets pool of connection id if present
+func (m *Manager) Evict(id int) error {
+	m.mu.Lock()
+	p, ok := m.pools[id]
+	delete(m.pools, id)
+	m.mu.Unlock()
+
+	if !ok {
+		return nil
+	}
+
+	return p.db.Close()
+}
+
+// Close stops idle eviction and closes every cached pool
+func (m *Manager) Close() error {
+	m.mu.Lock()
+
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+
+	m.closed = true
+
+	pools := m.pools
+	m.pools = make(map[int]*pool)
+
+	m.mu.Unlock()
+
+	close(m.done)
+	m.wg.Wait()
+
+	var errs []error
+
+	for _, p := range pools {
+		if err := p.db.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m *Manager) open(ctx context.Context, driverName, dsn string) (*sqlx.DB, error) {
+	db, err := sqlx.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	db.SetMaxOpenConns(m.opts.MaxOpenConns)
+	db.SetMaxIdleConns(m.opts.MaxIdleConns)
+	db.SetConnMaxLifetime(m.opts.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(m.opts.IdleTimeout)
+
+	pingCtx := ctx
+	if m.opts.PingTimeout > 0 {
+		var cancel context.CancelFunc
+
+		pingCtx, cancel = context.WithTimeout(ctx, m.opts.PingTimeout)
+		defer cancel()
+	}
+
+	if err = db.PingContext(pingCtx); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+func (m *Manager) evictIdle() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(m.opts.IdleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+
+		case now := <-ticker.C:
+			var expired []*pool
+
+			m.mu.Lock()
+
+			for id, p := range m.pools {
+				// connections may still be held after context of Get is done
+				if p.refs > 0 || p.db.Stats().InUse > 0 {
+					p.lastUsed = now
+					continue
+				}
+
+				if now.Sub(p.lastUsed) >= m.opts.IdleTimeout {
+					expired = append(expired, p)
+					delete(m.pools, id)
+				}
+			}
+
+			m.mu.Unlock()
+
+			for _, p := range expired {
+				p.db.Close()
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package dbpool
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestEvict(t *testing.T) {
+	m := New(Options{MaxOpenConns: 1})
+	t.Cleanup(func() { m.Close() })
+
+	ctx := context.Background()
+	dsn := filepath.Join(t.TempDir(), "evict.db")
+
+	db, err := m.Get(ctx, 1, "sqlite", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// another connection to the same database keeps its own pool
+	other, err := m.Get(ctx, 2, "sqlite", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = m.Evict(1); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = db.PingContext(ctx); err == nil {
+		t.Error("evicted pool is still open")
+	}
+
+	if err = other.PingContext(ctx); err != nil {
+		t.Errorf("pool of other connection was closed: %v", err)
+	}
+
+	reopened, err := m.Get(ctx, 1, "sqlite", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reopened == db {
+		t.Error("evicted pool is still cached")
+	}
+
+	if err = m.Evict(3); err != nil {
+		t.Errorf("evicting unknown pool: %v", err)
+	}
+}
+
+func TestGetReplacesPoolOfOtherDSN(t *testing.T) {
+	m := New(Options{MaxOpenConns: 1})
+	t.Cleanup(func() { m.Close() })
+
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	db, err := m.Get(ctx, 1, "sqlite", filepath.Join(dir, "old.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replaced, err := m.Get(ctx, 1, "sqlite", filepath.Join(dir, "new.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if replaced == db {
+		t.Fatal("pool of old dsn is still cached")
+	}
+
+	if err = db.PingContext(ctx); err == nil {
+		t.Error("pool of old dsn is still open")
+	}
+}
+
+func TestIdlePoolInUseIsKept(t *testing.T) {
+	const idleTimeout = 50 * time.Millisecond
+
+	m := New(Options{MaxOpenConns: 1, IdleTimeout: idleTimeout})
+	t.Cleanup(func() { m.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	dsn := filepath.Join(t.TempDir(), "idle.db")
+
+	db, err := m.Get(ctx, 1, "sqlite", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// pool is kept before the first query of caller
+	time.Sleep(4 * idleTimeout)
+
+	// connection held like by long running stream
+	conn, err := db.Connx(ctx)
+	if err != nil {
+		t.Fatalf("pool in use was closed: %v", err)
+	}
+
+	cancel()
+
+	time.Sleep(4 * idleTimeout)
+
+	var one int
+
+	if err = conn.QueryRowxContext(context.Background(), "SELECT 1").Scan(&one); err != nil {
+		t.Fatalf("pool with connection in use was closed: %v", err)
+	}
+
+	conn.Close()
+
+	ctx, cancel = context.WithCancel(context.Background())
+
+	if cached, err := m.Get(ctx, 1, "sqlite", dsn); err != nil || cached != db {
+		t.Fatalf("pool in use was evicted: %v", err)
+	}
+
+	cancel()
+
+	time.Sleep(4 * idleTimeout)
+
+	if err = db.PingContext(context.Background()); err == nil {
+		t.Error("idle pool is still open")
+	}
+}
@@ -0,0 +1,7 @@
+package crypto
+
+import "errors"
+
+var (
+	ErrCiphertextTooShort = errors.New("ciphertext too short")
+)
@@ -7,4 +7,7 @@ var (
 	ErrInvalidHeaderProvided = errors.New("invalid header provided")
 
 	ErrNoQueryParamProvided = errors.New("no query param provided")
+
+	ErrNoURLParamProvided      = errors.New("no url param provided")
+	ErrInvalidURLParamProvided = errors.New("invalid url param provided")
 )
@@ -0,0 +1,6 @@
+package handler
+
+const (
+	DefaultOffset = 0
+	DefaultLimit  = 100
+)
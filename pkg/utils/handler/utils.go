@@ -1,12 +1,25 @@
 package handler
 
 import (
+	"encoding/json"
+	"io"
 	"net/http"
 	"strconv"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/sirupsen/logrus"
 )
 
+// DecodeJSONNumbers decodes json body like render.DecodeJSON but keeps numbers of untyped values as json.Number
+func DecodeJSONNumbers(r io.Reader, v any) error {
+	defer io.Copy(io.Discard, r)
+
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	return dec.Decode(v)
+}
+
 func WriteErrResponseAndLog(rw http.ResponseWriter, logger *logrus.Logger, statusCode int, logMsg string, respMsg string) {
 	if logMsg != "" {
 		logger.Errorf(logMsg)
@@ -58,3 +71,17 @@ func GetStringHeaderByKey(req *http.Request, key string) (string, error) {
 
 	return str, nil
 }
+
+func GetIntURLParam(req *http.Request, key string) (int, error) {
+	str := chi.URLParam(req, key)
+	if str == "" {
+		return -1, ErrNoURLParamProvided
+	}
+
+	val, err := strconv.Atoi(str)
+	if err != nil {
+		return -1, ErrInvalidURLParamProvided
+	}
+
+	return val, nil
+}
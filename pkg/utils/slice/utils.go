@@ -86,3 +86,13 @@ func PointerAndValueSlicesEquals[T comparable](p []*T, v []T) bool {
 
 	return true
 }
+
+func Contains[T comparable](s []T, v T) bool {
+	for _, elem := range s {
+		if elem == v {
+			return true
+		}
+	}
+
+	return false
+}